@@ -7,6 +7,7 @@ import "openzeppelin-solidity/contracts/token/ERC20/IERC20.sol";
 
 import "./IFederation.sol";
 import "./IAutonomousSwapProofVerifier.sol";
+import "./ILegacyAutonomousSwapBridge.sol";
 import "./StringUtils.sol";
 
 
@@ -16,7 +17,11 @@ contract AutonomousSwapBridge is Ownable {
     using StringUtils for string;
 
     // The version of the current ASB smart contract.
-    uint public constant VERSION = 1;
+    uint public constant VERSION = 2;
+
+    // The maximum Orbs token amount. Orbs token amounts are uint64, so a value of more Orbs token units could be locked
+    // here but never minted on Orbs.
+    uint256 public constant MAX_ORBS_AMOUNT = 2 ** 64 - 1;
 
     // The network type of the Orbs network this contract is compatible for.
     uint32 public networkType;
@@ -36,6 +41,10 @@ contract AutonomousSwapBridge is Ownable {
     // The ASB proof verifier.
     IAutonomousSwapProofVerifier public verifier;
 
+    // The number of token units in one Orbs token unit, 10^(token decimals - Orbs token decimals). A proven Orbs transfer
+    // carries its amount in Orbs token units and the part of a value below one Orbs token unit as dust.
+    uint256 public decimalsScale;
+
     // Incremental counter for Transaction Unique Identifiers (TUID).
     uint256 public tuidCounter = 0;
 
@@ -45,6 +54,13 @@ contract AutonomousSwapBridge is Ownable {
     // Mapping of spent Orbs TUIDs.
     mapping(uint256 => bool) public spentOrbsTuids;
 
+    // The version 1 ASB this one succeeds (see AutonomousSwapBridgeMigrationVerifier), if any.
+    ILegacyAutonomousSwapBridge public legacyBridge;
+
+    // The last Orbs TUID of the Orbs ASB before it bridged several tokens. Its transfers are proven by receipts without
+    // an ASB address, which this ASB releases unless the legacy bridge already did.
+    uint256 public legacyOrbsTuidMax;
+
     event EthTransferredOut(uint256 indexed tuid, address indexed from, bytes20 indexed to, uint256 value);
     event EthTransferredIn(uint256 indexed tuid, bytes20 indexed from, address indexed to, uint256 value);
 
@@ -70,6 +86,7 @@ contract AutonomousSwapBridge is Ownable {
     function transferOut(bytes20 _to, uint256 _value) public {
         require(verifier.isOrbsAddressValid(_to), "Orbs address is invalid!");
         require(_value > 0, "Value must be greater than 0!");
+        require(_value.div(decimalsScale) <= MAX_ORBS_AMOUNT, "Value must be representable in Orbs token units!");
 
         // Verify that the requested approved enough tokens to transfer out.
         require(token.transferFrom(msg.sender, address(this), _value), "Insufficient allowance!");
@@ -90,12 +107,22 @@ contract AutonomousSwapBridge is Ownable {
 
         require(eventData.to != address(0), "Destination address can't be 0!");
         require(eventData.value > 0, "Value must be greater than 0!");
+        require(eventData.dust < decimalsScale, "Dust must be less than one Orbs token unit!");
 
         // Verify network and protocol parameters.
         require(networkType == eventData.networkType, "Incorrect network type!");
         require(virtualChainId == eventData.virtualChainId, "Incorrect virtual chain ID!");
         require(orbsASBContractName.equal(eventData.orbsContractName), "Incorrect Orbs ASB contract name!");
 
+        // The Orbs ASB bridges several tokens, each through its own ASB, so the transfer must be of this one. A receipt of
+        // the Orbs ASB before the upgrade has no ASB address, it was of the Orbs token the legacy bridge served.
+        if (eventData.asbAddress == address(0)) {
+            require(eventData.tuid <= legacyOrbsTuidMax, "Incorrect ASB address!");
+            require(!legacyBridge.spentOrbsTuids(eventData.tuid), "TUID was already spent by the legacy bridge!");
+        } else {
+            require(eventData.asbAddress == address(this), "Incorrect ASB address!");
+        }
+
         // Make sure that the transaction wasn't already spent and mark it as such;
         require(!spentOrbsTuids[eventData.tuid], "TUID was already spent!");
         spentOrbsTuids[eventData.tuid] = true;
@@ -104,9 +131,36 @@ contract AutonomousSwapBridge is Ownable {
         }
 
         // Transfer the token.
-        require(token.transfer(eventData.to, eventData.value), "Insufficient funds!");
+        uint256 value = eventData.value.mul(decimalsScale).add(eventData.dust);
+        require(token.transfer(eventData.to, value), "Insufficient funds!");
+
+        emit EthTransferredIn(eventData.tuid, eventData.from, eventData.to, value);
+    }
+
+    /// @dev Allows the owner to succeed a version 1 ASB before any token was transferred. The version 1 ASB bridged the
+    /// Orbs token in token units, so the decimals scale must be 1.
+    /// @param _legacyBridge ILegacyAutonomousSwapBridge The version 1 ASB.
+    /// @param _legacyOrbsTuidMax uint256 The outbound TUID of the Orbs ASB when it was upgraded.
+    function setLegacyBridge(ILegacyAutonomousSwapBridge _legacyBridge, uint256 _legacyOrbsTuidMax) public onlyOwner {
+        require(address(_legacyBridge) != address(0), "Legacy bridge must not be 0!");
+        require(address(_legacyBridge.token()) == address(token), "Legacy bridge must be of the same token!");
+        require(decimalsScale == 1, "Legacy bridge requires a decimals scale of 1!");
+        require(tuidCounter == 0 && maxOrbsTuid == 0, "Legacy bridge cannot be set once tokens were transferred!");
+
+        legacyBridge = _legacyBridge;
+        legacyOrbsTuidMax = _legacyOrbsTuidMax;
+
+        // The Orbs ASB spent the TUIDs of the legacy bridge, so the TUIDs continue from there.
+        tuidCounter = _legacyBridge.tuidCounter();
+    }
+
+    /// @dev Allows the owner to set the decimals scale before any token was transferred.
+    /// @param _decimalsScale uint256 The number of token units in one Orbs token unit.
+    function setDecimalsScale(uint256 _decimalsScale) public onlyOwner {
+        require(_decimalsScale > 0, "Decimals scale must be greater than 0!");
+        require(tuidCounter == 0 && maxOrbsTuid == 0, "Decimals scale cannot change once tokens were transferred!");
 
-        emit EthTransferredIn(eventData.tuid, eventData.from, eventData.to, eventData.value);
+        decimalsScale = _decimalsScale;
     }
 
     /// @dev Allows the owner to upgrade its ASB proof verifier.
@@ -134,6 +188,9 @@ contract AutonomousSwapBridge is Ownable {
         virtualChainId = _virtualChainId;
         orbsASBContractName = _orbsASBContractName;
         token = _token;
+        decimalsScale = 1;
+        legacyBridge = ILegacyAutonomousSwapBridge(0);
+        legacyOrbsTuidMax = 0;
         tuidCounter = 0;
         
         // TODO address gas limit by allowing reset by multiple transactions.
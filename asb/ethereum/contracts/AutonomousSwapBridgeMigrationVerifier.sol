@@ -0,0 +1,64 @@
+pragma solidity 0.4.24;
+pragma experimental ABIEncoderV2;
+
+import "./ILegacyAutonomousSwapBridge.sol";
+
+
+/// @title A proof verifier that retires a version 1 ASB smart contract and moves its locked tokens to its successor.
+/// The version 1 ASB owner upgrades its verifier to this one, after which:
+/// 1. Any transferIn of the version 1 ASB, with any proof, transfers its whole balance to the successor ASB, once. The
+///    transfer is marked with MIGRATION_TUID, which no Orbs transfer reaches.
+/// 2. No transferOut of the version 1 ASB is possible, since no Orbs address is valid.
+/// The full upgrade of the Orbs token bridge is:
+/// 1. Pause both directions of the Orbs ASB and wait until every transfer locked on the version 1 ASB was minted on Orbs.
+/// 2. Deploy the successor ASB and call its setLegacyBridge with the version 1 ASB and the Orbs ASB outbound TUID. The
+///    successor continues the TUIDs of the version 1 ASB and releases the Orbs transfers it didn't release.
+/// 3. Deploy this verifier, set it as the version 1 ASB verifier and call the version 1 ASB transferIn.
+/// 4. Migrate the Orbs ASB state layout, bind it to the successor ASB and resume transfers.
+contract AutonomousSwapBridgeMigrationVerifier {
+    // The version 1 TransferInEvent layout.
+    struct TransferInEvent {
+        uint32 networkType;
+        uint64 virtualChainId;
+        string orbsContractName;
+        bytes20 from;
+        address to;
+        uint256 value;
+        uint256 tuid;
+    }
+
+    // The TUID of the migration transfer.
+    uint256 public constant MIGRATION_TUID = 2 ** 256 - 1;
+
+    // The retired version 1 ASB.
+    ILegacyAutonomousSwapBridge public legacyBridge;
+
+    // The ASB the locked tokens are moved to.
+    address public successorBridge;
+
+    /// @dev Constructor that initializes the migration verifier.
+    /// @param _legacyBridge ILegacyAutonomousSwapBridge The retired version 1 ASB.
+    /// @param _successorBridge address The ASB the locked tokens are moved to.
+    constructor(ILegacyAutonomousSwapBridge _legacyBridge, address _successorBridge) public {
+        require(address(_legacyBridge) != address(0), "Legacy bridge must not be 0!");
+        require(_successorBridge != address(0), "Successor bridge must not be 0!");
+
+        legacyBridge = _legacyBridge;
+        successorBridge = _successorBridge;
+    }
+
+    /// @dev Returns the transfer of the whole balance of the version 1 ASB to its successor, regardless of the proof.
+    function processPackedProof(bytes, bytes) public view returns(TransferInEvent memory transferInEvent) {
+        transferInEvent.networkType = legacyBridge.networkType();
+        transferInEvent.virtualChainId = legacyBridge.virtualChainId();
+        transferInEvent.orbsContractName = legacyBridge.orbsASBContractName();
+        transferInEvent.to = successorBridge;
+        transferInEvent.value = legacyBridge.token().balanceOf(address(legacyBridge));
+        transferInEvent.tuid = MIGRATION_TUID;
+    }
+
+    /// @dev No Orbs address is valid, so the version 1 ASB locks no more tokens.
+    function isOrbsAddressValid(bytes20) public pure returns (bool) {
+        return false;
+    }
+}
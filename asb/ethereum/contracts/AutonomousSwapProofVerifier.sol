@@ -89,6 +89,8 @@ contract AutonomousSwapProofVerifier is IAutonomousSwapProofVerifier {
         bytes20 from;
         address to;
         uint256 value;
+        address asbAddress;
+        uint256 dust;
     }
 
     // The federation smart contract.
@@ -183,6 +185,8 @@ contract AutonomousSwapProofVerifier is IAutonomousSwapProofVerifier {
         transferInEvent.to = eventData.to;
         transferInEvent.value = eventData.value;
         transferInEvent.tuid = eventData.tuid;
+        transferInEvent.asbAddress = eventData.asbAddress;
+        transferInEvent.dust = eventData.dust;
     }
 
     /// @dev Parses and validates the raw transfer proof. Please note that this method can't be external (yet), since
@@ -228,6 +232,8 @@ contract AutonomousSwapProofVerifier is IAutonomousSwapProofVerifier {
         transferInEvent.to = eventData.to;
         transferInEvent.value = eventData.value;
         transferInEvent.tuid = eventData.tuid;
+        transferInEvent.asbAddress = eventData.asbAddress;
+        transferInEvent.dust = eventData.dust;
     }
 
     /// @dev Checks Orbs address for correctness.
@@ -421,11 +427,33 @@ contract AutonomousSwapProofVerifier is IAutonomousSwapProofVerifier {
         res.to = _eventData.toAddress(offset);
         offset = offset.add(ADDRESS_SIZE);
 
-        /// argument[3] UINT256 ammount (bytes)
+        /// argument[3] uint64 amount, in Orbs token units
         offset = offset.add(LENGTH_SIZE);
         offset = ParseUint16(offset, DWORD_ALIGNED); //oneof field
         res.value = _eventData.toUint64BE(offset);
-        offset = offset.add(UINT64_SIZE); // TODO issue #18
+        offset = offset.add(UINT64_SIZE);
+
+        /// argument[4] bytes[20] eth_asb_address (bytes)
+        /// An event of the Orbs ASB before the upgrade has none and is left with a 0x0 ASB address, only the ASB that
+        /// succeeds the legacy bridge accepts it.
+        if (offset < _eventData.length) {
+            offset = offset.add(LENGTH_SIZE);
+            offset = ParseUint16(offset, DWORD_ALIGNED); //oneof field
+            uint32 asbAddressSize =_eventData.toUint32BE(offset);
+            require(asbAddressSize == ADDRESS_SIZE, "Invalid ASB address size!");
+            offset = offset.add(LENGTH_SIZE);
+            res.asbAddress = _eventData.toAddress(offset);
+            offset = offset.add(ADDRESS_SIZE);
+        }
+
+        /// argument[5] uint64 eth_dust, in Ethereum token units
+        /// The value below one Orbs token unit refunded with the transfer, an event without it has no dust.
+        if (offset < _eventData.length) {
+            offset = offset.add(LENGTH_SIZE);
+            offset = ParseUint16(offset, DWORD_ALIGNED); //oneof field
+            res.dust = _eventData.toUint64BE(offset);
+            offset = offset.add(UINT64_SIZE);
+        }
     }
 
     /// @dev Verifies federation members signatures on the blockref message.
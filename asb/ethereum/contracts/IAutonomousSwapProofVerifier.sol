@@ -15,6 +15,8 @@ contract IAutonomousSwapProofVerifier {
         address to;
         uint256 value;
         uint256 tuid;
+        address asbAddress;
+        uint256 dust;
     }
 
     /// @dev Parses and validates the raw transfer proof. Please note that this method can't be external (yet), since
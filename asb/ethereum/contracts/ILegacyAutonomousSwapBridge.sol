@@ -0,0 +1,26 @@
+pragma solidity 0.4.24;
+
+import "openzeppelin-solidity/contracts/token/ERC20/IERC20.sol";
+
+
+/// @title The getters of the version 1 ASB smart contract, which bridged the Orbs token only.
+interface ILegacyAutonomousSwapBridge {
+    /// @dev Returns the network type of the Orbs network the ASB is compatible for.
+    function networkType() external view returns (uint32);
+
+    /// @dev Returns the virtual chain ID of the underlying token on the Orbs network.
+    function virtualChainId() external view returns (uint64);
+
+    /// @dev Returns the name of the Orbs ASB smart contract.
+    function orbsASBContractName() external view returns (string);
+
+    /// @dev Returns the swappable ERC20 token.
+    function token() external view returns (IERC20);
+
+    /// @dev Returns the TUID of the last transfer to Orbs.
+    function tuidCounter() external view returns (uint256);
+
+    /// @dev Returns whether an Orbs TUID was spent.
+    /// @param _tuid uint256 The Orbs TUID to check.
+    function spentOrbsTuids(uint256 _tuid) external view returns (bool);
+}
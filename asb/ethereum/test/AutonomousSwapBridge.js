@@ -15,6 +15,8 @@ const { expect } = chai;
 
 const AutonomousSwapBridgeWrapper = artifacts.require('../test/AutonomousSwapBridgeWrapper.sol');
 const AutonomousSwapBridge = artifacts.require('./AutonomousSwapBridge.sol');
+const AutonomousSwapBridgeMigrationVerifierWrapper = artifacts.require('../test/AutonomousSwapBridgeMigrationVerifierWrapper.sol');
+const LegacyAutonomousSwapBridgeMock = artifacts.require('../test/LegacyAutonomousSwapBridgeMock.sol');
 const AutonomousSwapProofVerifier = artifacts.require('./AutonomousSwapProofVerifier.sol');
 const Federation = artifacts.require('./Federation.sol');
 const TokenMock = artifacts.require('./OrbsTokenMock.sol');
@@ -31,7 +33,7 @@ contract('AutonomousSwapBridge', (accounts) => {
   const ORBS_TRANSFERRED_IN_EVENT_NAME = 'OrbsTransferredIn';
   const ETH_TRANSFERRED_OUT_EVENT_NAME = 'EthTransferredOut';
   const ETH_TRANSFERRED_IN_EVENT_NAME = 'EthTransferredIn';
-  const VERSION = 1;
+  const VERSION = 2;
   const EMPTY = '';
   const ZERO_ADDRESS = '0x0000000000000000000000000000000000000000';
 
@@ -313,6 +315,66 @@ contract('AutonomousSwapBridge', (accounts) => {
 
       await expectRevert(asb.transferOut(orbsUser1Address, 0, { from: user1 }));
     });
+
+    it('should not allow to transfer out more than the maximum Orbs amount', async () => {
+      const maxValue = await asb.MAX_ORBS_AMOUNT.call();
+      const value = maxValue.plus(1);
+      await token.assign(user1, value);
+      await token.approve(asb.address, value, { from: user1 });
+
+      await expectRevert(asb.transferOut(orbsUser1Address, value, { from: user1 }));
+
+      await token.approve(asb.address, maxValue, { from: user1 });
+      await asb.transferOut(orbsUser1Address, maxValue, { from: user1 });
+      expect(await token.balanceOf.call(asb.address)).to.be.bignumber.equal(maxValue);
+    });
+
+    it('should scale the maximum Orbs amount by the decimals scale', async () => {
+      const decimalsScale = 10000000000;
+      await asb.setDecimalsScale(decimalsScale, { from: owner });
+      const maxValue = (await asb.MAX_ORBS_AMOUNT.call()).plus(1).times(decimalsScale).minus(1);
+      await token.assign(user1, maxValue.plus(1));
+      await token.approve(asb.address, maxValue.plus(1), { from: user1 });
+
+      await expectRevert(asb.transferOut(orbsUser1Address, maxValue.plus(1), { from: user1 }));
+
+      await asb.transferOut(orbsUser1Address, maxValue, { from: user1 });
+      expect(await token.balanceOf.call(asb.address)).to.be.bignumber.equal(maxValue);
+    });
+  });
+
+  describe('decimals scale', async () => {
+    let asb;
+
+    beforeEach(async () => {
+      const federationMembers = accounts.slice(7, 10);
+      const federation = await Federation.new(federationMembers, { from: owner });
+      const verifier = await AutonomousSwapProofVerifier.new(federation.address);
+      asb = await AutonomousSwapBridgeWrapper.new(NETWORK_TYPE, VIRTUAL_CHAIN_ID, ORBS_ASB_CONTRACT_NAME, token.address,
+        federation.address, verifier.address, { from: owner });
+    });
+
+    it('should default to 1', async () => {
+      expect(await asb.decimalsScale.call()).to.be.bignumber.equal(1);
+    });
+
+    it('should allow the owner to set it', async () => {
+      await asb.setDecimalsScale(100, { from: owner });
+      expect(await asb.decimalsScale.call()).to.be.bignumber.equal(100);
+    });
+
+    it('should not allow not the owner to set it', async () => {
+      await expectRevert(asb.setDecimalsScale(100, { from: notOwner }));
+    });
+
+    it('should not allow to set it to 0', async () => {
+      await expectRevert(asb.setDecimalsScale(0, { from: owner }));
+    });
+
+    it('should not allow to set it once tokens were transferred', async () => {
+      await asb.injectTransferIn(1, ORBS_ADDRESS, ZERO_ADDRESS, 1000);
+      await expectRevert(asb.setDecimalsScale(100, { from: owner }));
+    });
   });
 
   describe('transfer tokens from Orbs', async () => {
@@ -347,6 +409,7 @@ contract('AutonomousSwapBridge', (accounts) => {
         .setOrbsAddress(ORBS_ADDRESS)
         .setEthereumAddress(receiver)
         .setValue(value)
+        .setAsbAddress(asb.address)
         .setTransactionExecutionResult(1)
         .setTransactionReceipts(['transaction1', 'transaction2', 5, 4, 3])
         .setProtocolVersion(PROTOCOL_VERSION)
@@ -388,6 +451,30 @@ contract('AutonomousSwapBridge', (accounts) => {
       });
     });
 
+    context('scaled by decimals', async () => {
+      const decimalsScale = 100;
+      const dust = 7;
+      const scaledValue = (value * decimalsScale) + dust;
+
+      beforeEach(async () => {
+        await asb.setDecimalsScale(decimalsScale, { from: owner });
+        proof.setDust(dust);
+      });
+
+      it('should transfer the amount in token units with the dust', async () => {
+        const tx = await transferIn(proof);
+        const event = tx.logs[0];
+        expect(event.event).to.eql(ETH_TRANSFERRED_IN_EVENT_NAME);
+        expect(event.args.value).to.be.bignumber.equal(scaledValue);
+        expect(await token.balanceOf.call(receiver)).to.be.bignumber.equal(scaledValue);
+      });
+
+      it('should not allow dust of a whole Orbs token unit', async () => {
+        proof.setDust(decimalsScale);
+        await expectRevert(transferIn(proof));
+      });
+    });
+
     context('invalid', async () => {
       afterEach(async () => {
         await expectRevert(transferIn(proof));
@@ -426,11 +513,109 @@ contract('AutonomousSwapBridge', (accounts) => {
         });
       });
 
+      context('incorrect ASB address', async () => {
+        it('should revert', async () => {
+          proof.setAsbAddress(token.address);
+        });
+      });
+
       context('requesting too many tokens', async () => {
         it('should revert', async () => {
           proof.setValue(initialASBBalance + 1);
         });
       });
     });
+
+    context('legacy receipt', async () => {
+      const legacyTuidCounter = 30;
+      const legacyOrbsTuidMax = 20;
+
+      let legacyBridge;
+
+      beforeEach(async () => {
+        legacyBridge = await LegacyAutonomousSwapBridgeMock.new(NETWORK_TYPE, VIRTUAL_CHAIN_ID, ORBS_ASB_CONTRACT_NAME,
+          token.address, legacyTuidCounter);
+        await asb.setLegacyBridge(legacyBridge.address, legacyOrbsTuidMax, { from: owner });
+        proof.setAsbAddress(undefined);
+      });
+
+      it('should continue the TUIDs of the legacy bridge', async () => {
+        expect(await asb.legacyBridge.call()).to.eql(legacyBridge.address);
+        expect(await asb.legacyOrbsTuidMax.call()).to.be.bignumber.equal(legacyOrbsTuidMax);
+        expect(await asb.tuidCounter.call()).to.be.bignumber.equal(legacyTuidCounter);
+      });
+
+      it('should transfer tokens of a TUID the legacy bridge did not spend', async () => {
+        const tx = await transferIn(proof);
+        const event = tx.logs[0];
+        expect(event.event).to.eql(ETH_TRANSFERRED_IN_EVENT_NAME);
+        expect(event.args.value).to.be.bignumber.equal(value);
+        expect(event.args.tuid).to.be.bignumber.equal(tuid);
+        expect(await token.balanceOf.call(receiver)).to.be.bignumber.equal(value);
+      });
+
+      it('should not transfer tokens of a TUID the legacy bridge spent', async () => {
+        await legacyBridge.injectTransferIn(tuid);
+        await expectRevert(transferIn(proof));
+      });
+
+      it('should not transfer tokens of a TUID after the upgrade', async () => {
+        proof.setTuid(legacyOrbsTuidMax + 1);
+        await expectRevert(transferIn(proof));
+      });
+
+      it('should not allow to set the legacy bridge again', async () => {
+        await expectRevert(asb.setLegacyBridge(legacyBridge.address, legacyOrbsTuidMax, { from: owner }));
+      });
+    });
+
+    context('without a legacy bridge', async () => {
+      it('should not transfer tokens of a legacy receipt', async () => {
+        proof.setAsbAddress(undefined);
+        await expectRevert(transferIn(proof));
+      });
+
+      it('should not allow not the owner to set the legacy bridge', async () => {
+        const legacyBridge = await LegacyAutonomousSwapBridgeMock.new(NETWORK_TYPE, VIRTUAL_CHAIN_ID,
+          ORBS_ASB_CONTRACT_NAME, token.address, 1);
+        await expectRevert(asb.setLegacyBridge(legacyBridge.address, 1, { from: notOwner }));
+      });
+
+      it('should not allow a legacy bridge of another token', async () => {
+        const otherToken = await TokenMock.new();
+        const legacyBridge = await LegacyAutonomousSwapBridgeMock.new(NETWORK_TYPE, VIRTUAL_CHAIN_ID,
+          ORBS_ASB_CONTRACT_NAME, otherToken.address, 1);
+        await expectRevert(asb.setLegacyBridge(legacyBridge.address, 1, { from: owner }));
+      });
+    });
+  });
+
+  describe('migration from a legacy bridge', async () => {
+    const lockedValue = 5000;
+    const successor = accounts[6];
+
+    let legacyBridge;
+    let migrationVerifier;
+
+    beforeEach(async () => {
+      legacyBridge = await LegacyAutonomousSwapBridgeMock.new(NETWORK_TYPE, VIRTUAL_CHAIN_ID, ORBS_ASB_CONTRACT_NAME,
+        token.address, 10);
+      await token.assign(legacyBridge.address, lockedValue);
+      migrationVerifier = await AutonomousSwapBridgeMigrationVerifierWrapper.new(legacyBridge.address, successor);
+    });
+
+    it('should transfer the whole legacy bridge balance to the successor', async () => {
+      const transferInEvent = await migrationVerifier.processPackedProofRaw.call('0x', '0x');
+      expect(transferInEvent[0]).to.be.bignumber.equal(NETWORK_TYPE);
+      expect(transferInEvent[1]).to.be.bignumber.equal(VIRTUAL_CHAIN_ID);
+      expect(transferInEvent[2]).to.eql(ORBS_ASB_CONTRACT_NAME);
+      expect(transferInEvent[3]).to.eql(successor);
+      expect(transferInEvent[4]).to.be.bignumber.equal(lockedValue);
+      expect(transferInEvent[5]).to.be.bignumber.equal(await migrationVerifier.MIGRATION_TUID.call());
+    });
+
+    it('should not accept any Orbs address', async () => {
+      expect(await migrationVerifier.isOrbsAddressValid.call(ORBS_ADDRESS)).to.be.false;
+    });
   });
 });
@@ -0,0 +1,24 @@
+pragma solidity 0.4.24;
+pragma experimental ABIEncoderV2;
+
+import "../contracts/AutonomousSwapBridgeMigrationVerifier.sol";
+
+
+/// @title A wrapper around AutonomousSwapBridgeMigrationVerifier which implements non-Struct returning versions of some
+/// methods for testing.
+contract AutonomousSwapBridgeMigrationVerifierWrapper is AutonomousSwapBridgeMigrationVerifier {
+    constructor(ILegacyAutonomousSwapBridge _legacyBridge, address _successorBridge) public
+        AutonomousSwapBridgeMigrationVerifier(_legacyBridge, _successorBridge) {
+    }
+
+    function processPackedProofRaw(bytes _packedProof, bytes _transactionReceipt) public view returns(uint32 networkType,
+        uint64 virtualChainId, string orbsContractName, address to, uint256 value, uint256 tuid) {
+        TransferInEvent memory eventData = processPackedProof(_packedProof, _transactionReceipt);
+        networkType = eventData.networkType;
+        virtualChainId = eventData.virtualChainId;
+        orbsContractName = eventData.orbsContractName;
+        to = eventData.to;
+        value = eventData.value;
+        tuid = eventData.tuid;
+    }
+}
@@ -266,6 +266,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
           orbsAddress: Buffer.from('ef0ee8a2ba59624e227f6ac0a85e6aa5e75df86a', 'hex'),
           ethereumAddress: accounts[8],
           value: 1500,
+          asbAddress: accounts[9],
         };
 
         const data = {
@@ -290,6 +291,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
           orbsAddress: Buffer.from('ef0ee8a2ba59624e227f6ac0a85e6aa5e75df86a', 'hex'),
           ethereumAddress: accounts[3],
           value: 1500,
+          asbAddress: accounts[4],
         };
 
         const event = ASBProof.buildEventData(data);
@@ -302,6 +304,29 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
         expect(eventData[3]).to.eql(utils.bufferToHex(data.orbsAddress));
         expect(eventData[4]).to.eql(data.ethereumAddress);
         expect(eventData[5]).to.be.bignumber.equal(data.value);
+        expect(eventData[6]).to.eql(data.asbAddress);
+        expect(eventData[7]).to.be.bignumber.equal(0);
+      });
+
+      it('should properly parse the dust', async () => {
+        const data = {
+          orbsContractName: 'Hello World!',
+          eventName: ORBS_TRANSFERED_OUT_EVENT_NAME,
+          tuid: 56789,
+          orbsAddress: Buffer.from('ef0ee8a2ba59624e227f6ac0a85e6aa5e75df86a', 'hex'),
+          ethereumAddress: accounts[3],
+          value: 1500,
+          asbAddress: accounts[4],
+          dust: 7,
+        };
+
+        const event = ASBProof.buildEventData(data);
+        const rawEventData = utils.bufferToHex(event);
+        const eventData = await verifier.parseEventDataRaw.call(rawEventData);
+
+        expect(eventData[5]).to.be.bignumber.equal(data.value);
+        expect(eventData[6]).to.eql(data.asbAddress);
+        expect(eventData[7]).to.be.bignumber.equal(data.dust);
       });
     });
   });
@@ -340,6 +365,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
         ethereumAddress: proofData[4],
         value: proofData[5],
         tuid: proofData[6],
+        asbAddress: proofData[7],
       };
     };
 
@@ -381,6 +407,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
         .setOrbsAddress(ORBS_ADDRESS)
         .setEthereumAddress(accounts[5])
         .setValue(100000)
+        .setAsbAddress(accounts[6])
         .setTransactionExecutionResult(1)
         .setTransactionReceipts(['transaction1', 'transaction2', 5, 4, 3])
         .setProtocolVersion(PROTOCOL_VERSION)
@@ -400,6 +427,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
         expect(proofData.ethereumAddress).to.eql(proof.ethereumAddress);
         expect(proofData.value).to.be.bignumber.equal(proof.value);
         expect(proofData.tuid).to.be.bignumber.equal(proof.tuid);
+        expect(proofData.asbAddress).to.eql(proof.asbAddress);
       });
 
       it('should process correctly historic events', async () => {
@@ -419,6 +447,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
             .setOrbsAddress(ORBS_ADDRESS)
             .setEthereumAddress(accounts[5])
             .setValue(100000)
+            .setAsbAddress(accounts[6])
             .setTransactionExecutionResult(1)
             .setTransactionReceipts(['transaction1', 'transaction2', 5, 4, 3])
             .setProtocolVersion(PROTOCOL_VERSION)
@@ -444,6 +473,7 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
           expect(proofData.ethereumAddress).to.eql(currentProof.ethereumAddress);
           expect(proofData.value).to.be.bignumber.equal(currentProof.value);
           expect(proofData.tuid).to.be.bignumber.equal(currentProof.tuid);
+          expect(proofData.asbAddress).to.eql(currentProof.asbAddress);
         }
       });
 
@@ -505,6 +535,20 @@ contract('AutonomousSwapProofVerifier', (accounts) => {
         });
       });
 
+      context('ASB address', async () => {
+        context('is too long', async () => {
+          it('should revert', async () => {
+            proof.setAsbAddress(`${accounts[1]}1234`);
+          });
+        });
+
+        context('is too short', async () => {
+          it('should revert', async () => {
+            proof.setAsbAddress(accounts[1].slice(0, -4));
+          });
+        });
+      });
+
       context('event name is not TransferedOut', async () => {
         context('is incorrect', async () => {
           it('should revert', async () => {
@@ -12,7 +12,7 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
 
     function processParsedProofRaw(bytes _resultsBlockHeader, bytes _resultsBlockProof, bytes _transactionReceipt,
         bytes32[] _transactionReceiptProof) public view returns(uint32 networkType, uint64 virtualChainId,
-        string orbsContractName, bytes20 from, address to, uint256 value, uint256 tuid) {
+        string orbsContractName, bytes20 from, address to, uint256 value, uint256 tuid, address asbAddress) {
         TransferInEvent memory eventData = processProof(_resultsBlockHeader, _resultsBlockProof, _transactionReceipt,
             _transactionReceiptProof);
         networkType = eventData.networkType;
@@ -22,6 +22,7 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
         to = eventData.to;
         value = eventData.value;
         tuid = eventData.tuid;
+        asbAddress = eventData.asbAddress;
     }
 
     function parseResultsBlockHeaderRaw(bytes _resultsBlockHeader) public pure returns (uint32 protocolVersion,
@@ -56,7 +57,7 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
     }
 
     function parseEventDataRaw(bytes _eventData) public pure returns (string orbsContractName, string eventName,
-        uint64 tuid, bytes20 from, address to, uint256 value) {
+        uint64 tuid, bytes20 from, address to, uint256 value, address asbAddress, uint256 dust) {
         EventData memory eventData = parseEventData(_eventData);
         orbsContractName = eventData.orbsContractName;
         eventName = eventData.eventName;
@@ -64,6 +65,8 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
         from = eventData.from;
         to = eventData.to;
         value = eventData.value;
+        asbAddress = eventData.asbAddress;
+        dust = eventData.dust;
     }
 
     function parsePackedProofRaw(bytes _packedProof) public pure returns(bytes resultsBlockHeader, 
@@ -72,7 +75,7 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
     }
 
     function processPackedProofRaw(bytes _packedProof, bytes _transactionReceipt) public view returns(uint32 networkType, uint64 virtualChainId,
-        string orbsContractName, bytes20 from, address to, uint256 value, uint256 tuid) {
+        string orbsContractName, bytes20 from, address to, uint256 value, uint256 tuid, address asbAddress) {
         TransferInEvent memory eventData = processPackedProof(_packedProof, _transactionReceipt);
         networkType = eventData.networkType;
         virtualChainId = eventData.virtualChainId;
@@ -81,5 +84,6 @@ contract AutonomousSwapProofVerifierWrapper is AutonomousSwapProofVerifier {
         to = eventData.to;
         value = eventData.value;
         tuid = eventData.tuid;
+        asbAddress = eventData.asbAddress;
     }
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+pragma solidity 0.4.24;
+
+import "openzeppelin-solidity/contracts/token/ERC20/IERC20.sol";
+
+
+/// @title The state of a version 1 ASB smart contract for testing.
+contract LegacyAutonomousSwapBridgeMock {
+    uint32 public networkType;
+    uint64 public virtualChainId;
+    string public orbsASBContractName;
+    IERC20 public token;
+    uint256 public tuidCounter;
+    mapping(uint256 => bool) public spentOrbsTuids;
+
+    constructor(uint32 _networkType, uint64 _virtualChainId, string _orbsASBContractName, IERC20 _token,
+        uint256 _tuidCounter) public {
+        networkType = _networkType;
+        virtualChainId = _virtualChainId;
+        orbsASBContractName = _orbsASBContractName;
+        token = _token;
+        tuidCounter = _tuidCounter;
+    }
+
+    function injectTransferIn(uint256 _tuid) public {
+        spentOrbsTuids[_tuid] = true;
+    }
+}
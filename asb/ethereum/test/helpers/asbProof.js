@@ -15,6 +15,7 @@ const UINT16_SIZE = 2;
 const UINT32_SIZE = 4;
 const UINT64_SIZE = 8;
 const UINT256_SIZE = 32;
+const ADDRESS_SIZE = 20;
 const SHA256_SIZE = 32;
 
 const DUMMY_BLOCK_HASH = utils.sha256('Dummy Block Hash');
@@ -33,6 +34,8 @@ class ASBProof {
       orbsAddress: this.orbsAddress,
       ethereumAddress: this.ethereumAddress,
       value: this.value,
+      asbAddress: this.asbAddress,
+      dust: this.dust,
     }, this.eventOptions);
 
     // Create the transaction receipt merkle proof.
@@ -151,6 +154,16 @@ class ASBProof {
     return this;
   }
 
+  setAsbAddress(asbAddress) {
+    this.asbAddress = asbAddress;
+    return this;
+  }
+
+  setDust(dust) {
+    this.dust = dust;
+    return this;
+  }
+
   setTransactionExecutionResult(executionResult) {
     this.executionResult = executionResult;
     return this;
@@ -400,10 +413,28 @@ class ASBProof {
   // | orbs_address             | N+44   | 20   | bytes (20B) |                               |
   // | tokens length            | N+64   | 4    | always 32   | reserved                      |
   // | tokens                   | N+68   | 32   | uint256     |                               |
+  // | asb_address length       | N+100  | 4    | always 20   | optional, not before upgrade  |
+  // | asb_address              | N+104  | 20   | bytes (20B) | optional, not before upgrade  |
+  // | eth_dust                 | N+124  | 8    | uint64      | optional                      |
   // +--------------------------+--------+------+-------------+-------------------------------+
   static buildEventData(event, options = {}) {
     const ethereumAddressBuffer = Bytes.prefixedHexToBuffer(event.ethereumAddress);
     const arguments_name = "testing";
+    const asbAddress = event.asbAddress ? [
+      Bytes.numberToBuffer(100, UINT32_SIZE), //TODO set actual size
+      Bytes.numberToBuffer(arguments_name.length, UINT32_SIZE), // name size
+      Bytes.padToWord(Buffer.from(arguments_name)),
+      Bytes.padToDword(Bytes.numberToBuffer(7, UINT16_SIZE)), // type
+      Bytes.numberToBuffer(ADDRESS_SIZE, UINT32_SIZE),
+      Bytes.prefixedHexToBuffer(event.asbAddress),
+    ] : [];
+    const dust = event.asbAddress && Number.isInteger(event.dust) ? [
+      Bytes.numberToBuffer(100, UINT32_SIZE), //TODO set actual size
+      Bytes.numberToBuffer(arguments_name.length, UINT32_SIZE), // name size
+      Bytes.padToWord(Buffer.from(arguments_name)),
+      Bytes.padToDword(Bytes.numberToBuffer(7, UINT16_SIZE)), // type
+      Bytes.numberToBuffer(event.dust, UINT64_SIZE),
+    ] : [];
     return Buffer.concat([
       Bytes.numberToBuffer(event.orbsContractName.length, UINT32_SIZE),
       Bytes.padToDword(Buffer.from(event.orbsContractName)),
@@ -437,6 +468,9 @@ class ASBProof {
       Bytes.padToDword(Bytes.numberToBuffer(7, UINT16_SIZE)), // type
       //Bytes.numberToBuffer(options.wrongValueSize || UINT256_SIZE, UINT32_SIZE),
       Bytes.numberToBuffer(event.value, UINT64_SIZE),
+
+      ...asbAddress,
+      ...dust,
     ]);
   }
 }
@@ -15,6 +15,13 @@ type OrbsAdapter interface {
 	TransferOut(orbsErc20ContractName string, orbsAsbContractName string, userAccountOnOrbs string, userAccountOnEthereum string, userTransferAmount int) (orbsTxId string, userBalanceOnOrbsAfter int)
 	GetBalance(orbsErc20ContractName string, userAccountOnOrbs string) (userBalanceOnOrbs int)
 	GenerateReceiptProof(orbsTxId string) (packedOrbsReceiptProof string, packedOrbsReceipt string)
+	GetTotalSupply(orbsErc20ContractName string) (totalSupplyOnOrbs int)
+	GetInTuidMax(orbsAsbContractName string) (inTuidMax uint64)
+	GetOutTuid(orbsAsbContractName string) (outTuid uint64)
+	GetTransferIn(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int)
+	GetTransferOut(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int, ethereumDust int)
+	GetDecimals(orbsAsbContractName string) (ethereumDecimals int, orbsDecimals int)
+	GetTotalDust(orbsAsbContractName string) (totalDustOnOrbs int)
 }
 
 type EthereumAdapter interface {
@@ -26,4 +33,7 @@ type EthereumAdapter interface {
 	TransferIn(ethereumErc20Address string, userAccountOnEthereum string, packedOrbsReceiptProof string, packedOrbsReceipt string) (ethereumTxHash string, userBalanceOnEthereumAfter int)
 	GetBalance(ethereumErc20Address string, userAccountOnEthereum string) (userBalanceOnEthereum int)
 	WaitForFinality()
+	GetASBTuids() (tuidCounter uint64, maxOrbsTuid uint64)
+	IsOrbsTuidSpent(orbsTuid uint64) (spent bool)
+	GetTransferOutValue(tuid uint64) (value int)
 }
@@ -95,6 +95,54 @@ func (gc *gammaCliAdapter) GenerateReceiptProof(orbsTxId string) (packedOrbsRece
 	return out.PackedProof, out.PackedReceipt
 }
 
+func (gc *gammaCliAdapter) GetTotalSupply(orbsErc20ContractName string) (totalSupplyOnOrbs int) {
+	out := gc.runQuery("./gammacli-jsons/get-total-supply.json -name " + orbsErc20ContractName)
+	n, _ := strconv.ParseUint(out[0], 10, 32)
+	return int(n)
+}
+
+func (gc *gammaCliAdapter) GetInTuidMax(orbsAsbContractName string) (inTuidMax uint64) {
+	out := gc.runQuery("./gammacli-jsons/asb-get-in-tuid-max.json -name " + orbsAsbContractName)
+	n, _ := strconv.ParseUint(out[0], 10, 64)
+	return n
+}
+
+func (gc *gammaCliAdapter) GetOutTuid(orbsAsbContractName string) (outTuid uint64) {
+	out := gc.runQuery("./gammacli-jsons/asb-get-out-tuid.json -name " + orbsAsbContractName)
+	n, _ := strconv.ParseUint(out[0], 10, 64)
+	return n
+}
+
+// a transfer record is found by its orbs block height, which is never zero
+func (gc *gammaCliAdapter) GetTransferIn(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int) {
+	out := gc.runQuery(fmt.Sprintf("./gammacli-jsons/asb-get-transfer-in.json -name %s -arg2 %d", orbsAsbContractName, tuid))
+	amount, _ := strconv.ParseUint(out[4], 10, 32)
+	blockHeight, _ := strconv.ParseUint(out[5], 10, 64)
+	return blockHeight != 0, int(amount)
+}
+
+func (gc *gammaCliAdapter) GetTransferOut(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int, ethereumDust int) {
+	out := gc.runQuery(fmt.Sprintf("./gammacli-jsons/asb-get-transfer-out.json -name %s -arg2 %d", orbsAsbContractName, tuid))
+	amount, _ := strconv.ParseUint(out[2], 10, 32)
+	dust, _ := strconv.ParseUint(out[3], 10, 32)
+	blockHeight, _ := strconv.ParseUint(out[4], 10, 64)
+	return blockHeight != 0, int(amount), int(dust)
+}
+
+func (gc *gammaCliAdapter) GetDecimals(orbsAsbContractName string) (ethereumDecimals int, orbsDecimals int) {
+	ethOut := gc.runQuery("./gammacli-jsons/asb-get-eth-decimals.json -name " + orbsAsbContractName)
+	orbsOut := gc.runQuery("./gammacli-jsons/asb-get-orbs-decimals.json -name " + orbsAsbContractName)
+	eth, _ := strconv.ParseUint(ethOut[0], 10, 32)
+	orbs, _ := strconv.ParseUint(orbsOut[0], 10, 32)
+	return int(eth), int(orbs)
+}
+
+func (gc *gammaCliAdapter) GetTotalDust(orbsAsbContractName string) (totalDustOnOrbs int) {
+	out := gc.runQuery("./gammacli-jsons/asb-get-total-dust.json -name " + orbsAsbContractName)
+	n, _ := strconv.ParseUint(out[0], 10, 32)
+	return int(n)
+}
+
 func (gc *gammaCliAdapter) OrbsUserIdToHexAddress(orbsUserId string) (userAccountOnOrbsHex string) {
 	file, err := ioutil.ReadFile("orbs-test-keys.json")
 	if err != nil {
@@ -114,6 +162,23 @@ func (gc *gammaCliAdapter) OrbsUserIdToHexAddress(orbsUserId string) (userAccoun
 	return key.Address
 }
 
+func (gc *gammaCliAdapter) runQuery(args string) (outputArguments []string) {
+	bytes := gc.run("run-query " + args + " -signer user1")
+	out := struct {
+		OutputArguments []*struct {
+			Value string
+		}
+	}{}
+	err := json.Unmarshal(bytes, &out)
+	if err != nil {
+		panic(err.Error() + "\n" + string(bytes))
+	}
+	for _, arg := range out.OutputArguments {
+		outputArguments = append(outputArguments, arg.Value)
+	}
+	return outputArguments
+}
+
 func (gc *gammaCliAdapter) run(args string, env ...string) []byte {
 	args += " -env " + gc.env
 	if gc.debug {
@@ -0,0 +1,237 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package driver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// blocks mined by WaitForFinality, matches the confirmations the orbs asb requires by default
+const inMemoryFinalityBlocks = 12
+
+// an ethereum of one ERC20 and one AutonomousSwapBridge kept in memory, every transaction mines a block.
+// the asb locks on TransferOut and emits EthTransferredOut, and unlocks on TransferIn of an orbs receipt
+// made by the in process orbs adapter, nothing signs the receipt so the proof is not checked.
+type inMemoryEthereum struct {
+	debug               bool
+	blockNumber         uint64
+	erc20Address        string
+	balances            map[string]int
+	asbAddress          string
+	asbErc20Address     string
+	orbsAsbContractName string
+	decimalsScale       int
+	tuidCounter         uint64
+	maxOrbsTuid         uint64
+	spentOrbsTuids      map[uint64]bool
+	logs                map[string]*ethTransferredOutLog
+	logsByTuid          map[uint64]*ethTransferredOutLog
+}
+
+type ethTransferredOutLog struct {
+	blockNumber uint64
+	tuid        uint64
+	from        [20]byte
+	to          [20]byte
+	value       int
+}
+
+// the receipt of an OrbsTransferredOut event, packed as hex encoded json. Amount is in orbs token units and Dust in
+// ethereum token units, as the ethereum asb reads them
+type inProcessOrbsReceipt struct {
+	OrbsAsbContractName string
+	Tuid                uint64
+	From                []byte
+	To                  []byte
+	Amount              uint64
+	AsbAddress          []byte
+	Dust                uint64
+}
+
+func AdapterForInMemoryEthereum(config *Config) EthereumAdapter {
+	return newInMemoryEthereum(config)
+}
+
+func newInMemoryEthereum(config *Config) *inMemoryEthereum {
+	return &inMemoryEthereum{
+		debug:          config.DebugLogs,
+		balances:       make(map[string]int),
+		spentOrbsTuids: make(map[uint64]bool),
+		logs:           make(map[string]*ethTransferredOutLog),
+		logsByTuid:     make(map[uint64]*ethTransferredOutLog),
+	}
+}
+
+func (ie *inMemoryEthereum) DeployERC20Contract() (ethereumErc20Address string) {
+	ie.erc20Address = ie.mineContract("erc20")
+	return ie.erc20Address
+}
+
+func (ie *inMemoryEthereum) DeployASBContract(ethereumErc20Address string, orbsAsbContractName string) (ethereumAsbAddress string) {
+	ie.requireErc20(ethereumErc20Address)
+	ie.asbAddress = ie.mineContract("asb")
+	ie.asbErc20Address = ethereumErc20Address
+	ie.orbsAsbContractName = orbsAsbContractName
+	ie.decimalsScale = 1
+	ie.tuidCounter, ie.maxOrbsTuid = 0, 0
+	ie.spentOrbsTuids = make(map[uint64]bool)
+	return ie.asbAddress
+}
+
+func (ie *inMemoryEthereum) GetASBContractAddress() (ethereumAsbAddress string) {
+	return ie.asbAddress
+}
+
+func (ie *inMemoryEthereum) FundUserAccount(ethereumErc20Address string, userAccountOnEthereum string, userInitialBalanceOnEthereum int) (userBalanceOnEthereumAfter int) {
+	ie.requireErc20(ethereumErc20Address)
+	ie.mine("assign")
+	ie.balances[normalizeEthereumAddress(userAccountOnEthereum)] = userInitialBalanceOnEthereum
+	return ie.GetBalance(ethereumErc20Address, userAccountOnEthereum)
+}
+
+func (ie *inMemoryEthereum) TransferOut(ethereumErc20Address string, userAccountOnEthereum string, userAccountOnOrbs string, userTransferAmount int) (ethereumTxHash string, userBalanceOnEthereumAfter int) {
+	ie.requireErc20(ethereumErc20Address)
+	if ie.asbAddress == "" || ie.asbErc20Address != ethereumErc20Address {
+		panic(fmt.Sprintf("no asb deployed for ERC20 %s", ethereumErc20Address))
+	}
+	if userTransferAmount <= 0 {
+		panic("Value must be greater than 0!")
+	}
+	ie.transfer(userAccountOnEthereum, ie.asbAddress, userTransferAmount)
+
+	ethereumTxHash = ie.mine("transferOut")
+	ie.tuidCounter++
+	log := &ethTransferredOutLog{blockNumber: ie.blockNumber, tuid: ie.tuidCounter, value: userTransferAmount}
+	copy(log.from[:], decodeHexAddress(userAccountOnEthereum))
+	copy(log.to[:], decodeHexAddress(userAccountOnOrbs))
+	ie.logs[ethereumTxHash] = log
+	ie.logsByTuid[log.tuid] = log
+	ie.logf("EthTransferredOut tuid %d from %x to %x value %d in tx %s", log.tuid, log.from, log.to, log.value, ethereumTxHash)
+	return ethereumTxHash, ie.GetBalance(ethereumErc20Address, userAccountOnEthereum)
+}
+
+func (ie *inMemoryEthereum) TransferIn(ethereumErc20Address string, userAccountOnEthereum string, packedOrbsReceiptProof string, packedOrbsReceipt string) (ethereumTxHash string, userBalanceOnEthereumAfter int) {
+	ie.requireErc20(ethereumErc20Address)
+	receipt := unpackInProcessOrbsReceipt(packedOrbsReceipt)
+	if receipt.OrbsAsbContractName != ie.orbsAsbContractName {
+		panic("Incorrect Orbs ASB contract name!")
+	}
+	if !bytes.Equal(receipt.AsbAddress, decodeHexAddress(ie.asbAddress)) {
+		panic("Incorrect ASB address!")
+	}
+	if int(receipt.Dust) >= ie.decimalsScale {
+		panic("Dust must be less than one Orbs token unit!")
+	}
+	if ie.spentOrbsTuids[receipt.Tuid] {
+		panic("TUID was already spent!")
+	}
+	ie.spentOrbsTuids[receipt.Tuid] = true
+	if receipt.Tuid > ie.maxOrbsTuid {
+		ie.maxOrbsTuid = receipt.Tuid
+	}
+	value := int(receipt.Amount)*ie.decimalsScale + int(receipt.Dust)
+	ie.transfer(ie.asbAddress, "0x"+hex.EncodeToString(receipt.To), value)
+
+	ethereumTxHash = ie.mine("transferIn")
+	ie.logf("EthTransferredIn tuid %d from %x to %x value %d in tx %s", receipt.Tuid, receipt.From, receipt.To, value, ethereumTxHash)
+	return ethereumTxHash, ie.GetBalance(ethereumErc20Address, userAccountOnEthereum)
+}
+
+func (ie *inMemoryEthereum) GetBalance(ethereumErc20Address string, userAccountOnEthereum string) (userBalanceOnEthereum int) {
+	ie.requireErc20(ethereumErc20Address)
+	return ie.balances[normalizeEthereumAddress(userAccountOnEthereum)]
+}
+
+func (ie *inMemoryEthereum) WaitForFinality() {
+	for i := 0; i < inMemoryFinalityBlocks; i++ {
+		ie.mine("empty block")
+	}
+}
+
+func (ie *inMemoryEthereum) GetASBTuids() (tuidCounter uint64, maxOrbsTuid uint64) {
+	return ie.tuidCounter, ie.maxOrbsTuid
+}
+
+func (ie *inMemoryEthereum) IsOrbsTuidSpent(orbsTuid uint64) (spent bool) {
+	return ie.spentOrbsTuids[orbsTuid]
+}
+
+func (ie *inMemoryEthereum) GetTransferOutValue(tuid uint64) (value int) {
+	if log, found := ie.logsByTuid[tuid]; found {
+		return log.value
+	}
+	return 0
+}
+
+func (ie *inMemoryEthereum) requireErc20(ethereumErc20Address string) {
+	if ie.erc20Address == "" || ie.erc20Address != ethereumErc20Address {
+		panic(fmt.Sprintf("no ERC20 deployed at %s", ethereumErc20Address))
+	}
+}
+
+func (ie *inMemoryEthereum) transfer(from string, to string, value int) {
+	from, to = normalizeEthereumAddress(from), normalizeEthereumAddress(to)
+	if ie.balances[from] < value {
+		panic(fmt.Sprintf("transfer of %d from %s to %s failed since balance is only %d", value, from, to, ie.balances[from]))
+	}
+	ie.balances[from] -= value
+	ie.balances[to] += value
+}
+
+// mines a block with a single transaction
+func (ie *inMemoryEthereum) mine(tx string) (txHash string) {
+	ie.blockNumber++
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d %s", ie.blockNumber, tx)))
+	return "0x" + hex.EncodeToString(hash[:])
+}
+
+func (ie *inMemoryEthereum) mineContract(contract string) (address string) {
+	return ie.mine("deploy " + contract)[:42]
+}
+
+func (ie *inMemoryEthereum) logf(msg string, args ...interface{}) {
+	if ie.debug {
+		fmt.Printf("  ### ETHEREUM: "+msg+"\n", args...)
+	}
+}
+
+func packInProcessOrbsReceipt(receipt *inProcessOrbsReceipt) string {
+	bytes, err := json.Marshal(receipt)
+	if err != nil {
+		panic(err.Error())
+	}
+	return "0x" + hex.EncodeToString(bytes)
+}
+
+func unpackInProcessOrbsReceipt(packedOrbsReceipt string) *inProcessOrbsReceipt {
+	bytes, err := hex.DecodeString(strings.TrimPrefix(packedOrbsReceipt, "0x"))
+	if err != nil {
+		panic(err.Error())
+	}
+	receipt := &inProcessOrbsReceipt{}
+	if err := json.Unmarshal(bytes, receipt); err != nil {
+		panic(err.Error())
+	}
+	return receipt
+}
+
+func normalizeEthereumAddress(addr string) string {
+	return strings.ToLower(strings.TrimPrefix(addr, "0x"))
+}
+
+func decodeHexAddress(addr string) []byte {
+	bytes, err := hex.DecodeString(normalizeEthereumAddress(addr))
+	if err != nil || len(bytes) != 20 {
+		panic(fmt.Sprintf("%s is not a hex encoded address", addr))
+	}
+	return bytes
+}
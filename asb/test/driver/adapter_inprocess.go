@@ -0,0 +1,370 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+//go:generate go test -run TestInProcessContractsAreGenerated -update-contracts .
+
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/orbs-network/orbs-ethereum-contracts/asb/test/driver/internal/orbsasb"
+	"github.com/orbs-network/orbs-ethereum-contracts/asb/test/driver/internal/orbserc20proxy"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// deploys and configures the contracts like gamma-cli does with -signer user1
+const inProcessDeployer = "user1"
+
+// runs _OrbsASB and _OrbsERC20Proxy in process, every transaction and query is a unit testing service scope over the
+// state the contract committed so far. a service call a contract makes runs the called contract in a scope of its own,
+// with the calling contract as caller, and its state is committed with the transaction. the other sdk calls are stubbed
+// from the in memory ethereum and from the deployment, so the asb is expected to keep its defaults (equal decimals,
+// no fees) for the events it emits.
+type inProcessOrbs struct {
+	debug       bool
+	ethereum    *inMemoryEthereum
+	blockHeight uint64
+	contracts   map[string]*inProcessContract
+	receipts    map[string]*inProcessOrbsReceipt
+}
+
+type inProcessContract struct {
+	name    string
+	address []byte
+	methods map[string]reflect.Value
+	state   map[string][]byte
+}
+
+// the state of the contracts a transaction called, committed once the transaction is done
+type inProcessTransaction struct {
+	ip          *inProcessOrbs
+	signer      []byte
+	blockHeight uint64
+	called      map[string]*inProcessContract
+}
+
+// an sdk handler of a scope that sends the service calls of its contract to the called contracts of the transaction
+type inProcessServiceCallRouter struct {
+	context.SdkHandler
+	tx       *inProcessTransaction
+	contract *inProcessContract
+}
+
+func AdapterForInProcessOrbs(config *Config, ethereum EthereumAdapter) OrbsAdapter {
+	inMemory, ok := ethereum.(*inMemoryEthereum)
+	if !ok {
+		panic("the in process orbs adapter reads ethereum logs from AdapterForInMemoryEthereum")
+	}
+	return &inProcessOrbs{
+		debug:     config.DebugLogs,
+		ethereum:  inMemory,
+		contracts: make(map[string]*inProcessContract),
+		receipts:  make(map[string]*inProcessOrbsReceipt),
+	}
+}
+
+func (ip *inProcessOrbs) DeployERC20Contract(orbsErc20ContractName string, orbsAsbContractName string) {
+	erc20 := newInProcessContract(orbsErc20ContractName, orbserc20proxy.PUBLIC, orbserc20proxy.SYSTEM)
+	ip.contracts[orbsErc20ContractName] = erc20
+	ip.sendTransaction(erc20, inProcessDeployer, nil, "_init")
+	ip.sendTransaction(erc20, inProcessDeployer, func(m unit.Mockery) {
+		m.MockCallContractAddress(orbsAsbContractName, inProcessContractAddress(orbsAsbContractName))
+	}, "asbBind", orbsAsbContractName)
+}
+
+func (ip *inProcessOrbs) DeployASBContract(orbsAsbContractName string, orbsErc20ContractName string) {
+	asb := newInProcessContract(orbsAsbContractName, orbsasb.PUBLIC, orbsasb.SYSTEM)
+	ip.contracts[orbsAsbContractName] = asb
+	ip.sendTransaction(asb, inProcessDeployer, nil, "_init")
+	ip.sendTransaction(asb, inProcessDeployer, nil, "resetContract")
+	ip.sendTransaction(asb, inProcessDeployer, nil, "setTokenContract", orbsErc20ContractName)
+}
+
+func (ip *inProcessOrbs) BindASBContractToEthereum(orbsAsbContractName string, ethereumAsbAddress string) {
+	ip.sendTransaction(ip.contract(orbsAsbContractName), inProcessDeployer, nil, "setAsbAddr", ethereumAsbAddress)
+}
+
+func (ip *inProcessOrbs) OrbsUserIdToHexAddress(orbsUserId string) (userAccountOnOrbsHex string) {
+	return "0x" + hex.EncodeToString(inProcessUserAddress(orbsUserId))
+}
+
+func (ip *inProcessOrbs) TransferIn(orbsErc20ContractName string, orbsAsbContractName string, userAccountOnOrbs string, ethereumTxHash string) (userBalanceOnOrbsAfter int) {
+	log, found := ip.ethereum.logs[ethereumTxHash]
+	if !found {
+		panic(fmt.Sprintf("no EthTransferredOut log in ethereum tx %s", ethereumTxHash))
+	}
+	asb := ip.contract(orbsAsbContractName)
+	ip.sendTransaction(asb, userAccountOnOrbs, func(m unit.Mockery) {
+		m.MockEthereumGetBlockNumber(int(ip.ethereum.blockNumber))
+		m.MockEthereumLog(asb.call("getAsbAddr")[0].(string), asb.call("getAsbAbi")[0].(string), ethereumTxHash, "EthTransferredOut", int(log.blockNumber), 0, func(out interface{}) {
+			e := out.(*orbsasb.EthTransferredOut)
+			e.Tuid = new(big.Int).SetUint64(log.tuid)
+			e.From = log.from
+			e.To = log.to
+			e.Value = big.NewInt(int64(log.value))
+		})
+	}, "transferIn", ethereumTxHash)
+	return ip.GetBalance(orbsErc20ContractName, userAccountOnOrbs)
+}
+
+func (ip *inProcessOrbs) TransferOut(orbsErc20ContractName string, orbsAsbContractName string, userAccountOnOrbs string, userAccountOnEthereum string, userTransferAmount int) (orbsTxId string, userBalanceOnOrbsAfter int) {
+	asb := ip.contract(orbsAsbContractName)
+	user := inProcessUserAddress(userAccountOnOrbs)
+	ethAddr := decodeHexAddress(userAccountOnEthereum)
+	amount := uint64(userTransferAmount)
+	receipt := &inProcessOrbsReceipt{OrbsAsbContractName: orbsAsbContractName, From: user, To: ethAddr, Amount: amount}
+	orbsTxId = ip.sendTransaction(asb, userAccountOnOrbs, func(m unit.Mockery) {
+		receipt.Tuid = asb.call("getOutTuid", "")[0].(uint64) + 1
+		receipt.AsbAddress = decodeHexAddress(asb.call("getAsbAddr")[0].(string))
+		receipt.Dust, _ = strconv.ParseUint(asb.call("getDust", "", user)[0].(string), 10, 64)
+		m.MockEmitEvent(orbsasb.OrbsTransferredOut, receipt.Tuid, user, ethAddr, amount, receipt.AsbAddress, receipt.Dust)
+	}, "transferOut", ethAddr, amount)
+	ip.receipts[orbsTxId] = receipt
+	return orbsTxId, ip.GetBalance(orbsErc20ContractName, userAccountOnOrbs)
+}
+
+func (ip *inProcessOrbs) GetBalance(orbsErc20ContractName string, userAccountOnOrbs string) (userBalanceOnOrbs int) {
+	return int(ip.runQuery(ip.contract(orbsErc20ContractName), "balanceOf", inProcessUserAddress(userAccountOnOrbs))[0].(uint64))
+}
+
+// nothing signs an in process receipt, the in memory ethereum takes it as is
+func (ip *inProcessOrbs) GenerateReceiptProof(orbsTxId string) (packedOrbsReceiptProof string, packedOrbsReceipt string) {
+	receipt, found := ip.receipts[orbsTxId]
+	if !found {
+		panic(fmt.Sprintf("no OrbsTransferredOut event in orbs tx %s", orbsTxId))
+	}
+	return "0x", packInProcessOrbsReceipt(receipt)
+}
+
+func (ip *inProcessOrbs) GetTotalSupply(orbsErc20ContractName string) (totalSupplyOnOrbs int) {
+	return int(ip.runQuery(ip.contract(orbsErc20ContractName), "totalSupply")[0].(uint64))
+}
+
+func (ip *inProcessOrbs) GetInTuidMax(orbsAsbContractName string) (inTuidMax uint64) {
+	return ip.runQuery(ip.contract(orbsAsbContractName), "getInTuidMax", "")[0].(uint64)
+}
+
+func (ip *inProcessOrbs) GetOutTuid(orbsAsbContractName string) (outTuid uint64) {
+	return ip.runQuery(ip.contract(orbsAsbContractName), "getOutTuid", "")[0].(uint64)
+}
+
+func (ip *inProcessOrbs) GetTransferIn(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int) {
+	out := ip.runQuery(ip.contract(orbsAsbContractName), "getTransferIn", "", tuid)
+	return out[5].(uint64) != 0, int(out[4].(uint64))
+}
+
+func (ip *inProcessOrbs) GetTransferOut(orbsAsbContractName string, tuid uint64) (found bool, orbsAmount int, ethereumDust int) {
+	out := ip.runQuery(ip.contract(orbsAsbContractName), "getTransferOut", "", tuid)
+	return out[4].(uint64) != 0, int(out[2].(uint64)), int(out[3].(uint64))
+}
+
+func (ip *inProcessOrbs) GetDecimals(orbsAsbContractName string) (ethereumDecimals int, orbsDecimals int) {
+	asb := ip.contract(orbsAsbContractName)
+	return int(ip.runQuery(asb, "getEthDecimals", "")[0].(uint32)), int(ip.runQuery(asb, "getOrbsDecimals", "")[0].(uint32))
+}
+
+func (ip *inProcessOrbs) GetTotalDust(orbsAsbContractName string) (totalDustOnOrbs int) {
+	n, _ := strconv.ParseUint(ip.runQuery(ip.contract(orbsAsbContractName), "getTotalDust", "")[0].(string), 10, 32)
+	return int(n)
+}
+
+// prepare stubs the sdk calls of the method other than service calls
+func (ip *inProcessOrbs) sendTransaction(contract *inProcessContract, signerUserId string, prepare func(m unit.Mockery), methodName string, args ...interface{}) (orbsTxId string) {
+	ip.blockHeight++
+	tx := ip.newTransaction(inProcessUserAddress(signerUserId))
+	tx.run(contract, tx.signer, func(m unit.Mockery) {
+		if prepare != nil {
+			prepare(m)
+		}
+		contract.call(methodName, args...)
+	})
+	tx.commit()
+
+	orbsTxId = fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%d %s.%s", ip.blockHeight, contract.name, methodName))))
+	ip.logf("%s.%s%v signed by %s at block %d, tx %s", contract.name, methodName, args, signerUserId, ip.blockHeight, orbsTxId)
+	return orbsTxId
+}
+
+// the state a query writes, also through service calls, is dropped
+func (ip *inProcessOrbs) runQuery(contract *inProcessContract, methodName string, args ...interface{}) (out []interface{}) {
+	tx := ip.newTransaction(inProcessUserAddress(inProcessDeployer))
+	tx.run(contract, tx.signer, func(m unit.Mockery) {
+		out = contract.call(methodName, args...)
+	})
+	return out
+}
+
+func (ip *inProcessOrbs) newTransaction(signer []byte) *inProcessTransaction {
+	return &inProcessTransaction{
+		ip:          ip,
+		signer:      signer,
+		blockHeight: ip.blockHeight,
+		called:      make(map[string]*inProcessContract),
+	}
+}
+
+// runs f in a scope of the contract as the transaction left it so far, with the service calls of the contract routed
+func (tx *inProcessTransaction) run(contract *inProcessContract, caller []byte, f func(m unit.Mockery)) {
+	contract = tx.contract(contract.name)
+	inSdkScope(func(handler context.SdkHandler) context.SdkHandler {
+		return &inProcessServiceCallRouter{SdkHandler: handler, tx: tx, contract: contract}
+	}, func(scoped func(m unit.Mockery)) {
+		contract.commit(contract.inScope(tx.signer, caller, tx.blockHeight, scoped))
+	}, f)
+}
+
+func (tx *inProcessTransaction) contract(name string) *inProcessContract {
+	if called, found := tx.called[name]; found {
+		return called
+	}
+	committed := tx.ip.contract(name)
+	called := &inProcessContract{
+		name:    committed.name,
+		address: committed.address,
+		methods: committed.methods,
+		state:   make(map[string][]byte, len(committed.state)),
+	}
+	for key, value := range committed.state {
+		called.state[key] = value
+	}
+	tx.called[name] = called
+	return called
+}
+
+func (tx *inProcessTransaction) commit() {
+	for name, called := range tx.called {
+		tx.ip.contract(name).state = called.state
+	}
+}
+
+func (r *inProcessServiceCallRouter) SdkServiceCallMethod(ctx context.ContextId, permissionScope context.PermissionScope, serviceName string, methodName string, args ...interface{}) (out []interface{}) {
+	r.tx.ip.logf("%s calls %s.%s%v", r.contract.name, serviceName, methodName, args)
+	callee := r.tx.contract(serviceName)
+	r.tx.run(callee, r.contract.address, func(m unit.Mockery) {
+		out = callee.call(methodName, args...)
+	})
+	return out
+}
+
+// inSdkScope runs scope on a goroutine of its own and, once scope has pushed the sdk context of its unit testing
+// scope, has the handler of that context wrapped while f runs.
+//
+// this works around orbs-contract-sdk v1.2.0 and has to be revisited when the sdk is upgraded: its fake sdk stubs a
+// service call only with a fixed result (MockServiceCallMethod), and its context package keeps one context per
+// goroutine, so the handler is only replaced by popping and pushing the context, and a scope opened while another is
+// running, as a service call does, needs a goroutine of its own.
+func inSdkScope(wrap func(handler context.SdkHandler) context.SdkHandler, scope func(f func(m unit.Mockery)), f func(m unit.Mockery)) {
+	failure := make(chan interface{})
+	go func() {
+		defer func() {
+			failure <- recover()
+		}()
+		scope(func(m unit.Mockery) {
+			contextId, handler, permissionScope := context.GetContext()
+			context.PopContext(contextId)
+			context.PushContext(contextId, wrap(handler), permissionScope)
+			defer func() {
+				context.PopContext(contextId)
+				// a failed scope never returns to the unit testing scope, which would leave its context behind
+				if err := recover(); err != nil {
+					panic(err)
+				}
+				context.PushContext(contextId, handler, permissionScope)
+			}()
+			f(m)
+		})
+	}()
+	if err := <-failure; err != nil {
+		panic(err)
+	}
+}
+
+func (ip *inProcessOrbs) contract(name string) *inProcessContract {
+	contract, found := ip.contracts[name]
+	if !found {
+		panic(fmt.Sprintf("contract %s is not deployed", name))
+	}
+	return contract
+}
+
+func (ip *inProcessOrbs) logf(msg string, args ...interface{}) {
+	if ip.debug {
+		fmt.Printf("  ### ORBS: "+msg+"\n", args...)
+	}
+}
+
+// methods are found by name in the exports of the contract, like the orbs processor does
+func newInProcessContract(name string, exports ...[]interface{}) *inProcessContract {
+	contract := &inProcessContract{
+		name:    name,
+		address: inProcessContractAddress(name),
+		methods: make(map[string]reflect.Value),
+		state:   make(map[string][]byte),
+	}
+	for _, export := range exports {
+		for _, method := range export {
+			value := reflect.ValueOf(method)
+			fullName := runtime.FuncForPC(value.Pointer()).Name()
+			contract.methods[fullName[strings.LastIndex(fullName, ".")+1:]] = value
+		}
+	}
+	return contract
+}
+
+func (c *inProcessContract) call(methodName string, args ...interface{}) (out []interface{}) {
+	method, found := c.methods[methodName]
+	if !found {
+		panic(fmt.Sprintf("method %s is not exported by contract %s", methodName, c.name))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		in[i] = reflect.ValueOf(arg)
+	}
+	for _, value := range method.Call(in) {
+		out = append(out, value.Interface())
+	}
+	return out
+}
+
+// the scope starts from the committed state, the diffs are all the state it wrote
+func (c *inProcessContract) inScope(signer []byte, caller []byte, blockHeight uint64, f func(m unit.Mockery)) []*unit.StateDiff {
+	diffs, _, _ := unit.InServiceScope(signer, caller, func(m unit.Mockery) {
+		m.MockEnvBlockHeight(int(blockHeight))
+		for key, value := range c.state {
+			state.WriteBytes([]byte(key), value)
+		}
+		f(m)
+	})
+	return diffs
+}
+
+func (c *inProcessContract) commit(diffs []*unit.StateDiff) {
+	for _, diff := range diffs {
+		c.state[string(diff.Key)] = diff.Value
+	}
+}
+
+func inProcessUserAddress(orbsUserId string) []byte {
+	return inProcessAddress("user " + orbsUserId)
+}
+
+func inProcessContractAddress(contractName string) []byte {
+	return inProcessAddress("contract " + contractName)
+}
+
+func inProcessAddress(id string) []byte {
+	hash := sha256.Sum256([]byte(id))
+	return hash[:20]
+}
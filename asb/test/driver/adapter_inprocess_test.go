@@ -0,0 +1,40 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package driver
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestInProcessOrbs_RoutesServiceCallsOfTheAsb(t *testing.T) {
+	config := &Config{}
+	ethereum := newInMemoryEthereum(config)
+	ip := AdapterForInProcessOrbs(config, ethereum).(*inProcessOrbs)
+	ethereumErc20Address := ethereum.DeployERC20Contract()
+	ip.DeployERC20Contract("erc20", "asb")
+	ip.DeployASBContract("asb", "erc20")
+	ip.BindASBContractToEthereum("asb", ethereum.DeployASBContract(ethereumErc20Address, "asb"))
+	ethereum.FundUserAccount(ethereumErc20Address, "0x000000000000000000000000000000000000abcd", 100)
+	erc20, asb := ip.contract("erc20"), ip.contract("asb")
+
+	// the asb reads the treasury from the token contract
+	committed := asb.state
+	require.Panics(t, func() {
+		ip.sendTransaction(asb, inProcessDeployer, nil, "setFees", "", "in", uint64(5), uint64(0))
+	})
+	require.Equal(t, committed, asb.state, "a failed transaction should not commit")
+	ip.sendTransaction(erc20, inProcessDeployer, nil, "setTreasury", inProcessUserAddress("treasury"))
+	ip.sendTransaction(asb, inProcessDeployer, nil, "setFees", "", "in", uint64(5), uint64(0))
+
+	// asbMint and asbMintFee run on the token contract within the same transaction
+	ethereumTxHash, _ := ethereum.TransferOut(ethereumErc20Address, "0x000000000000000000000000000000000000abcd", ip.OrbsUserIdToHexAddress("user2"), 30)
+	ethereum.WaitForFinality()
+	require.Equal(t, 25, ip.TransferIn("erc20", "asb", "user2", ethereumTxHash))
+	require.Equal(t, 5, ip.GetBalance("erc20", "treasury"))
+	require.Equal(t, 30, ip.GetTotalSupply("erc20"))
+}
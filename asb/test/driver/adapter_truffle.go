@@ -144,6 +144,50 @@ func (ta *truffleAdapter) GetBalance(ethereumErc20Address string, userAccountOnE
 	return int(n)
 }
 
+func (ta *truffleAdapter) GetASBTuids() (tuidCounter uint64, maxOrbsTuid uint64) {
+	bytes := ta.run("exec ./truffle-scripts/getASBTuids.js")
+	out := struct {
+		TuidCounter string
+		MaxOrbsTuid string
+	}{}
+	err := json.Unmarshal(bytes, &out)
+	if err != nil {
+		panic(err.Error() + "\n" + string(bytes))
+	}
+	tuidCounter, _ = strconv.ParseUint(out.TuidCounter, 10, 64)
+	maxOrbsTuid, _ = strconv.ParseUint(out.MaxOrbsTuid, 10, 64)
+	return tuidCounter, maxOrbsTuid
+}
+
+func (ta *truffleAdapter) IsOrbsTuidSpent(orbsTuid uint64) (spent bool) {
+	bytes := ta.run("exec ./truffle-scripts/isOrbsTuidSpent.js",
+		"TUID="+fmt.Sprintf("%d", orbsTuid),
+	)
+	out := struct {
+		Spent bool
+	}{}
+	err := json.Unmarshal(bytes, &out)
+	if err != nil {
+		panic(err.Error() + "\n" + string(bytes))
+	}
+	return out.Spent
+}
+
+func (ta *truffleAdapter) GetTransferOutValue(tuid uint64) (value int) {
+	bytes := ta.run("exec ./truffle-scripts/getTransferOutValue.js",
+		"TUID="+fmt.Sprintf("%d", tuid),
+	)
+	out := struct {
+		Value string
+	}{}
+	err := json.Unmarshal(bytes, &out)
+	if err != nil {
+		panic(err.Error() + "\n" + string(bytes))
+	}
+	n, _ := strconv.ParseUint(out.Value, 10, 32)
+	return int(n)
+}
+
 func (ta *truffleAdapter) run(args string, env ...string) []byte {
 	args += " --network " + ta.network
 	if ta.debug {
@@ -0,0 +1,162 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package driver
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// the tokens locked in the ethereum asb back the orbs supply and the transfers still in flight, locked on ethereum equals
+// the total supply on orbs + the dust held on orbs + pending to orbs (locked, not minted yet) + pending to ethereum
+// (burned, not released yet). everything is compared in ethereum token units, orbs amounts are scaled by the asb decimals.
+// fees stay in the supply since the treasury holds them.
+// only the token of the bound ethereum asb is audited, tokens registered on the orbs asb are not.
+type supplySnapshot struct {
+	lockedOnEthereum    int
+	totalSupplyOnOrbs   int // in orbs token units
+	totalDustOnOrbs     int
+	decimalsScale       int // ethereum token units in one orbs token unit
+	ethereumTuidCounter uint64
+	ethereumMaxOrbsTuid uint64
+	orbsInTuidMax       uint64
+	orbsOutTuid         uint64
+	toOrbs              []*transferSnapshot // by ethereum tuid, 1 to ethereumTuidCounter
+	toEthereum          []*transferSnapshot // by orbs tuid, 1 to orbsOutTuid
+	unlockedToOrbs      []*transferSnapshot // minted on orbs above ethereumTuidCounter
+	unburnedToEthereum  []*transferSnapshot // released on ethereum above orbsOutTuid
+}
+
+// sentValue is in ethereum token units, what the ethereum asb locked or releases. receivedValue is the orbs amount minted
+// before fees.
+type transferSnapshot struct {
+	tuid          uint64
+	sentValue     int
+	received      bool
+	receivedValue int
+}
+
+func RunSupplyAuditFlow(t *testing.T, config *Config, orbs OrbsAdapter, ethereum EthereumAdapter) {
+
+	require.NoError(t, config.Validate())
+
+	logStage("Auditing bridge supply...")
+	snapshot := takeSupplySnapshot(config, orbs, ethereum)
+	pendingToOrbs, pendingToEthereum := snapshot.pendingToOrbs(), snapshot.pendingToEthereum()
+	discrepancies := snapshot.discrepancies()
+	logStageDone("LockedOnEthereum=%d TotalSupplyOnOrbs=%d TotalDustOnOrbs=%d PendingToOrbs=%d (tuids %v) PendingToEthereum=%d (tuids %v) Discrepancies=%d",
+		snapshot.lockedOnEthereum, snapshot.totalSupplyOnOrbs, snapshot.totalDustOnOrbs, sumSent(pendingToOrbs), tuidsOf(pendingToOrbs), sumSent(pendingToEthereum), tuidsOf(pendingToEthereum), len(discrepancies))
+
+	require.Empty(t, discrepancies, "bridge supply invariant is broken")
+
+}
+
+func takeSupplySnapshot(config *Config, orbs OrbsAdapter, ethereum EthereumAdapter) *supplySnapshot {
+	s := &supplySnapshot{
+		lockedOnEthereum:  ethereum.GetBalance(config.EthereumErc20Address, ethereum.GetASBContractAddress()),
+		totalSupplyOnOrbs: orbs.GetTotalSupply(config.OrbsErc20ContractName),
+		orbsInTuidMax:     orbs.GetInTuidMax(config.OrbsAsbContractName),
+		orbsOutTuid:       orbs.GetOutTuid(config.OrbsAsbContractName),
+		totalDustOnOrbs:   orbs.GetTotalDust(config.OrbsAsbContractName),
+		decimalsScale:     1,
+	}
+	ethereumDecimals, orbsDecimals := orbs.GetDecimals(config.OrbsAsbContractName)
+	for i := orbsDecimals; i < ethereumDecimals; i++ {
+		s.decimalsScale *= 10
+	}
+	s.ethereumTuidCounter, s.ethereumMaxOrbsTuid = ethereum.GetASBTuids()
+
+	for tuid := uint64(1); tuid <= s.ethereumTuidCounter; tuid++ {
+		transfer := &transferSnapshot{tuid: tuid, sentValue: ethereum.GetTransferOutValue(tuid)}
+		transfer.received, transfer.receivedValue = orbs.GetTransferIn(config.OrbsAsbContractName, tuid)
+		s.toOrbs = append(s.toOrbs, transfer)
+	}
+	for tuid := s.ethereumTuidCounter + 1; tuid <= s.orbsInTuidMax; tuid++ {
+		if found, amount := orbs.GetTransferIn(config.OrbsAsbContractName, tuid); found {
+			s.unlockedToOrbs = append(s.unlockedToOrbs, &transferSnapshot{tuid: tuid, received: true, receivedValue: amount})
+		}
+	}
+
+	for tuid := uint64(1); tuid <= s.orbsOutTuid; tuid++ {
+		transfer := &transferSnapshot{tuid: tuid}
+		if found, amount, dust := orbs.GetTransferOut(config.OrbsAsbContractName, tuid); found {
+			transfer.sentValue = amount*s.decimalsScale + dust
+		}
+		transfer.received = ethereum.IsOrbsTuidSpent(tuid)
+		s.toEthereum = append(s.toEthereum, transfer)
+	}
+	for tuid := s.orbsOutTuid + 1; tuid <= s.ethereumMaxOrbsTuid; tuid++ {
+		if ethereum.IsOrbsTuidSpent(tuid) {
+			s.unburnedToEthereum = append(s.unburnedToEthereum, &transferSnapshot{tuid: tuid, received: true})
+		}
+	}
+	return s
+}
+
+func (s *supplySnapshot) pendingToOrbs() []*transferSnapshot {
+	return pendingOf(s.toOrbs)
+}
+
+func (s *supplySnapshot) pendingToEthereum() []*transferSnapshot {
+	return pendingOf(s.toEthereum)
+}
+
+func (s *supplySnapshot) discrepancies() (discrepancies []string) {
+	if len(s.unlockedToOrbs) > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("tuids %v were minted on orbs but never locked on ethereum (ethereum tuid counter %d, orbs inbound tuid max %d)",
+			tuidsOf(s.unlockedToOrbs), s.ethereumTuidCounter, s.orbsInTuidMax))
+	}
+	if len(s.unburnedToEthereum) > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("tuids %v were released on ethereum but never burned on orbs (orbs outbound tuid %d, ethereum max orbs tuid %d)",
+			tuidsOf(s.unburnedToEthereum), s.orbsOutTuid, s.ethereumMaxOrbsTuid))
+	}
+	for _, transfer := range s.toOrbs {
+		if transfer.sentValue == 0 {
+			discrepancies = append(discrepancies, fmt.Sprintf("tuid %d has no EthTransferredOut event on ethereum", transfer.tuid))
+		} else if transfer.received && transfer.receivedValue != transfer.sentValue/s.decimalsScale {
+			discrepancies = append(discrepancies, fmt.Sprintf("tuid %d locked %d on ethereum but minted %d on orbs", transfer.tuid, transfer.sentValue, transfer.receivedValue))
+		}
+	}
+	for _, transfer := range s.toEthereum {
+		if transfer.sentValue == 0 {
+			discrepancies = append(discrepancies, fmt.Sprintf("tuid %d has no transfer out record on orbs", transfer.tuid))
+		}
+	}
+
+	pendingToOrbs, pendingToEthereum := s.pendingToOrbs(), s.pendingToEthereum()
+	supplyOnOrbs := s.totalSupplyOnOrbs * s.decimalsScale
+	backed := supplyOnOrbs + s.totalDustOnOrbs + sumSent(pendingToOrbs) + sumSent(pendingToEthereum)
+	if s.lockedOnEthereum != backed {
+		discrepancies = append(discrepancies, fmt.Sprintf("locked on ethereum %d differs by %d from total supply on orbs %d + dust on orbs %d + pending to orbs %d (tuids %v) + pending to ethereum %d (tuids %v)",
+			s.lockedOnEthereum, s.lockedOnEthereum-backed, supplyOnOrbs, s.totalDustOnOrbs, sumSent(pendingToOrbs), tuidsOf(pendingToOrbs), sumSent(pendingToEthereum), tuidsOf(pendingToEthereum)))
+	}
+	return discrepancies
+}
+
+func pendingOf(transfers []*transferSnapshot) (pending []*transferSnapshot) {
+	for _, transfer := range transfers {
+		if !transfer.received {
+			pending = append(pending, transfer)
+		}
+	}
+	return pending
+}
+
+func sumSent(transfers []*transferSnapshot) (sum int) {
+	for _, transfer := range transfers {
+		sum += transfer.sentValue
+	}
+	return sum
+}
+
+func tuidsOf(transfers []*transferSnapshot) (tuids []uint64) {
+	for _, transfer := range transfers {
+		tuids = append(tuids, transfer.tuid)
+	}
+	return tuids
+}
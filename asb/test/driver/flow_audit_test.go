@@ -0,0 +1,75 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package driver
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSupplyAudit_BalancedWithPendingTransfers(t *testing.T) {
+	s := &supplySnapshot{
+		lockedOnEthereum:    230,
+		totalSupplyOnOrbs:   100,
+		decimalsScale:       1,
+		ethereumTuidCounter: 2,
+		ethereumMaxOrbsTuid: 1,
+		orbsInTuidMax:       1,
+		orbsOutTuid:         2,
+		toOrbs:              []*transferSnapshot{{tuid: 1, sentValue: 130, received: true, receivedValue: 130}, {tuid: 2, sentValue: 70}},
+		toEthereum:          []*transferSnapshot{{tuid: 1, sentValue: 30, received: true}, {tuid: 2, sentValue: 60}},
+	}
+
+	require.Empty(t, s.discrepancies())
+	require.Equal(t, []uint64{2}, tuidsOf(s.pendingToOrbs()))
+	require.Equal(t, []uint64{2}, tuidsOf(s.pendingToEthereum()))
+}
+
+func TestSupplyAudit_ScaledWithDust(t *testing.T) {
+	s := &supplySnapshot{
+		lockedOnEthereum:    13807,
+		totalSupplyOnOrbs:   100,
+		totalDustOnOrbs:     7,
+		decimalsScale:       100,
+		ethereumTuidCounter: 2,
+		ethereumMaxOrbsTuid: 1,
+		orbsInTuidMax:       1,
+		orbsOutTuid:         2,
+		toOrbs:              []*transferSnapshot{{tuid: 1, sentValue: 13007, received: true, receivedValue: 130}, {tuid: 2, sentValue: 700}},
+		toEthereum:          []*transferSnapshot{{tuid: 1, sentValue: 3000, received: true}, {tuid: 2, sentValue: 3100}},
+	}
+
+	require.Empty(t, s.discrepancies(), "dust of a transfer in stays on orbs and pending values are in ethereum units")
+
+	s.toOrbs[0].receivedValue = 13007
+	require.Equal(t, []string{"tuid 1 locked 13007 on ethereum but minted 13007 on orbs"}, s.discrepancies(), "orbs amounts are scaled")
+}
+
+func TestSupplyAudit_ReportsOffendingTuids(t *testing.T) {
+	s := &supplySnapshot{
+		lockedOnEthereum:    130,
+		totalSupplyOnOrbs:   150,
+		decimalsScale:       1,
+		ethereumTuidCounter: 1,
+		ethereumMaxOrbsTuid: 3,
+		orbsInTuidMax:       2,
+		orbsOutTuid:         1,
+		toOrbs:              []*transferSnapshot{{tuid: 1, sentValue: 130, received: true, receivedValue: 140}},
+		toEthereum:          []*transferSnapshot{{tuid: 1, received: true}},
+		unlockedToOrbs:      []*transferSnapshot{{tuid: 2, received: true, receivedValue: 10}},
+		unburnedToEthereum:  []*transferSnapshot{{tuid: 3, received: true}},
+	}
+
+	discrepancies := s.discrepancies()
+	require.Len(t, discrepancies, 5)
+	require.True(t, strings.HasPrefix(discrepancies[0], "tuids [2] were minted on orbs but never locked"))
+	require.True(t, strings.HasPrefix(discrepancies[1], "tuids [3] were released on ethereum but never burned"))
+	require.Equal(t, "tuid 1 locked 130 on ethereum but minted 140 on orbs", discrepancies[2])
+	require.Equal(t, "tuid 1 has no transfer out record on orbs", discrepancies[3])
+	require.True(t, strings.HasPrefix(discrepancies[4], "locked on ethereum 130 differs by -20 from total supply on orbs 150"))
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package driver
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var updateContracts = flag.Bool("update-contracts", false, "regenerate the in process copies of the orbs contracts")
+
+// gamma-cli deploys a contract from a single file of package main, which go cannot import, so the in process
+// adapter runs copies of the contracts under internal/ that differ only by their package clause
+var inProcessContracts = []struct {
+	source      string
+	packageName string
+}{
+	{"../orbs-contracts/_OrbsASB/contract.go", "orbsasb"},
+	{"../orbs-contracts/_OrbsERC20Proxy/contract.go", "orbserc20proxy"},
+}
+
+func TestInProcessContractsAreGenerated(t *testing.T) {
+	for _, contract := range inProcessContracts {
+		source, err := ioutil.ReadFile(contract.source)
+		require.NoError(t, err)
+		target := filepath.Join("internal", contract.packageName, "contract.go")
+		header := fmt.Sprintf("// Code generated from %s by go generate. DO NOT EDIT.\n\npackage %s\n", filepath.ToSlash(contract.source), contract.packageName)
+		generated := bytes.Replace(source, []byte("\npackage main\n"), []byte("\n"+header), 1)
+		require.NotEqual(t, source, generated, "%s is not of package main", contract.source)
+
+		if *updateContracts {
+			require.NoError(t, ioutil.WriteFile(target, generated, 0644))
+		}
+		existing, err := ioutil.ReadFile(target)
+		require.NoError(t, err)
+		require.Equal(t, string(generated), string(existing), "%s is out of date, run go generate ./driver", target)
+	}
+}
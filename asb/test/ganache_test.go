@@ -39,12 +39,14 @@ func TestEthToOrbsOnGanache(t *testing.T) {
 	orbs := driver.AdapterForGammaCliLocal(configGanache)
 	ethereum := driver.AdapterForTruffleGanache(configGanache)
 	driver.RunEthToOrbsFlow(t, configGanache, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, configGanache, orbs, ethereum)
 }
 
 func TestOrbsToEthOnGanache(t *testing.T) {
 	orbs := driver.AdapterForGammaCliLocal(configGanache)
 	ethereum := driver.AdapterForTruffleGanache(configGanache)
 	driver.RunOrbsToEthFlow(t, configGanache, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, configGanache, orbs, ethereum)
 }
 
 func TestFullFlowOnGanache(t *testing.T) {
@@ -55,5 +57,7 @@ func TestFullFlowOnGanache(t *testing.T) {
 	ethereum := driver.AdapterForTruffleGanache(configGanache)
 	driver.RunDeployFlow(t, configGanache, orbs, ethereum)
 	driver.RunEthToOrbsFlow(t, configGanache, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, configGanache, orbs, ethereum)
 	driver.RunOrbsToEthFlow(t, configGanache, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, configGanache, orbs, ethereum)
 }
@@ -0,0 +1,38 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"github.com/orbs-network/orbs-ethereum-contracts/asb/test/driver"
+	"testing"
+)
+
+// runs the contracts in process against an in memory ethereum, no gamma server or ganache needed
+func configInProcess() *driver.Config {
+	return &driver.Config{
+		DebugLogs:                        false,
+		EthereumErc20Address:             "", // every run deploys a fresh in memory ERC20
+		OrbsErc20ContractName:            "ERC20TokenProxy",
+		OrbsAsbContractName:              "ASBEthereum",
+		UserAccountOnEthereum:            "0xd1948B0252242B60DAb2E3566AD2971B87868644",
+		UserAccountOnOrbs:                "user1",
+		UserInitialBalanceOnEthereum:     20000,
+		UserTransferAmountToOrbs:         130,
+		UserTransferAmountBackToEthereum: 30,
+	}
+}
+
+func TestFullFlowInProcess(t *testing.T) {
+	config := configInProcess()
+	ethereum := driver.AdapterForInMemoryEthereum(config)
+	orbs := driver.AdapterForInProcessOrbs(config, ethereum)
+	driver.RunDeployFlow(t, config, orbs, ethereum)
+	driver.RunEthToOrbsFlow(t, config, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, config, orbs, ethereum)
+	driver.RunOrbsToEthFlow(t, config, orbs, ethereum)
+	driver.RunSupplyAuditFlow(t, config, orbs, ethereum)
+}
@@ -7,36 +7,106 @@
 package main
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/address"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/env"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/service"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math"
 	"math/big"
+	"strings"
 )
 
-var PUBLIC = sdk.Export(setAsbAddr, setTokenContract, resetContract /* TODO V1 security issue*/, getAsbAddr, getAsbAbi, getTokenContract, transferIn, transferOut)
+var PUBLIC = sdk.Export(setAsbAddr, setTokenContract, resetContract, getAsbAddr, getAsbAbi, getTokenContract, transferIn, transferOut,
+	transferInBatch, transferInTokenBatch, registerToken, setTokenEnabled, isTokenEnabled, getRegisteredTokenContract, transferInToken, transferOutToken,
+	getOwner, getPendingOwner, transferOwnership, claimOwnership, grantRole, revokeRole, hasRole,
+	setDecimals, getEthDecimals, getOrbsDecimals, getDust, getTotalDust,
+	getInTuidMax, getOutTuid, getTransferIn, getTransferOut, getTransfersInCount, getTransfersOutCount, getTransfersIn, getTransfersOut,
+	getStateLayoutVersion, getStateEpoch, migrateStateLayout,
+	pauseTransfers, resumeTransfers, isPaused, setRateLimits, getRateLimits, getRateLimitUsage,
+	setRequiredConfirmations, getRequiredConfirmations, setFees, getFees, getCollectedFees)
 var SYSTEM = sdk.Export(_init, setAsbAbi)
 var EVENTS = sdk.Export(OrbsTransferredOut)
 
 // defaults
 const defaultTokenContract = "Erc20TokenProxy"
+const defaultEthDecimals = 18
+const defaultOrbsDecimals = 18
+const defaultRequiredConfirmations = 12
+const DEFAULT_TOKEN = ""
+const maxTransfersPageSize = 100
+const maxFeeBasisPoints = 10000
+const maxTransferInBatchSize = 50
+const maxDecimalsDifference = 18
+const stateLayoutVersion = 2
+const DIRECTION_IN = "in"
+const DIRECTION_OUT = "out"
+const ROLE_ADMIN = "admin"
+const ROLE_OPERATOR = "operator"
+const TRANSFER_IN_MINTED = "minted"
+const TRANSFER_IN_DUPLICATE = "duplicate"
+const TRANSFER_IN_BAD_TUID = "bad tuid"
+const TRANSFER_IN_BAD_VALUE = "bad value"
+const TRANSFER_IN_BAD_ADDRESS = "bad address"
+const TRANSFER_IN_NOT_FOUND = "not found"
+const TRANSFER_IN_NOT_FINAL = "not final"
+const TRANSFER_IN_RATE_LIMITED = "rate limited"
+const TRANSFER_IN_FEE_ONLY = "fee only"
 const defaultAsbAbi = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"tuid","type":"uint256"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"bytes20"},{"indexed":false,"name":"value","type":"uint256"}],"name":"EthTransferredOut","type":"event"}]`
 
 // state keys
 var TOKEN_CONTRACT_KEY = []byte("_TOKEN_CONTRACT_KEY_")
 var ASB_ETH_ADDR_KEY = []byte("_ASB_ETH_ADDR_KEY_")
 var ASB_ABI_KEY = []byte("_ASB_ABI_KEY_")
-var OUT_TUID_KEY = []byte("_OUT_TUID_KEY_")
-var IN_TUID_KEY = []byte("_IN_TUID_KEY_")
-var IN_TUID_MAX_KEY = []byte("_IN_TUID_KEY_")
+var OWNER_KEY = []byte("_OWNER_KEY_")
+var PENDING_OWNER_KEY = []byte("_PENDING_OWNER_KEY_")
+var ROLE_KEY = []byte("_ROLE_KEY_")
+var ETH_DECIMALS_KEY = []byte("_ETH_DECIMALS_KEY_")
+var ORBS_DECIMALS_KEY = []byte("_ORBS_DECIMALS_KEY_")
+var DUST_KEY = []byte("_DUST_KEY_")
+var TOTAL_DUST_KEY = []byte("_TOTAL_DUST_KEY_")
+var PAUSED_KEY = []byte("_PAUSED_KEY_")
+var RATE_LIMITS_KEY = []byte("_RATE_LIMITS_KEY_")
+var RATE_LIMIT_USED_KEY = []byte("_RATE_LIMIT_USED_KEY_")
+var STATE_LAYOUT_VERSION_KEY = []byte("_STATE_LAYOUT_VERSION_KEY_")
+var STATE_EPOCH_KEY = []byte("_STATE_EPOCH_KEY_")
+var LEGACY_IN_TUIDS_KEY = []byte("_LEGACY_IN_TUIDS_KEY_")
+var TOKEN_REGISTRY_KEY = []byte("_TOKEN_REGISTRY_KEY_")
+var TOKEN_DISABLED_KEY = []byte("_TOKEN_DISABLED_KEY_")
+var REQUIRED_CONFIRMATIONS_KEY = []byte("_REQUIRED_CONFIRMATIONS_KEY_")
+var FEES_KEY = []byte("_FEES_KEY_")
+var COLLECTED_FEES_KEY = []byte("_COLLECTED_FEES_KEY_")
+
+// transfer state namespaces, versioned by layout and epoch (see _epochKey)
+const OUT_TUID_NS = "OutTuid"
+const IN_TUID_NS = "InTuid"
+const IN_TUID_MAX_NS = "InTuidMax"
+const IN_TRANSFER_NS = "InTransfer"
+const OUT_TRANSFER_NS = "OutTransfer"
+const IN_ADDR_TRANSFERS_NS = "InAddrTransfers"
+const IN_ADDR_TRANSFERS_COUNT_NS = "InAddrTransfersCount"
+const OUT_ADDR_TRANSFERS_NS = "OutAddrTransfers"
+const OUT_ADDR_TRANSFERS_COUNT_NS = "OutAddrTransfersCount"
+
+// layout 1 state keys, the inbound tuid max shared the key prefix of the inbound tuid flags
+var LEGACY_OUT_TUID_KEY = []byte("_OUT_TUID_KEY_")
+var LEGACY_IN_TUID_KEY = []byte("_IN_TUID_KEY_")
+var LEGACY_IN_TUID_MAX_KEY = []byte("_IN_TUID_KEY_")
 
 func _init() {
+	state.WriteBytes(OWNER_KEY, address.GetSignerAddress())
+	state.WriteUint32(STATE_LAYOUT_VERSION_KEY, stateLayoutVersion)
 	setAsbAbi(defaultAsbAbi)
-	setTokenContract(defaultTokenContract)
+	_setTokenContract(defaultTokenContract)
+	_setDecimals(DEFAULT_TOKEN, defaultEthDecimals, defaultOrbsDecimals)
+	state.WriteUint64(REQUIRED_CONFIRMATIONS_KEY, defaultRequiredConfirmations)
 	// TODO v1 do we have someway to start with a real asbEthAddress ?
 }
 
@@ -47,75 +117,209 @@ type EthTransferredOut struct {
 	Value *big.Int
 }
 
+// ethAsbAddress follows the arguments the ethereum verifier parses, it identifies the token the tuid belongs to.
+// amount is in orbs token units, the ethereum asb scales it by its decimals and adds ethDust, in ethereum token units.
 func OrbsTransferredOut(
 	tuid uint64,
 	orbsAddress []byte,
 	ethAddress []byte,
-	amount uint64) {
+	amount uint64,
+	ethAsbAddress []byte,
+	ethDust uint64) {
 }
 
 func transferIn(hexEncodedEthTxHash string) {
-	asbAddr := getAsbAddr()
+	transferInToken(DEFAULT_TOKEN, hexEncodedEthTxHash)
+}
+
+// the log is read from the ethereum asb of the token, so the mint is routed by the contract that emitted it
+func transferInToken(token string, hexEncodedEthTxHash string) {
+	_requireStateLayout()
+	_requireNotPaused(DIRECTION_IN)
+	token = _normalizeToken(token)
+	_requireTokenEnabled(token)
 	e := &EthTransferredOut{}
-	ethereum.GetTransactionLog(asbAddr, getAsbAbi(), hexEncodedEthTxHash, "EthTransferredOut", e)
+	ethBlockNumber, _ := ethereum.GetTransactionLog(_getTokenAsbAddr(token), getAsbAbi(), hexEncodedEthTxHash, "EthTransferredOut", e)
+	_requireConfirmed(hexEncodedEthTxHash, ethBlockNumber)
 
 	if e.Tuid == nil {
 		panic("Got nil tuid from logs")
 	}
 
+	if !e.Tuid.IsUint64() {
+		panic(fmt.Sprintf("Got tuid %s beyond uint64 from logs", e.Tuid))
+	}
+
 	if e.Value == nil || e.Value.Cmp(big.NewInt(0)) <= 0 {
 		panic("Got nil or non positive value from log")
 	}
 
 	address.ValidateAddress(e.To[:])
+	if e.To == [20]byte{} {
+		panic("Got zero orbs address from log")
+	}
 
-	inTuidKey := genInTuidKey(e.Tuid.Bytes())
-	if isInTuidExists(inTuidKey) {
+	if isInTuidExists(token, e.Tuid.Uint64()) {
 		panic(fmt.Errorf("transfer of %d to address %x failed since inbound-tuid %d has already been spent", e.Value, e.To, e.Tuid))
 	}
 
-	service.CallMethod(getTokenContract(), "asbMint", e.To[:], e.Value.Uint64())
+	_mintTransferIn(token, hexEncodedEthTxHash, e)
+}
+
+func transferInBatch(hexEncodedEthTxHashes string) string {
+	return transferInTokenBatch(DEFAULT_TOKEN, hexEncodedEthTxHashes)
+}
+
+// hashes are comma separated, the result has the status of each hash in the same order, comma separated.
+// a hash that cannot be minted is skipped with its status, so a relayer can sweep a backlog without knowing which
+// hashes were already submitted. a hash over a rate limit is skipped as rate limited and can be resubmitted in a later
+// period, a hash that does not cover its fee is minted whole to the treasury as fee only.
+func transferInTokenBatch(token string, hexEncodedEthTxHashes string) string {
+	_requireStateLayout()
+	_requireNotPaused(DIRECTION_IN)
+	token = _normalizeToken(token)
+	_requireTokenEnabled(token)
+	hashes := strings.Split(hexEncodedEthTxHashes, ",")
+	if len(hashes) > maxTransferInBatchSize {
+		panic(fmt.Sprintf("batch of %d tx hashes exceeds %d", len(hashes), maxTransferInBatchSize))
+	}
 
-	setInTuid(inTuidKey)
-	setInTuidMax(e.Tuid.Uint64())
+	statuses := make([]string, len(hashes))
+	for i, hexEncodedEthTxHash := range hashes {
+		e := &EthTransferredOut{}
+		statuses[i] = _transferInStatus(token, hexEncodedEthTxHash, e)
+		switch statuses[i] {
+		case TRANSFER_IN_MINTED, TRANSFER_IN_FEE_ONLY:
+			_mintTransferIn(token, hexEncodedEthTxHash, e)
+		}
+	}
+	return strings.Join(statuses, ",")
+}
+
+// the checks of transferInToken and of the mint, without side effects
+func _transferInStatus(token string, hexEncodedEthTxHash string, e *EthTransferredOut) string {
+	ethBlockNumber, found := _tryGetTransactionLog(token, hexEncodedEthTxHash, e)
+	if !found {
+		return TRANSFER_IN_NOT_FOUND
+	}
+	if ethereum.GetBlockNumber() < safeuint64.Add(ethBlockNumber, getRequiredConfirmations()) {
+		return TRANSFER_IN_NOT_FINAL
+	}
+	if e.Tuid == nil || !e.Tuid.IsUint64() {
+		return TRANSFER_IN_BAD_TUID
+	}
+	if e.Value == nil || e.Value.Cmp(big.NewInt(0)) <= 0 || !_isOrbsAmount(token, e.Value) {
+		return TRANSFER_IN_BAD_VALUE
+	}
+	if e.To == [20]byte{} {
+		return TRANSFER_IN_BAD_ADDRESS
+	}
+	if isInTuidExists(token, e.Tuid.Uint64()) {
+		return TRANSFER_IN_DUPLICATE
+	}
+	amount, _ := _ethToOrbsAmount(token, e.Value)
+	if _rateLimitExceeded(token, DIRECTION_IN, e.To[:], amount) != "" {
+		return TRANSFER_IN_RATE_LIMITED
+	}
+	if amount != 0 && _getFee(token, DIRECTION_IN, amount) >= amount {
+		return TRANSFER_IN_FEE_ONLY
+	}
+	return TRANSFER_IN_MINTED
+}
+
+// a log that cannot be read panics in the sdk, it is reported as not found
+func _tryGetTransactionLog(token string, hexEncodedEthTxHash string, e *EthTransferredOut) (ethBlockNumber uint64, found bool) {
+	defer func() {
+		if recover() != nil {
+			found = false
+		}
+	}()
+	ethBlockNumber, _ = ethereum.GetTransactionLog(_getTokenAsbAddr(token), getAsbAbi(), hexEncodedEthTxHash, "EthTransferredOut", e)
+	return ethBlockNumber, true
+}
+
+func _mintTransferIn(token string, hexEncodedEthTxHash string, e *EthTransferredOut) {
+	amount, dust := _ethToOrbsAmount(token, e.Value)
+	_useRateLimit(token, DIRECTION_IN, e.To[:], amount)
+	fee := _takeFee(token, DIRECTION_IN, amount)
+	if amount > fee {
+		service.CallMethod(_getTokenContract(token), "asbMint", e.To[:], amount-fee)
+	}
+	if fee > 0 {
+		service.CallMethod(_getTokenContract(token), "asbMintFee", fee)
+	}
+	_addDust(token, e.To[:], dust)
+
+	setInTuid(token, e.Tuid.Uint64())
+	setInTuidMax(token, e.Tuid.Uint64())
+	_recordTransferIn(token, e.Tuid.Uint64(), hexEncodedEthTxHash, e.From[:], e.To[:], e.Value, amount)
 }
 
 func transferOut(ethAddr []byte, amount uint64) {
-	tuid := safeuint64.Add(getOutTuid(), 1)
-	setOutTuid(tuid)
+	transferOutToken(DEFAULT_TOKEN, ethAddr, amount)
+}
 
+// amount is in orbs token units and includes the fee, the event carries the net amount and the refunded dust of the sender
+func transferOutToken(token string, ethAddr []byte, amount uint64) {
+	_requireStateLayout()
+	_requireNotPaused(DIRECTION_OUT)
+	token = _normalizeToken(token)
+	_requireTokenEnabled(token)
 	sourceOrbsAddress := address.GetSignerAddress()
-	service.CallMethod(getTokenContract(), "asbBurn", sourceOrbsAddress, amount)
+	fee := _takeFee(token, DIRECTION_OUT, amount)
+	if amount <= fee {
+		panic("transfer out of zero amount")
+	}
+	dust := _readBigInt(genDustKey(token, sourceOrbsAddress))
+
+	_useRateLimit(token, DIRECTION_OUT, sourceOrbsAddress, amount)
 
-	events.EmitEvent(OrbsTransferredOut, tuid, sourceOrbsAddress, ethAddr, amount)
+	tuid := safeuint64.Add(getOutTuid(token), 1)
+	setOutTuid(token, tuid)
+
+	if amount > fee {
+		service.CallMethod(_getTokenContract(token), "asbBurn", sourceOrbsAddress, amount-fee)
+	}
+	if fee > 0 {
+		service.CallMethod(_getTokenContract(token), "asbCollectFee", sourceOrbsAddress, fee)
+	}
+	_clearDust(token, sourceOrbsAddress, dust)
+	_recordTransferOut(token, tuid, sourceOrbsAddress, ethAddr, amount-fee, dust.Uint64())
+
+	events.EmitEvent(OrbsTransferredOut, tuid, sourceOrbsAddress, ethAddr, amount-fee, _getTokenAsbAddrBytes(token), dust.Uint64())
 }
 
-func genInTuidKey(tuid []byte) []byte {
-	return append(IN_TUID_KEY, tuid...)
+func genInTuidKey(token string, tuid uint64) []byte {
+	return _epochKey(token, IN_TUID_NS, _uint64ToBytes(tuid))
 }
 
-func isInTuidExists(tuidKey []byte) bool {
-	return state.ReadUint32(tuidKey) != 0
+func isInTuidExists(token string, tuid uint64) bool {
+	if state.ReadUint32(genInTuidKey(token, tuid)) != 0 {
+		return true
+	}
+	return token == DEFAULT_TOKEN && _isLegacyInTuidExists(tuid)
 }
 
-func setInTuid(tuidKey []byte) {
-	state.WriteUint32(tuidKey, 1)
+func setInTuid(token string, tuid uint64) {
+	state.WriteUint32(genInTuidKey(token, tuid), 1)
 }
 
-func getInTuidMax() uint64 {
-	return state.ReadUint64(IN_TUID_MAX_KEY)
+func getInTuidMax(token string) uint64 {
+	return state.ReadUint64(_epochKey(_normalizeToken(token), IN_TUID_MAX_NS))
 }
 
-func setInTuidMax(next uint64) {
-	state.WriteUint64(IN_TUID_MAX_KEY, next)
+func setInTuidMax(token string, tuid uint64) {
+	if tuid > getInTuidMax(token) {
+		state.WriteUint64(_epochKey(token, IN_TUID_MAX_NS), tuid)
+	}
 }
 
-func getOutTuid() uint64 {
-	return state.ReadUint64(OUT_TUID_KEY)
+func getOutTuid(token string) uint64 {
+	return state.ReadUint64(_epochKey(_normalizeToken(token), OUT_TUID_NS))
 }
 
-func setOutTuid(next uint64) {
-	state.WriteUint64(OUT_TUID_KEY, next)
+func setOutTuid(token string, next uint64) {
+	state.WriteUint64(_epochKey(token, OUT_TUID_NS), next)
 }
 
 func getAsbAddr() string {
@@ -123,6 +327,10 @@ func getAsbAddr() string {
 }
 
 func setAsbAddr(asbAddr string) { // upgrade
+	_requireRole(ROLE_ADMIN, "setAsbAddr")
+	if _isTokenRegistered(_normalizeToken(asbAddr)) {
+		panic(fmt.Sprintf("ethereum asb %s is already registered as a token", asbAddr))
+	}
 	state.WriteString(ASB_ETH_ADDR_KEY, asbAddr)
 }
 
@@ -139,14 +347,658 @@ func getTokenContract() string {
 }
 
 func setTokenContract(erc20Proxy string) { // upgrade
+	_requireRole(ROLE_ADMIN, "setTokenContract")
+	_setTokenContract(erc20Proxy)
+}
+
+func _setTokenContract(erc20Proxy string) {
 	state.WriteString(TOKEN_CONTRACT_KEY, erc20Proxy)
 }
 
+// a new epoch starts with empty transfer state, the previous epoch keys are never read again
 func resetContract() {
-	setOutTuid(0)
-	max := int64(getInTuidMax())
-	for i := int64(0); i <= max; i++ {
-		state.Clear(genInTuidKey(big.NewInt(i).Bytes()))
+	_requireRole(ROLE_ADMIN, "resetContract")
+	_requireStateLayout()
+	state.WriteUint64(STATE_EPOCH_KEY, getStateEpoch()+1)
+}
+
+/***
+ * tokens : besides the token of the bound ethereum asb (setAsbAddr, setTokenContract), tokens are registered by the address
+ * of their ethereum asb and mapped to an orbs token contract. the ethereum asb address (lower case hex, no 0x) identifies the
+ * token, its tuids, records, decimals, dust and rate limits are kept apart from other tokens.
+ * an ethereum asb serves one token only, otherwise a single ethereum transfer could be minted under two tuid namespaces.
+ * the ethereum asb only releases a transfer out whose ethAsbAddress argument of OrbsTransferredOut is its own address.
+ * a transfer out before migrateStateLayout has no ethAsbAddress, the ethereum asb of the default token releases it up to
+ * the outbound tuid of the migration (setLegacyBridge) unless the ethereum asb it succeeds already did.
+ */
+func registerToken(ethAsbAddr string, orbsTokenContract string, ethDecimals uint32, orbsDecimals uint32) {
+	_requireRole(ROLE_ADMIN, "registerToken")
+	token := _normalizeToken(ethAsbAddr)
+	if token == DEFAULT_TOKEN || token == _normalizeToken(getAsbAddr()) || _isTokenRegistered(token) {
+		panic(fmt.Sprintf("ethereum asb %s is already bridged", ethAsbAddr))
+	}
+	if orbsTokenContract == "" {
+		panic("orbs token contract is missing")
+	}
+	state.WriteString(genTokenKey(TOKEN_REGISTRY_KEY, token), orbsTokenContract)
+	_setDecimals(token, ethDecimals, orbsDecimals)
+}
+
+func setTokenEnabled(token string, enabled uint32) {
+	_requireRole(ROLE_ADMIN, "setTokenEnabled")
+	token = _normalizeToken(token)
+	if !_isTokenRegistered(token) {
+		panic(fmt.Sprintf("token %s is not registered", token))
+	}
+	if enabled != 0 {
+		state.Clear(genTokenKey(TOKEN_DISABLED_KEY, token))
+	} else {
+		state.WriteUint32(genTokenKey(TOKEN_DISABLED_KEY, token), 1)
+	}
+}
+
+func isTokenEnabled(token string) uint32 {
+	token = _normalizeToken(token)
+	if _isTokenRegistered(token) && state.ReadUint32(genTokenKey(TOKEN_DISABLED_KEY, token)) == 0 {
+		return 1
+	}
+	return 0
+}
+
+func getRegisteredTokenContract(token string) string {
+	return _getTokenContract(_normalizeToken(token))
+}
+
+func _isTokenRegistered(token string) bool {
+	return token == DEFAULT_TOKEN || state.ReadString(genTokenKey(TOKEN_REGISTRY_KEY, token)) != ""
+}
+
+func _requireTokenEnabled(token string) {
+	if isTokenEnabled(token) == 0 {
+		panic(fmt.Sprintf("token %s is not enabled", token))
+	}
+}
+
+func _getTokenContract(token string) string {
+	if token == DEFAULT_TOKEN {
+		return getTokenContract()
+	}
+	return state.ReadString(genTokenKey(TOKEN_REGISTRY_KEY, token))
+}
+
+func _getTokenAsbAddr(token string) string {
+	if token == DEFAULT_TOKEN {
+		return getAsbAddr()
+	}
+	return "0x" + token
+}
+
+func _getTokenAsbAddrBytes(token string) []byte {
+	asbAddr, err := hex.DecodeString(_normalizeToken(_getTokenAsbAddr(token)))
+	if err != nil {
+		return nil
+	}
+	return asbAddr
+}
+
+func _normalizeToken(ethAsbAddr string) string {
+	if ethAsbAddr == DEFAULT_TOKEN {
+		return DEFAULT_TOKEN
+	}
+	token := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(ethAsbAddr, "0x"), "0X"))
+	if decoded, err := hex.DecodeString(token); err != nil || len(decoded) != 20 {
+		panic(fmt.Sprintf("token %s is not an ethereum address", ethAsbAddr))
+	}
+	return token
+}
+
+// the default token keeps the keys it had before tokens were registered
+func genTokenKey(prefix []byte, token string) []byte {
+	key := append([]byte{}, prefix...)
+	if token == DEFAULT_TOKEN {
+		return key
+	}
+	return append(key, "T"+token+"_"...)
+}
+
+/***
+ * finality : an inbound transfer is accepted only once the block of its ethereum log is buried under the required
+ * number of confirmations, counted as the blocks after it up to the current ethereum block. an early submission is
+ * rejected with the ethereum block to retry after, no state is kept for it.
+ */
+func getRequiredConfirmations() uint64 {
+	return state.ReadUint64(REQUIRED_CONFIRMATIONS_KEY)
+}
+
+func setRequiredConfirmations(confirmations uint64) {
+	_requireRole(ROLE_ADMIN, "setRequiredConfirmations")
+	state.WriteUint64(REQUIRED_CONFIRMATIONS_KEY, confirmations)
+}
+
+func _requireConfirmed(hexEncodedEthTxHash string, ethBlockNumber uint64) {
+	finalBlockNumber := safeuint64.Add(ethBlockNumber, getRequiredConfirmations())
+	currentBlockNumber := ethereum.GetBlockNumber()
+	if currentBlockNumber < finalBlockNumber {
+		panic(fmt.Sprintf("ethereum tx %s at block %d is not final at block %d, retry after block %d (%d more blocks)",
+			hexEncodedEthTxHash, ethBlockNumber, currentBlockNumber, finalBlockNumber, finalBlockNumber-currentBlockNumber))
+	}
+}
+
+/***
+ * fees : each direction of a token has an optional fee of a flat amount plus basis points of the transferred amount,
+ * in orbs token units. the fee is credited to the treasury of the token contract, minted to it on the way in and moved
+ * to it instead of burned on the way out, so only the net amount crosses the bridge. a transfer out that does not cover
+ * its fee is rejected, a transfer in that does not is minted whole to the treasury since its tokens are already locked.
+ * a dust only transfer pays no fee. fees are only set once the token contract has a treasury.
+ */
+func setFees(token string, direction string, flatFee uint64, basisPoints uint64) {
+	_requireRole(ROLE_ADMIN, "setFees")
+	token = _normalizeToken(token)
+	if basisPoints > maxFeeBasisPoints {
+		panic(fmt.Sprintf("fee of %d basis points exceeds %d", basisPoints, maxFeeBasisPoints))
+	}
+	if flatFee != 0 || basisPoints != 0 {
+		tokenContract := _getTokenContract(token)
+		if len(service.CallMethod(tokenContract, "getTreasury")[0].([]byte)) == 0 {
+			panic(fmt.Sprintf("token contract %s has no treasury to credit the fees to", tokenContract))
+		}
+	}
+	state.WriteUint64(genFeeKey(token, direction, "FlatFee"), flatFee)
+	state.WriteUint64(genFeeKey(token, direction, "BasisPoints"), basisPoints)
+}
+
+func getFees(token string, direction string) (flatFee uint64, basisPoints uint64) {
+	token = _normalizeToken(token)
+	return state.ReadUint64(genFeeKey(token, direction, "FlatFee")), state.ReadUint64(genFeeKey(token, direction, "BasisPoints"))
+}
+
+func getCollectedFees(token string, direction string) uint64 {
+	return state.ReadUint64(genDirectionKey(genTokenKey(COLLECTED_FEES_KEY, _normalizeToken(token)), direction))
+}
+
+func _takeFee(token string, direction string, amount uint64) uint64 {
+	if amount == 0 {
+		return 0
+	}
+	fee := _getFee(token, direction, amount)
+	if fee >= amount {
+		if direction == DIRECTION_OUT {
+			panic(fmt.Sprintf("transfer %s of %d does not cover the fee %d", direction, amount, fee))
+		}
+		fee = amount
+	}
+	collectedKey := genDirectionKey(genTokenKey(COLLECTED_FEES_KEY, token), direction)
+	state.WriteUint64(collectedKey, safeuint64.Add(state.ReadUint64(collectedKey), fee))
+	return fee
+}
+
+func _getFee(token string, direction string, amount uint64) uint64 {
+	flatFee, basisPoints := getFees(token, direction)
+	proportionalFee := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(basisPoints))
+	proportionalFee.Div(proportionalFee, big.NewInt(maxFeeBasisPoints))
+	return safeuint64.Add(flatFee, proportionalFee.Uint64())
+}
+
+func genFeeKey(token string, direction string, fee string) []byte {
+	return append(append(genDirectionKey(genTokenKey(FEES_KEY, token), direction), '_'), fee...)
+}
+
+/***
+ * pause and rate limits : each direction (in mints, out burns) can be paused on its own, an operator or admin may pause
+ * but only an admin resumes, a pause applies to all tokens. minted and burned amounts of a token, in orbs token units, are limited
+ * per transfer, per orbs address per period and globally per period, a zero limit is unlimited. periods are aligned to the unix epoch.
+ */
+func isPaused(direction string) uint32 {
+	return state.ReadUint32(genDirectionKey(PAUSED_KEY, direction))
+}
+
+func pauseTransfers(direction string) {
+	signer := address.GetSignerAddress()
+	if hasRole(ROLE_OPERATOR, signer) == 0 && hasRole(ROLE_ADMIN, signer) == 0 {
+		panic(fmt.Sprintf("only operator or admin can call pauseTransfers, signer %x", signer))
+	}
+	state.WriteUint32(genDirectionKey(PAUSED_KEY, direction), 1)
+}
+
+func resumeTransfers(direction string) {
+	_requireRole(ROLE_ADMIN, "resumeTransfers")
+	state.Clear(genDirectionKey(PAUSED_KEY, direction))
+}
+
+func _requireNotPaused(direction string) {
+	if isPaused(direction) != 0 {
+		panic(fmt.Sprintf("transfer %s is paused", direction))
+	}
+}
+
+func setRateLimits(token string, direction string, maxPerTransfer uint64, maxPerAddressPerPeriod uint64, maxGlobalPerPeriod uint64, periodInSeconds uint64) {
+	_requireRole(ROLE_ADMIN, "setRateLimits")
+	token = _normalizeToken(token)
+	if (maxPerAddressPerPeriod != 0 || maxGlobalPerPeriod != 0) && periodInSeconds == 0 {
+		panic("rate limits per period require a period")
+	}
+	state.WriteUint64(genRateLimitKey(token, direction, "MaxPerTransfer"), maxPerTransfer)
+	state.WriteUint64(genRateLimitKey(token, direction, "MaxPerAddressPerPeriod"), maxPerAddressPerPeriod)
+	state.WriteUint64(genRateLimitKey(token, direction, "MaxGlobalPerPeriod"), maxGlobalPerPeriod)
+	state.WriteUint64(genRateLimitKey(token, direction, "PeriodInSeconds"), periodInSeconds)
+}
+
+func getRateLimits(token string, direction string) (maxPerTransfer uint64, maxPerAddressPerPeriod uint64, maxGlobalPerPeriod uint64, periodInSeconds uint64) {
+	token = _normalizeToken(token)
+	return state.ReadUint64(genRateLimitKey(token, direction, "MaxPerTransfer")),
+		state.ReadUint64(genRateLimitKey(token, direction, "MaxPerAddressPerPeriod")),
+		state.ReadUint64(genRateLimitKey(token, direction, "MaxGlobalPerPeriod")),
+		state.ReadUint64(genRateLimitKey(token, direction, "PeriodInSeconds"))
+}
+
+// usage in the current period, the remaining amounts are math.MaxUint64 when unlimited
+func getRateLimitUsage(token string, direction string, orbsAddr []byte) (period uint64, usedByAddress uint64, usedGlobal uint64, remainingForAddress uint64, remainingGlobal uint64) {
+	token = _normalizeToken(token)
+	_, maxPerAddressPerPeriod, maxGlobalPerPeriod, periodInSeconds := getRateLimits(token, direction)
+	if periodInSeconds == 0 {
+		return 0, 0, 0, math.MaxUint64, math.MaxUint64
+	}
+	period = _currentRatePeriod(periodInSeconds)
+	usedByAddress = state.ReadUint64(genRateLimitUsedKey(token, direction, period, orbsAddr))
+	usedGlobal = state.ReadUint64(genRateLimitUsedKey(token, direction, period, nil))
+	return period, usedByAddress, usedGlobal, _remainingRate(maxPerAddressPerPeriod, usedByAddress), _remainingRate(maxGlobalPerPeriod, usedGlobal)
+}
+
+func _useRateLimit(token string, direction string, orbsAddr []byte, amount uint64) {
+	if exceeded := _rateLimitExceeded(token, direction, orbsAddr, amount); exceeded != "" {
+		panic(exceeded)
+	}
+	_, _, _, periodInSeconds := getRateLimits(token, direction)
+	if periodInSeconds == 0 {
+		return
+	}
+	period := _currentRatePeriod(periodInSeconds)
+	addressKey := genRateLimitUsedKey(token, direction, period, orbsAddr)
+	state.WriteUint64(addressKey, safeuint64.Add(state.ReadUint64(addressKey), amount))
+	globalKey := genRateLimitUsedKey(token, direction, period, nil)
+	state.WriteUint64(globalKey, safeuint64.Add(state.ReadUint64(globalKey), amount))
+}
+
+// the limit the transfer exceeds, empty when within the limits
+func _rateLimitExceeded(token string, direction string, orbsAddr []byte, amount uint64) string {
+	maxPerTransfer, maxPerAddressPerPeriod, maxGlobalPerPeriod, _ := getRateLimits(token, direction)
+	if maxPerTransfer != 0 && amount > maxPerTransfer {
+		return fmt.Sprintf("transfer %s of %d exceeds the per transfer limit %d", direction, amount, maxPerTransfer)
+	}
+	period, _, _, remainingForAddress, remainingGlobal := getRateLimitUsage(token, direction, orbsAddr)
+	if amount > remainingForAddress {
+		return fmt.Sprintf("transfer %s of %d for address %x exceeds the per address limit %d for period %d", direction, amount, orbsAddr, maxPerAddressPerPeriod, period)
+	}
+	if amount > remainingGlobal {
+		return fmt.Sprintf("transfer %s of %d exceeds the global limit %d for period %d", direction, amount, maxGlobalPerPeriod, period)
+	}
+	return ""
+}
+
+func _currentRatePeriod(periodInSeconds uint64) uint64 {
+	return env.GetBlockTimestamp() / 1000000000 / periodInSeconds
+}
+
+func _remainingRate(limit uint64, used uint64) uint64 {
+	if limit == 0 {
+		return math.MaxUint64
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+func genDirectionKey(prefix []byte, direction string) []byte {
+	if direction != DIRECTION_IN && direction != DIRECTION_OUT {
+		panic(fmt.Sprintf("unknown direction %s", direction))
+	}
+	return append(append([]byte{}, prefix...), direction...)
+}
+
+func genRateLimitKey(token string, direction string, limit string) []byte {
+	return append(append(genDirectionKey(genTokenKey(RATE_LIMITS_KEY, token), direction), '_'), limit...)
+}
+
+// the global usage of a period has no address
+func genRateLimitUsedKey(token string, direction string, period uint64, orbsAddr []byte) []byte {
+	key := append(append(genDirectionKey(genTokenKey(RATE_LIMIT_USED_KEY, token), direction), '_'), _uint64ToBytes(period)...)
+	return append(key, orbsAddr...)
+}
+
+/***
+ * state layout : transfer state keys are "_v<layout>_E<epoch>_<namespace>_" followed by fixed length parts, a registered token
+ * adds "T<token>_" before the namespace. namespaces contain no "_" and do not start with "T" so no key of one token, namespace
+ * or epoch is a prefix of a key of another.
+ * a layout 1 deployment is migrated in constant cost, its counters are moved and its inbound tuid flags
+ * are still honored during the first epoch, until the first reset.
+ * a layout 1 deployment has no owner, it is migrated by the layout migration owner of the release, which becomes its owner.
+ */
+
+// hex encoded orbs address, set in a release that migrates a layout 1 deployment
+var LAYOUT_MIGRATION_OWNER_ADDR = ""
+
+func getStateLayoutVersion() uint32 {
+	return state.ReadUint32(STATE_LAYOUT_VERSION_KEY)
+}
+
+func getStateEpoch() uint64 {
+	return state.ReadUint64(STATE_EPOCH_KEY)
+}
+
+func migrateStateLayout() {
+	migrationOwner := _getLayoutMigrationOwner()
+	signer := address.GetSignerAddress()
+	if len(migrationOwner) == 0 || !bytes.Equal(migrationOwner, signer) {
+		panic(fmt.Sprintf("only layout migration owner can call migrateStateLayout, signer %x", signer))
+	}
+	if getStateLayoutVersion() != 0 {
+		panic(fmt.Sprintf("state layout is already version %d", getStateLayoutVersion()))
+	}
+	state.WriteBytes(OWNER_KEY, migrationOwner)
+	state.WriteUint32(STATE_LAYOUT_VERSION_KEY, stateLayoutVersion)
+	state.WriteUint64(REQUIRED_CONFIRMATIONS_KEY, defaultRequiredConfirmations)
+
+	setOutTuid(DEFAULT_TOKEN, state.ReadUint64(LEGACY_OUT_TUID_KEY))
+	state.Clear(LEGACY_OUT_TUID_KEY)
+	setInTuidMax(DEFAULT_TOKEN, state.ReadUint64(LEGACY_IN_TUID_MAX_KEY))
+	state.Clear(LEGACY_IN_TUID_MAX_KEY)
+	state.WriteUint32(LEGACY_IN_TUIDS_KEY, 1)
+}
+
+func _getLayoutMigrationOwner() []byte {
+	migrationOwner, err := hex.DecodeString(LAYOUT_MIGRATION_OWNER_ADDR)
+	if err != nil {
+		panic(fmt.Sprintf("layout migration owner %s is not hex encoded: %s", LAYOUT_MIGRATION_OWNER_ADDR, err))
+	}
+	return migrationOwner
+}
+
+func _isLegacyInTuidExists(tuid uint64) bool {
+	if getStateEpoch() != 0 || state.ReadUint32(LEGACY_IN_TUIDS_KEY) == 0 {
+		return false
+	}
+	legacyKey := append(append([]byte{}, LEGACY_IN_TUID_KEY...), new(big.Int).SetUint64(tuid).Bytes()...)
+	return state.ReadUint32(legacyKey) != 0
+}
+
+func _requireStateLayout() {
+	if getStateLayoutVersion() != stateLayoutVersion {
+		panic(fmt.Sprintf("state layout version %d must be migrated to %d", getStateLayoutVersion(), stateLayoutVersion))
+	}
+}
+
+func _epochKey(token string, namespace string, parts ...[]byte) []byte {
+	key := genTokenKey([]byte(fmt.Sprintf("_v%d_E%d_", stateLayoutVersion, getStateEpoch())), token)
+	key = append(key, namespace+"_"...)
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+/***
+ * transfer records : every transfer is kept by its tuid with the orbs block height it was processed at,
+ * and listed per orbs address (the recipient of inbound transfers, the sender of outbound transfers).
+ * a page of a list is the tuids concatenated, 8 bytes big endian each.
+ */
+func getTransferIn(token string, tuid uint64) (ethTxHash string, ethFrom []byte, orbsTo []byte, ethValue string, orbsAmount uint64, orbsBlockHeight uint64) {
+	token = _normalizeToken(token)
+	tuidBytes := _uint64ToBytes(tuid)
+	return state.ReadString(genInTransferKey(token, tuidBytes, "TxHash")),
+		state.ReadBytes(genInTransferKey(token, tuidBytes, "From")),
+		state.ReadBytes(genInTransferKey(token, tuidBytes, "To")),
+		_readBigInt(genInTransferKey(token, tuidBytes, "Value")).String(),
+		state.ReadUint64(genInTransferKey(token, tuidBytes, "Amount")),
+		state.ReadUint64(genInTransferKey(token, tuidBytes, "BlockHeight"))
+}
+
+func getTransferOut(token string, tuid uint64) (orbsFrom []byte, ethTo []byte, orbsAmount uint64, ethDust uint64, orbsBlockHeight uint64) {
+	token = _normalizeToken(token)
+	tuidBytes := _uint64ToBytes(tuid)
+	return state.ReadBytes(genOutTransferKey(token, tuidBytes, "From")),
+		state.ReadBytes(genOutTransferKey(token, tuidBytes, "To")),
+		state.ReadUint64(genOutTransferKey(token, tuidBytes, "Amount")),
+		state.ReadUint64(genOutTransferKey(token, tuidBytes, "Dust")),
+		state.ReadUint64(genOutTransferKey(token, tuidBytes, "BlockHeight"))
+}
+
+func getTransfersInCount(token string, orbsAddr []byte) uint64 {
+	return state.ReadUint64(_epochKey(_normalizeToken(token), IN_ADDR_TRANSFERS_COUNT_NS, orbsAddr))
+}
+
+func getTransfersOutCount(token string, orbsAddr []byte) uint64 {
+	return state.ReadUint64(_epochKey(_normalizeToken(token), OUT_ADDR_TRANSFERS_COUNT_NS, orbsAddr))
+}
+
+func getTransfersIn(token string, orbsAddr []byte, offset uint64, limit uint64) []byte {
+	return _listAddrTransfers(_normalizeToken(token), IN_ADDR_TRANSFERS_NS, IN_ADDR_TRANSFERS_COUNT_NS, orbsAddr, offset, limit)
+}
+
+func getTransfersOut(token string, orbsAddr []byte, offset uint64, limit uint64) []byte {
+	return _listAddrTransfers(_normalizeToken(token), OUT_ADDR_TRANSFERS_NS, OUT_ADDR_TRANSFERS_COUNT_NS, orbsAddr, offset, limit)
+}
+
+func _recordTransferIn(token string, tuid uint64, ethTxHash string, ethFrom []byte, orbsTo []byte, ethValue *big.Int, orbsAmount uint64) {
+	tuidBytes := _uint64ToBytes(tuid)
+	state.WriteString(genInTransferKey(token, tuidBytes, "TxHash"), ethTxHash)
+	state.WriteBytes(genInTransferKey(token, tuidBytes, "From"), ethFrom)
+	state.WriteBytes(genInTransferKey(token, tuidBytes, "To"), orbsTo)
+	state.WriteBytes(genInTransferKey(token, tuidBytes, "Value"), ethValue.Bytes())
+	state.WriteUint64(genInTransferKey(token, tuidBytes, "Amount"), orbsAmount)
+	state.WriteUint64(genInTransferKey(token, tuidBytes, "BlockHeight"), env.GetBlockHeight())
+	_appendAddrTransfer(token, IN_ADDR_TRANSFERS_NS, IN_ADDR_TRANSFERS_COUNT_NS, orbsTo, tuidBytes)
+}
+
+func _recordTransferOut(token string, tuid uint64, orbsFrom []byte, ethTo []byte, orbsAmount uint64, ethDust uint64) {
+	tuidBytes := _uint64ToBytes(tuid)
+	state.WriteBytes(genOutTransferKey(token, tuidBytes, "From"), orbsFrom)
+	state.WriteBytes(genOutTransferKey(token, tuidBytes, "To"), ethTo)
+	state.WriteUint64(genOutTransferKey(token, tuidBytes, "Amount"), orbsAmount)
+	state.WriteUint64(genOutTransferKey(token, tuidBytes, "Dust"), ethDust)
+	state.WriteUint64(genOutTransferKey(token, tuidBytes, "BlockHeight"), env.GetBlockHeight())
+	_appendAddrTransfer(token, OUT_ADDR_TRANSFERS_NS, OUT_ADDR_TRANSFERS_COUNT_NS, orbsFrom, tuidBytes)
+}
+
+func _appendAddrTransfer(token string, listNamespace string, countNamespace string, orbsAddr []byte, tuidBytes []byte) {
+	countKey := _epochKey(token, countNamespace, orbsAddr)
+	count := state.ReadUint64(countKey)
+	state.WriteBytes(_epochKey(token, listNamespace, orbsAddr, _uint64ToBytes(count)), tuidBytes)
+	state.WriteUint64(countKey, count+1)
+}
+
+func _listAddrTransfers(token string, listNamespace string, countNamespace string, orbsAddr []byte, offset uint64, limit uint64) []byte {
+	if limit > maxTransfersPageSize {
+		limit = maxTransfersPageSize
+	}
+	count := state.ReadUint64(_epochKey(token, countNamespace, orbsAddr))
+	page := make([]byte, 0, limit*8)
+	for i := offset; i < count && i-offset < limit; i++ {
+		page = append(page, state.ReadBytes(_epochKey(token, listNamespace, orbsAddr, _uint64ToBytes(i)))...)
+	}
+	return page
+}
+
+func _uint64ToBytes(tuid uint64) []byte {
+	tuidBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(tuidBytes, tuid)
+	return tuidBytes
+}
+
+func genInTransferKey(token string, tuid []byte, field string) []byte {
+	return _epochKey(token, IN_TRANSFER_NS, tuid, []byte(field))
+}
+
+func genOutTransferKey(token string, tuid []byte, field string) []byte {
+	return _epochKey(token, OUT_TRANSFER_NS, tuid, []byte(field))
+}
+
+/***
+ * decimals : the ethereum token may have more decimals than the orbs token, amounts are scaled by 10^(ethDecimals-orbsDecimals).
+ * the part of an inbound value below one orbs unit is kept as dust of the recipient and refunded with its next transfer out.
+ * a transfer out carries its amount in orbs units, the ethereum asb is set with the same scale and pays amount*scale+dust.
+ * the ethereum asb only locks values of up to a uint64 orbs amount, a value that cannot be represented is never minted nor truncated.
+ */
+func getEthDecimals(token string) uint32 {
+	return state.ReadUint32(genTokenKey(ETH_DECIMALS_KEY, _normalizeToken(token)))
+}
+
+func getOrbsDecimals(token string) uint32 {
+	return state.ReadUint32(genTokenKey(ORBS_DECIMALS_KEY, _normalizeToken(token)))
+}
+
+func setDecimals(token string, ethDecimals uint32, orbsDecimals uint32) {
+	_requireRole(ROLE_ADMIN, "setDecimals")
+	token = _normalizeToken(token)
+	if !_isTokenRegistered(token) {
+		panic(fmt.Sprintf("token %s is not registered", token))
+	}
+	if getOutTuid(token) != 0 || getInTuidMax(token) != 0 || getTotalDust(token) != "0" {
+		panic("decimals cannot change once tokens were transferred")
+	}
+	_setDecimals(token, ethDecimals, orbsDecimals)
+}
+
+// the dust of a transfer out is below one orbs unit, so the difference is bounded for it to fit the uint64 ethDust argument
+func _setDecimals(token string, ethDecimals uint32, orbsDecimals uint32) {
+	if orbsDecimals > ethDecimals {
+		panic(fmt.Sprintf("orbs decimals %d must not exceed ethereum decimals %d", orbsDecimals, ethDecimals))
+	}
+	if ethDecimals-orbsDecimals > maxDecimalsDifference {
+		panic(fmt.Sprintf("ethereum decimals %d exceed orbs decimals %d by more than %d", ethDecimals, orbsDecimals, maxDecimalsDifference))
+	}
+	state.WriteUint32(genTokenKey(ETH_DECIMALS_KEY, token), ethDecimals)
+	state.WriteUint32(genTokenKey(ORBS_DECIMALS_KEY, token), orbsDecimals)
+}
+
+func _decimalsScale(token string) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(getEthDecimals(token)-getOrbsDecimals(token))), nil)
+}
+
+func _isOrbsAmount(token string, value *big.Int) bool {
+	return new(big.Int).Quo(value, _decimalsScale(token)).IsUint64()
+}
+
+func _ethToOrbsAmount(token string, value *big.Int) (amount uint64, dust *big.Int) {
+	orbsAmount, dust := new(big.Int).QuoRem(value, _decimalsScale(token), new(big.Int))
+	if !orbsAmount.IsUint64() {
+		panic(fmt.Sprintf("value %s cannot be represented in orbs token units", value))
+	}
+	return orbsAmount.Uint64(), dust
+}
+
+func getDust(token string, orbsAddr []byte) string {
+	return _readBigInt(genDustKey(_normalizeToken(token), orbsAddr)).String()
+}
+
+func getTotalDust(token string) string {
+	return _readBigInt(genTokenKey(TOTAL_DUST_KEY, _normalizeToken(token))).String()
+}
+
+func genDustKey(token string, orbsAddr []byte) []byte {
+	return append(genTokenKey(DUST_KEY, token), orbsAddr...)
+}
+
+func _addDust(token string, orbsAddr []byte, dust *big.Int) {
+	if dust.Sign() == 0 {
+		return
+	}
+	dustKey := genDustKey(token, orbsAddr)
+	totalDustKey := genTokenKey(TOTAL_DUST_KEY, token)
+	state.WriteBytes(dustKey, new(big.Int).Add(_readBigInt(dustKey), dust).Bytes())
+	state.WriteBytes(totalDustKey, new(big.Int).Add(_readBigInt(totalDustKey), dust).Bytes())
+}
+
+func _clearDust(token string, orbsAddr []byte, dust *big.Int) {
+	if dust.Sign() == 0 {
+		return
+	}
+	totalDustKey := genTokenKey(TOTAL_DUST_KEY, token)
+	state.Clear(genDustKey(token, orbsAddr))
+	state.WriteBytes(totalDustKey, new(big.Int).Sub(_readBigInt(totalDustKey), dust).Bytes())
+}
+
+func _readBigInt(key []byte) *big.Int {
+	return new(big.Int).SetBytes(state.ReadBytes(key))
+}
+
+/***
+ * access control : the owner is the signer of the deployment, it may hand over ownership in two steps
+ * (transferOwnership by the owner then claimOwnership by the new owner) and grant or revoke roles.
+ * the owner holds every role implicitly.
+ */
+func getOwner() []byte {
+	return state.ReadBytes(OWNER_KEY)
+}
+
+func getPendingOwner() []byte {
+	return state.ReadBytes(PENDING_OWNER_KEY)
+}
+
+func transferOwnership(newOwner []byte) {
+	_requireOwner("transferOwnership")
+	address.ValidateAddress(newOwner)
+	state.WriteBytes(PENDING_OWNER_KEY, newOwner)
+}
+
+func claimOwnership() {
+	pendingOwner := getPendingOwner()
+	signer := address.GetSignerAddress()
+	if len(pendingOwner) == 0 || !bytes.Equal(pendingOwner, signer) {
+		panic(fmt.Sprintf("only pending owner can call claimOwnership, signer %x", signer))
+	}
+	state.WriteBytes(OWNER_KEY, pendingOwner)
+	state.Clear(PENDING_OWNER_KEY)
+}
+
+func grantRole(role string, addr []byte) {
+	_requireOwner("grantRole")
+	_validateRole(role)
+	address.ValidateAddress(addr)
+	state.WriteUint32(genRoleKey(role, addr), 1)
+}
+
+func revokeRole(role string, addr []byte) {
+	_requireOwner("revokeRole")
+	_validateRole(role)
+	state.Clear(genRoleKey(role, addr))
+}
+
+func hasRole(role string, addr []byte) uint32 {
+	_validateRole(role)
+	if _isOwner(addr) || state.ReadUint32(genRoleKey(role, addr)) != 0 {
+		return 1
+	}
+	return 0
+}
+
+func genRoleKey(role string, addr []byte) []byte {
+	key := append([]byte{}, ROLE_KEY...)
+	key = append(key, role...)
+	return append(key, addr...)
+}
+
+func _validateRole(role string) {
+	if role != ROLE_ADMIN && role != ROLE_OPERATOR {
+		panic(fmt.Sprintf("unknown role %s", role))
+	}
+}
+
+func _isOwner(addr []byte) bool {
+	owner := getOwner()
+	return len(owner) != 0 && bytes.Equal(owner, addr)
+}
+
+func _requireOwner(method string) {
+	signer := address.GetSignerAddress()
+	if !_isOwner(signer) {
+		panic(fmt.Sprintf("only owner can call %s, signer %x", method, signer))
+	}
+}
+
+func _requireRole(role string, method string) {
+	signer := address.GetSignerAddress()
+	if hasRole(role, signer) == 0 {
+		panic(fmt.Sprintf("only %s can call %s, signer %x", role, method, signer))
 	}
-	setInTuidMax(0)
 }
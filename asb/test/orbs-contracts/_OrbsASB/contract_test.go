@@ -7,12 +7,20 @@
 package main
 
 import (
+	"encoding/hex"
+	"fmt"
 	orbsClient "github.com/orbs-network/orbs-client-sdk-go/orbsclient"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
 	"github.com/stretchr/testify/require"
+	"math"
 	"math/big"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTransferIn_AllGood(t *testing.T) {
@@ -22,7 +30,9 @@ func TestTransferIn_AllGood(t *testing.T) {
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
+		m.MockEnvBlockHeight(100)
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = big.NewInt(42)
@@ -39,7 +49,7 @@ func TestTransferIn_AllGood(t *testing.T) {
 
 		// assert
 		m.VerifyMocks()
-		require.True(t, isInTuidExists(genInTuidKey(big.NewInt(42).Bytes())))
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 42))
 	})
 
 }
@@ -50,6 +60,7 @@ func TestTransferIn_NoTuid(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = nil
@@ -68,6 +79,7 @@ func TestTransferIn_NoValue(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = big.NewInt(42)
@@ -86,6 +98,7 @@ func TestTransferIn_NegativeValue(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = big.NewInt(42)
@@ -105,6 +118,7 @@ func TestTransferIn_NoOrbsAddress(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = big.NewInt(42)
@@ -125,9 +139,10 @@ func TestTransferIn_TuidAlreadyUsed(t *testing.T) {
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
-		setInTuid(genInTuidKey(big.NewInt(42).Bytes()))
+		setInTuid(DEFAULT_TOKEN, 42)
 
 		// prepare
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
 		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
 			v := out.(*EthTransferredOut)
 			v.Tuid = big.NewInt(42)
@@ -142,6 +157,137 @@ func TestTransferIn_TuidAlreadyUsed(t *testing.T) {
 	})
 }
 
+func TestTransferIn_RequiresConfirmations(t *testing.T) {
+	earlyTxid := "cccc"
+	finalTxid := "dddd"
+	owner := createOrbsAccount()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		setRequiredConfirmations(20)
+		m.MockEthereumGetBlockNumber(1000)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), earlyTxid, "EthTransferredOut", 990, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(42)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(17)
+		})
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), finalTxid, "EthTransferredOut", 980, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(43)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(17)
+		})
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(17))
+
+		// call
+		require.PanicsWithValue(t, "ethereum tx cccc at block 990 is not final at block 1000, retry after block 1010 (10 more blocks)", func() {
+			transferIn(earlyTxid)
+		}, "should panic because log is not final")
+		transferIn(finalTxid)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 20, getRequiredConfirmations())
+		require.False(t, isInTuidExists(DEFAULT_TOKEN, 42), "early submission can be retried")
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 43))
+	})
+}
+
+func TestTransferInBatch_SkipsWithoutReverting(t *testing.T) {
+	orbsUserAddress := createOrbsAccount()
+	logs := []struct {
+		txid  string
+		block int
+		tuid  int64
+		to    [20]byte
+		value *big.Int
+	}{
+		{"a1", 0, 42, orbsUserAddress, big.NewInt(17)},
+		{"a2", 0, 7, orbsUserAddress, big.NewInt(5)},
+		{"a3", 0, 43, orbsUserAddress, big.NewInt(-1)},
+		{"a4", 0, 44, [20]byte{}, big.NewInt(5)},
+		{"a5", 10, 45, orbsUserAddress, big.NewInt(5)},
+		{"a6", 0, 46, orbsUserAddress, big.NewInt(3)},
+		{"a7", 0, 47, orbsUserAddress, new(big.Int).Lsh(big.NewInt(1), 64)},
+	}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		setInTuid(DEFAULT_TOKEN, 7)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		for i := range logs {
+			log := logs[i]
+			m.MockEthereumLog(getAsbAddr(), getAsbAbi(), log.txid, "EthTransferredOut", log.block, 0, func(out interface{}) {
+				v := out.(*EthTransferredOut)
+				v.Tuid = big.NewInt(log.tuid)
+				v.To = log.to
+				v.Value = log.value
+			})
+		}
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(17))
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(3))
+
+		// call
+		statuses := transferInBatch("a1,a2,a3,a4,a5,missing,a1,a6,a7")
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, strings.Join([]string{TRANSFER_IN_MINTED, TRANSFER_IN_DUPLICATE, TRANSFER_IN_BAD_VALUE, TRANSFER_IN_BAD_ADDRESS,
+			TRANSFER_IN_NOT_FINAL, TRANSFER_IN_NOT_FOUND, TRANSFER_IN_DUPLICATE, TRANSFER_IN_MINTED, TRANSFER_IN_BAD_VALUE}, ","), statuses)
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 42))
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 46))
+		for _, tuid := range []uint64{43, 44, 45, 47} {
+			require.False(t, isInTuidExists(DEFAULT_TOKEN, tuid), "skipped tuid %d is not spent", tuid)
+		}
+		require.EqualValues(t, 2, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.Panics(t, func() {
+			transferInBatch(strings.Repeat("a1,", maxTransferInBatchSize) + "a1")
+		}, "should panic because the batch is too large")
+	})
+}
+
+func TestTransferInBatch_LimitsAndFeesPerHash(t *testing.T) {
+	orbsUserAddress := createOrbsAccount()
+	values := []int64{5, 200, 50, 50}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		m.MockEnvBlockTimestamp(int(time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC).UnixNano()))
+		_mockTreasuryInTests(m, AnAddress())
+		setFees(DEFAULT_TOKEN, DIRECTION_IN, 10, 0)
+		setRateLimits(DEFAULT_TOKEN, DIRECTION_IN, 100, 0, 90, 3600)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		for i := range values {
+			i := i
+			m.MockEthereumLog(getAsbAddr(), getAsbAbi(), fmt.Sprintf("b%d", i+1), "EthTransferredOut", 0, 0, func(out interface{}) {
+				v := out.(*EthTransferredOut)
+				v.Tuid = big.NewInt(int64(42 + i))
+				v.To = orbsUserAddress
+				v.Value = big.NewInt(values[i])
+			})
+		}
+		m.MockServiceCallMethod(getTokenContract(), "asbMintFee", nil, uint64(5))
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(40))
+		m.MockServiceCallMethod(getTokenContract(), "asbMintFee", nil, uint64(10))
+
+		// call
+		statuses := transferInBatch("b1,b2,b3,b4")
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, strings.Join([]string{TRANSFER_IN_FEE_ONLY, TRANSFER_IN_RATE_LIMITED, TRANSFER_IN_MINTED, TRANSFER_IN_RATE_LIMITED}, ","), statuses)
+		require.EqualValues(t, 15, getCollectedFees(DEFAULT_TOKEN, DIRECTION_IN))
+		_, _, usedGlobal, _, _ := getRateLimitUsage(DEFAULT_TOKEN, DIRECTION_IN, orbsUserAddress[:])
+		require.EqualValues(t, 55, usedGlobal)
+		require.EqualValues(t, 2, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]), "rate limited hashes are not spent")
+	})
+}
+
 func TestTransferOut_AllGood(t *testing.T) {
 	amount := uint64(17)
 	ethAddr := AnAddress()
@@ -150,10 +296,11 @@ func TestTransferOut_AllGood(t *testing.T) {
 
 	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
 		_init() // start the asb contract // todo  v1 open bug
+		m.MockEnvBlockHeight(100)
 
 		// what is expected to be called
-		tuid := safeuint64.Add(getOutTuid(), 1)
-		m.MockEmitEvent(OrbsTransferredOut, tuid, orbsUserAddress[:], ethAddr, big.NewInt(17).Uint64())
+		tuid := safeuint64.Add(getOutTuid(DEFAULT_TOKEN), 1)
+		m.MockEmitEvent(OrbsTransferredOut, tuid, orbsUserAddress[:], ethAddr, big.NewInt(17).Uint64(), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
 		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], amount)
 
 		// call
@@ -161,7 +308,7 @@ func TestTransferOut_AllGood(t *testing.T) {
 
 		// assert
 		m.VerifyMocks()
-		require.Equal(t, uint64(1), getOutTuid())
+		require.Equal(t, uint64(1), getOutTuid(DEFAULT_TOKEN))
 	})
 }
 
@@ -172,27 +319,975 @@ func TestReset(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init() // start the asb contracat
 
-		setOutTuid(maxOut)
+		setOutTuid(DEFAULT_TOKEN, maxOut)
 		for i := int64(0); i < maxIn; i++ {
 			if i%54 == 0 {
 				continue // just as not to have all of them
 			}
-			setInTuid(genInTuidKey(big.NewInt(i).Bytes()))
+			setInTuid(DEFAULT_TOKEN, uint64(i))
 		}
-		setInTuidMax(uint64(maxIn))
+		setInTuidMax(DEFAULT_TOKEN, uint64(maxIn))
 
 		// call
 		resetContract()
 
 		// assert
-		require.Equal(t, uint64(0), getOutTuid())
-		require.Equal(t, uint64(0), getInTuidMax())
+		require.Equal(t, uint64(0), getOutTuid(DEFAULT_TOKEN))
+		require.Equal(t, uint64(0), getInTuidMax(DEFAULT_TOKEN))
 		for i := int64(0); i < maxIn; i++ {
-			require.False(t, isInTuidExists(genInTuidKey(big.NewInt(i).Bytes())), "tuid should be empty %d", i)
+			require.False(t, isInTuidExists(DEFAULT_TOKEN, uint64(i)), "tuid should be empty %d", i)
+		}
+	})
+}
+
+func TestReset_ConstantCost(t *testing.T) {
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		setInTuid(DEFAULT_TOKEN, math.MaxUint64)
+		setInTuidMax(DEFAULT_TOKEN, math.MaxUint64)
+		_recordTransferOut(DEFAULT_TOKEN, 1, orbsUserAddress[:], AnAddress(), 10, 10)
+
+		// call
+		resetContract()
+
+		// assert
+		require.EqualValues(t, 1, getStateEpoch())
+		require.Equal(t, uint64(0), getInTuidMax(DEFAULT_TOKEN))
+		require.False(t, isInTuidExists(DEFAULT_TOKEN, math.MaxUint64))
+		require.EqualValues(t, 0, getTransfersOutCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+		orbsFrom, _, _, _, _ := getTransferOut(DEFAULT_TOKEN, 1)
+		require.Empty(t, orbsFrom, "records of the previous epoch are not visible")
+	})
+}
+
+func TestStateLayout_NoKeyCollisions(t *testing.T) {
+	orbsUserAddress := createOrbsAccount()
+	tuids := []uint64{0, 1, 42, 0x4d61785f00000000, math.MaxUint64}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		keys := map[string]string{}
+		addKey := func(key []byte, name string) {
+			other, found := keys[string(key)]
+			require.False(t, found, "key of %s collides with %s", name, other)
+			keys[string(key)] = name
+		}
+		for _, key := range [][]byte{TOKEN_CONTRACT_KEY, ASB_ETH_ADDR_KEY, ASB_ABI_KEY, OWNER_KEY, PENDING_OWNER_KEY,
+			STATE_LAYOUT_VERSION_KEY, STATE_EPOCH_KEY, LEGACY_IN_TUIDS_KEY, LEGACY_OUT_TUID_KEY, LEGACY_IN_TUID_MAX_KEY} {
+			addKey(key, string(key))
 		}
+		addKey(genRoleKey(ROLE_ADMIN, orbsUserAddress[:]), "admin role")
+		addKey(genRoleKey(ROLE_OPERATOR, orbsUserAddress[:]), "operator role")
+		for _, token := range []string{DEFAULT_TOKEN, "00000000000000000000000000000000000000b1", "00000000000000000000000000000000000000b2"} {
+			addKey(genDustKey(token, orbsUserAddress[:]), fmt.Sprintf("dust of token %s", token))
+			addKey(genTokenKey(TOTAL_DUST_KEY, token), fmt.Sprintf("total dust of token %s", token))
+			addKey(genTokenKey(ETH_DECIMALS_KEY, token), fmt.Sprintf("eth decimals of token %s", token))
+			addKey(genTokenKey(ORBS_DECIMALS_KEY, token), fmt.Sprintf("orbs decimals of token %s", token))
+			addKey(genRateLimitKey(token, DIRECTION_IN, "MaxPerTransfer"), fmt.Sprintf("rate limit of token %s", token))
+			addKey(genRateLimitUsedKey(token, DIRECTION_IN, 1, orbsUserAddress[:]), fmt.Sprintf("rate limit usage of token %s", token))
+			if token != DEFAULT_TOKEN {
+				addKey(genTokenKey(TOKEN_REGISTRY_KEY, token), fmt.Sprintf("registry of token %s", token))
+				addKey(genTokenKey(TOKEN_DISABLED_KEY, token), fmt.Sprintf("disabled flag of token %s", token))
+			}
+			for _, epoch := range []uint64{0, 1, 10} {
+				state.WriteUint64(STATE_EPOCH_KEY, epoch)
+				at := fmt.Sprintf("of token %s at epoch %d", token, epoch)
+				addKey(_epochKey(token, OUT_TUID_NS), "out tuid "+at)
+				addKey(_epochKey(token, IN_TUID_MAX_NS), "in tuid max "+at)
+				addKey(_epochKey(token, IN_ADDR_TRANSFERS_COUNT_NS, orbsUserAddress[:]), "in count "+at)
+				addKey(_epochKey(token, OUT_ADDR_TRANSFERS_COUNT_NS, orbsUserAddress[:]), "out count "+at)
+				for _, tuid := range tuids {
+					addKey(genInTuidKey(token, tuid), fmt.Sprintf("in tuid %d %s", tuid, at))
+					addKey(_epochKey(token, IN_ADDR_TRANSFERS_NS, orbsUserAddress[:], _uint64ToBytes(tuid)), fmt.Sprintf("in list entry %d %s", tuid, at))
+					addKey(_epochKey(token, OUT_ADDR_TRANSFERS_NS, orbsUserAddress[:], _uint64ToBytes(tuid)), fmt.Sprintf("out list entry %d %s", tuid, at))
+					for _, field := range []string{"TxHash", "From", "To", "Value", "Amount", "BlockHeight"} {
+						addKey(genInTransferKey(token, _uint64ToBytes(tuid), field), fmt.Sprintf("in transfer %d %s %s", tuid, field, at))
+						addKey(genOutTransferKey(token, _uint64ToBytes(tuid), field), fmt.Sprintf("out transfer %d %s %s", tuid, field, at))
+					}
+				}
+			}
+		}
+
+		// the layout 1 collision, the inbound tuid max marked a tuid as spent
+		state.WriteUint64(STATE_EPOCH_KEY, 0)
+		setInTuidMax(DEFAULT_TOKEN, math.MaxUint64)
+		setOutTuid(DEFAULT_TOKEN, math.MaxUint64)
+		for _, tuid := range tuids {
+			require.False(t, isInTuidExists(DEFAULT_TOKEN, tuid), "tuid %d should not be spent", tuid)
+		}
+	})
+}
+
+func TestMigrateStateLayout(t *testing.T) {
+	txid := "cccc"
+	owner := createOrbsAccount()
+	orbsUserAddress := createOrbsAccount()
+	defer _setLayoutMigrationOwnerInTests(owner)()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		// layout 1 state of a deployed contract
+		state.WriteString(ASB_ABI_KEY, defaultAsbAbi)
+		state.WriteString(TOKEN_CONTRACT_KEY, defaultTokenContract)
+		state.WriteUint64(LEGACY_OUT_TUID_KEY, 7)
+		state.WriteUint32(append(append([]byte{}, LEGACY_IN_TUID_KEY...), big.NewInt(3).Bytes()...), 1)
+		state.WriteUint64(LEGACY_IN_TUID_MAX_KEY, 3)
+		require.Panics(t, func() {
+			transferOut(AnAddress(), 1)
+		}, "should panic because state layout is not migrated")
+
+		// call
+		callExported(PUBLIC, "migrateStateLayout")
+
+		// assert
+		require.EqualValues(t, stateLayoutVersion, getStateLayoutVersion())
+		require.Equal(t, owner[:], getOwner())
+		require.EqualValues(t, 7, getOutTuid(DEFAULT_TOKEN))
+		require.EqualValues(t, 3, getInTuidMax(DEFAULT_TOKEN))
+		require.EqualValues(t, defaultRequiredConfirmations, getRequiredConfirmations())
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 3), "layout 1 tuid stays spent")
+		require.False(t, isInTuidExists(DEFAULT_TOKEN, 4))
+		require.Empty(t, state.ReadBytes(LEGACY_OUT_TUID_KEY))
+		require.Panics(t, func() {
+			callExported(PUBLIC, "migrateStateLayout")
+		}, "should panic because state layout is already migrated")
+
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(3)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(17)
+		})
+		require.Panics(t, func() {
+			transferIn(txid)
+		}, "should panic because layout 1 tuid was spent")
+
+		resetContract()
+		require.False(t, isInTuidExists(DEFAULT_TOKEN, 3), "layout 1 tuids are dropped by reset")
+	})
+}
+
+func TestMigrateStateLayout_OnlyByLayoutMigrationOwner(t *testing.T) {
+	migrationOwner := createOrbsAccount()
+	otherSigner := createOrbsAccount()
+	require.Panics(t, func() {
+		callExported(SYSTEM, "migrateStateLayout")
+	}, "should panic because migration is not a system method")
+
+	InServiceScope(otherSigner[:], nil, func(m Mockery) {
+		require.Panics(t, func() {
+			callExported(PUBLIC, "migrateStateLayout")
+		}, "should panic because no layout migration owner is set")
+	})
+
+	defer _setLayoutMigrationOwnerInTests(migrationOwner)()
+	InServiceScope(otherSigner[:], nil, func(m Mockery) {
+		require.Panics(t, func() {
+			callExported(PUBLIC, "migrateStateLayout")
+		}, "should panic because signer is not the layout migration owner")
+		require.Zero(t, getStateLayoutVersion())
+		require.Empty(t, getOwner(), "a rejected caller does not become owner")
+	})
+}
+
+func TestTransferIn_ValueBoundaries(t *testing.T) {
+	txid := "cccc"
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	tests := []struct {
+		name           string
+		ethDecimals    uint32
+		orbsDecimals   uint32
+		value          *big.Int
+		expectedAmount uint64
+		expectedDust   string
+	}{
+		{"max uint64 at equal decimals", 18, 18, maxUint64, math.MaxUint64, "0"},
+		{"exact orbs units", 18, 6, new(big.Int).Mul(big.NewInt(5), big.NewInt(1000000000000)), 5, "0"},
+		{"orbs units with dust", 18, 6, big.NewInt(5000000000007), 5, "7"},
+		{"dust only", 18, 6, big.NewInt(999999999999), 0, "999999999999"},
+		{"above uint64 scaled down", 18, 0, new(big.Int).Mul(maxUint64, big.NewInt(1000000000000000000)), math.MaxUint64, "0"},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			orbsUserAddress := createOrbsAccount()
+
+			InServiceScope(nil, nil, func(m Mockery) {
+				_init()
+				m.MockEnvBlockHeight(100)
+				_setDecimals(DEFAULT_TOKEN, cTest.ethDecimals, cTest.orbsDecimals)
+				m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+				m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+					v := out.(*EthTransferredOut)
+					v.Tuid = big.NewInt(42)
+					v.To = orbsUserAddress
+					v.Value = cTest.value
+				})
+				if cTest.expectedAmount > 0 {
+					m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], cTest.expectedAmount)
+				}
+
+				// call
+				transferIn(txid)
+
+				// assert
+				m.VerifyMocks()
+				require.True(t, isInTuidExists(DEFAULT_TOKEN, 42))
+				require.Equal(t, cTest.expectedDust, getDust(DEFAULT_TOKEN, orbsUserAddress[:]))
+				require.Equal(t, cTest.expectedDust, getTotalDust(DEFAULT_TOKEN))
+			})
+		})
+	}
+}
+
+func TestTransferIn_ValueNotRepresentable(t *testing.T) {
+	txid := "cccc"
+	orbsUserAddress := createOrbsAccount()
+	tests := []struct {
+		name         string
+		ethDecimals  uint32
+		orbsDecimals uint32
+		value        *big.Int
+	}{
+		{"max uint64 plus one at equal decimals", 18, 18, new(big.Int).Add(new(big.Int).SetUint64(math.MaxUint64), big.NewInt(1))},
+		{"max uint256", 18, 6, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			InServiceScope(nil, nil, func(m Mockery) {
+				_init()
+				_setDecimals(DEFAULT_TOKEN, cTest.ethDecimals, cTest.orbsDecimals)
+				m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+				m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+					v := out.(*EthTransferredOut)
+					v.Tuid = big.NewInt(42)
+					v.To = orbsUserAddress
+					v.Value = cTest.value
+				})
+
+				m.MockEnvBlockHeight(100)
+
+				// call
+				require.PanicsWithValue(t, fmt.Sprintf("value %s cannot be represented in orbs token units", cTest.value), func() {
+					transferIn(txid)
+				}, "should panic because the ethereum asb never locks such a value")
+
+				// assert
+				require.False(t, isInTuidExists(DEFAULT_TOKEN, 42))
+				require.Zero(t, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+			})
+		})
+	}
+}
+
+func TestTransferOut_ScaledWithDustRefund(t *testing.T) {
+	ethAddr := AnAddress()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		_setDecimals(DEFAULT_TOKEN, 18, 6)
+		_addDust(DEFAULT_TOKEN, orbsUserAddress[:], big.NewInt(7))
+
+		// what is expected to be called
+		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], uint64(5))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), orbsUserAddress[:], ethAddr, uint64(5), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(7))
+
+		// call
+		transferOut(ethAddr, 5)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, "0", getDust(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.Equal(t, "0", getTotalDust(DEFAULT_TOKEN))
+	})
+}
+
+func TestTransferOut_DustKeptWithoutAmount(t *testing.T) {
+	ethAddr := AnAddress()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		_setDecimals(DEFAULT_TOKEN, 18, 6)
+		_addDust(DEFAULT_TOKEN, orbsUserAddress[:], big.NewInt(7))
+
+		// call
+		require.PanicsWithValue(t, "transfer out of zero amount", func() {
+			transferOut(ethAddr, 0)
+		}, "should panic because dust is only refunded with an amount")
+
+		// assert
+		require.Equal(t, "7", getDust(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.Equal(t, uint64(0), getOutTuid(DEFAULT_TOKEN))
+	})
+}
+
+func TestTransferOut_AmountInOrbsUnits(t *testing.T) {
+	ethAddr := AnAddress()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		_setDecimals(DEFAULT_TOKEN, 18, 0)
+		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], uint64(1000000))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), orbsUserAddress[:], ethAddr, uint64(1000000), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
+
+		// call
+		transferOut(ethAddr, 1000000)
+
+		// assert
+		m.VerifyMocks()
+		_, _, orbsAmount, ethDust, _ := getTransferOut(DEFAULT_TOKEN, 1)
+		require.EqualValues(t, 1000000, orbsAmount, "a million tokens of 18 ethereum decimals are not capped by a uint64 ethereum value")
+		require.Zero(t, ethDust)
+	})
+}
+
+func TestSetDecimals(t *testing.T) {
+	owner := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+		require.EqualValues(t, defaultEthDecimals, getEthDecimals(DEFAULT_TOKEN))
+		require.EqualValues(t, defaultOrbsDecimals, getOrbsDecimals(DEFAULT_TOKEN))
+
+		// call
+		setDecimals(DEFAULT_TOKEN, 18, 8)
+
+		// assert
+		require.EqualValues(t, 18, getEthDecimals(DEFAULT_TOKEN))
+		require.EqualValues(t, 8, getOrbsDecimals(DEFAULT_TOKEN))
+		require.Equal(t, big.NewInt(10000000000), _decimalsScale(DEFAULT_TOKEN))
+		require.Panics(t, func() {
+			setDecimals(DEFAULT_TOKEN, 6, 18)
+		}, "should panic because orbs decimals exceed ethereum decimals")
+		require.Panics(t, func() {
+			setDecimals(DEFAULT_TOKEN, 24, 5)
+		}, "should panic because the dust of a transfer out would not fit the event")
+		setOutTuid(DEFAULT_TOKEN, 1)
+		require.Panics(t, func() {
+			setDecimals(DEFAULT_TOKEN, 18, 18)
+		}, "should panic because tokens were already transferred")
+	})
+}
+
+func TestTransferIn_RecordKept(t *testing.T) {
+	txid := "cccc"
+	ethUserAddress := [20]byte{0xe1}
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(1234)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(42)
+			v.From = ethUserAddress
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(17)
+		})
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(17))
+
+		// call
+		transferIn(txid)
+
+		// assert
+		ethTxHash, ethFrom, orbsTo, ethValue, orbsAmount, orbsBlockHeight := getTransferIn(DEFAULT_TOKEN, 42)
+		require.Equal(t, txid, ethTxHash)
+		require.Equal(t, ethUserAddress[:], ethFrom)
+		require.Equal(t, orbsUserAddress[:], orbsTo)
+		require.Equal(t, "17", ethValue)
+		require.EqualValues(t, 17, orbsAmount)
+		require.EqualValues(t, 1234, orbsBlockHeight)
+		require.EqualValues(t, 1, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.Equal(t, _uint64ToBytes(42), getTransfersIn(DEFAULT_TOKEN, orbsUserAddress[:], 0, 10))
+	})
+}
+
+func TestTransferIn_TuidBeyondUint64(t *testing.T) {
+	txid := "cccc"
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = new(big.Int).Lsh(big.NewInt(1), 64)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(17)
+		})
+
+		// call
+		require.Panics(t, func() {
+			transferIn(txid)
+		}, "should panic because tuid cannot be recorded")
 	})
 }
 
+func TestTransferOut_RecordsListedByAddress(t *testing.T) {
+	ethAddr := AnAddress()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(777)
+		for i := uint64(1); i <= 5; i++ {
+			m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], i)
+			m.MockEmitEvent(OrbsTransferredOut, i, orbsUserAddress[:], ethAddr, i, _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
+		}
+
+		// call
+		for i := uint64(1); i <= 5; i++ {
+			transferOut(ethAddr, i)
+		}
+
+		// assert
+		orbsFrom, ethTo, orbsAmount, ethDust, orbsBlockHeight := getTransferOut(DEFAULT_TOKEN, 3)
+		require.Equal(t, orbsUserAddress[:], orbsFrom)
+		require.Equal(t, ethAddr, ethTo)
+		require.EqualValues(t, 3, orbsAmount)
+		require.Zero(t, ethDust)
+		require.EqualValues(t, 777, orbsBlockHeight)
+		require.EqualValues(t, 5, getTransfersOutCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.EqualValues(t, 0, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]), "directions are listed separately")
+		require.Equal(t, append(_uint64ToBytes(2), _uint64ToBytes(3)...), getTransfersOut(DEFAULT_TOKEN, orbsUserAddress[:], 1, 2))
+		require.Equal(t, _uint64ToBytes(5), getTransfersOut(DEFAULT_TOKEN, orbsUserAddress[:], 4, 10), "last page is partial")
+		require.Empty(t, getTransfersOut(DEFAULT_TOKEN, orbsUserAddress[:], 5, 10), "page beyond the list is empty")
+		require.Len(t, getTransfersOut(DEFAULT_TOKEN, orbsUserAddress[:], 0, 1000), 5*8, "large limit returns the whole list")
+	})
+}
+
+func TestPause_PerDirection(t *testing.T) {
+	txid := "cccc"
+	owner := createOrbsAccount()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+
+		// call
+		pauseTransfers(DIRECTION_IN)
+
+		// assert
+		require.EqualValues(t, 1, isPaused(DIRECTION_IN))
+		require.EqualValues(t, 0, isPaused(DIRECTION_OUT))
+		require.Panics(t, func() {
+			transferIn(txid)
+		}, "should panic because transfer in is paused")
+		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, owner[:], uint64(5))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), owner[:], orbsUserAddress[:], uint64(5), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
+		transferOut(orbsUserAddress[:], 5)
+
+		pauseTransfers(DIRECTION_OUT)
+		require.Panics(t, func() {
+			transferOut(orbsUserAddress[:], 5)
+		}, "should panic because transfer out is paused")
+		resumeTransfers(DIRECTION_IN)
+		require.EqualValues(t, 0, isPaused(DIRECTION_IN))
+		require.Panics(t, func() {
+			pauseTransfers("sideways")
+		}, "should panic because direction is unknown")
+	})
+}
+
+func TestPause_OperatorPausesAdminResumes(t *testing.T) {
+	owner := createOrbsAccount()
+	operator := createOrbsAccount()
+	stranger := createOrbsAccount()
+
+	InServiceScope(operator[:], nil, func(m Mockery) {
+		state.WriteBytes(OWNER_KEY, owner[:])
+		state.WriteUint32(genRoleKey(ROLE_OPERATOR, operator[:]), 1)
+
+		// call
+		pauseTransfers(DIRECTION_OUT)
+
+		// assert
+		require.EqualValues(t, 1, isPaused(DIRECTION_OUT))
+		require.Panics(t, func() {
+			resumeTransfers(DIRECTION_OUT)
+		}, "should panic because only admin resumes")
+		require.Panics(t, func() {
+			setRateLimits(DEFAULT_TOKEN, DIRECTION_OUT, 1, 0, 0, 0)
+		}, "should panic because only admin sets rate limits")
+	})
+
+	InServiceScope(stranger[:], nil, func(m Mockery) {
+		state.WriteBytes(OWNER_KEY, owner[:])
+
+		// call
+		require.Panics(t, func() {
+			pauseTransfers(DIRECTION_IN)
+		}, "should panic because signer is not authorized")
+	})
+}
+
+func TestRateLimits_TransferOut(t *testing.T) {
+	ethAddr := AnAddress()
+	periodInSeconds := uint64(3600)
+	aPeriodStart := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		m.MockEnvBlockTimestamp(int(aPeriodStart.UnixNano()))
+		setRateLimits(DEFAULT_TOKEN, DIRECTION_OUT, 50, 80, 100, periodInSeconds)
+		state.WriteUint64(genRateLimitUsedKey(DEFAULT_TOKEN, DIRECTION_OUT, _currentRatePeriod(periodInSeconds), nil), 10)
+		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], uint64(50))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), orbsUserAddress[:], ethAddr, uint64(50), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
+
+		// call
+		require.Panics(t, func() {
+			transferOut(ethAddr, 51)
+		}, "should panic because of the per transfer limit")
+		transferOut(ethAddr, 50)
+		require.Panics(t, func() {
+			transferOut(ethAddr, 31)
+		}, "should panic because of the per address limit")
+
+		// assert
+		period, usedByAddress, usedGlobal, remainingForAddress, remainingGlobal := getRateLimitUsage(DEFAULT_TOKEN, DIRECTION_OUT, orbsUserAddress[:])
+		require.EqualValues(t, uint64(aPeriodStart.Unix())/periodInSeconds, period)
+		require.EqualValues(t, 50, usedByAddress)
+		require.EqualValues(t, 60, usedGlobal)
+		require.EqualValues(t, 30, remainingForAddress)
+		require.EqualValues(t, 40, remainingGlobal)
+		_, _, _, _, inRemainingGlobal := getRateLimitUsage(DEFAULT_TOKEN, DIRECTION_IN, orbsUserAddress[:])
+		require.EqualValues(t, uint64(math.MaxUint64), inRemainingGlobal, "directions are limited separately")
+	})
+}
+
+func TestRateLimits_GlobalAcrossAddressesAndPeriods(t *testing.T) {
+	periodInSeconds := uint64(3600)
+	aPeriodStart := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+	user1, user2 := createOrbsAccount(), createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		setRateLimits(DEFAULT_TOKEN, DIRECTION_IN, 0, 0, 100, periodInSeconds)
+		m.MockEnvBlockTimestamp(int(aPeriodStart.UnixNano()))
+
+		// call
+		_useRateLimit(DEFAULT_TOKEN, DIRECTION_IN, user1[:], 70)
+		require.Panics(t, func() {
+			_useRateLimit(DEFAULT_TOKEN, DIRECTION_IN, user2[:], 31)
+		}, "should panic because of the global limit")
+		_useRateLimit(DEFAULT_TOKEN, DIRECTION_IN, user2[:], 30)
+		m.MockEnvBlockTimestamp(int(aPeriodStart.Add(time.Hour).UnixNano()))
+		_useRateLimit(DEFAULT_TOKEN, DIRECTION_IN, user2[:], 100)
+
+		// assert
+		_, usedByAddress, usedGlobal, _, remainingGlobal := getRateLimitUsage(DEFAULT_TOKEN, DIRECTION_IN, user1[:])
+		require.EqualValues(t, 0, usedByAddress, "a new period starts fresh")
+		require.EqualValues(t, 100, usedGlobal)
+		require.EqualValues(t, 0, remainingGlobal)
+		require.Panics(t, func() {
+			setRateLimits(DEFAULT_TOKEN, DIRECTION_IN, 0, 10, 0, 0)
+		}, "should panic because period limits require a period")
+	})
+}
+
+func TestFees_TransferInMintsFeeToTreasury(t *testing.T) {
+	txid := "cccc"
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		_mockTreasuryInTests(m, AnAddress())
+		setFees(DEFAULT_TOKEN, DIRECTION_IN, 3, 250)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(42)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(1000)
+		})
+		m.MockServiceCallMethod(getTokenContract(), "asbMint", nil, orbsUserAddress[:], uint64(972))
+		m.MockServiceCallMethod(getTokenContract(), "asbMintFee", nil, uint64(28))
+
+		// call
+		transferIn(txid)
+
+		// assert
+		m.VerifyMocks()
+		flatFee, basisPoints := getFees(DEFAULT_TOKEN, DIRECTION_IN)
+		require.EqualValues(t, 3, flatFee)
+		require.EqualValues(t, 250, basisPoints)
+		require.EqualValues(t, 28, getCollectedFees(DEFAULT_TOKEN, DIRECTION_IN))
+		require.EqualValues(t, 0, getCollectedFees(DEFAULT_TOKEN, DIRECTION_OUT))
+		_, _, _, _, orbsAmount, _ := getTransferIn(DEFAULT_TOKEN, 42)
+		require.EqualValues(t, 1000, orbsAmount, "the record keeps the amount that crossed the bridge")
+	})
+}
+
+func TestFees_TransferInNotCoveringFeeMintedToTreasury(t *testing.T) {
+	txid := "cccc"
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		_mockTreasuryInTests(m, AnAddress())
+		setFees(DEFAULT_TOKEN, DIRECTION_IN, 10, 0)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog(getAsbAddr(), getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(42)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(5)
+		})
+		m.MockServiceCallMethod(getTokenContract(), "asbMintFee", nil, uint64(5))
+
+		// call
+		transferIn(txid)
+
+		// assert
+		m.VerifyMocks()
+		require.True(t, isInTuidExists(DEFAULT_TOKEN, 42))
+		require.EqualValues(t, 5, getCollectedFees(DEFAULT_TOKEN, DIRECTION_IN))
+	})
+}
+
+func TestFees_RequireTreasury(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_mockTreasuryInTests(m, nil)
+
+		// call
+		require.Panics(t, func() {
+			setFees(DEFAULT_TOKEN, DIRECTION_IN, 3, 0)
+		}, "should panic because the token contract has no treasury")
+		setFees(DEFAULT_TOKEN, DIRECTION_IN, 0, 0)
+
+		// assert
+		flatFee, basisPoints := getFees(DEFAULT_TOKEN, DIRECTION_IN)
+		require.Zero(t, flatFee)
+		require.Zero(t, basisPoints)
+	})
+}
+
+func TestFees_TransferOutEmitsNetAmount(t *testing.T) {
+	ethAddr := AnAddress()
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		_mockTreasuryInTests(m, AnAddress())
+		setFees(DEFAULT_TOKEN, DIRECTION_OUT, 5, 100)
+		m.MockServiceCallMethod(getTokenContract(), "asbBurn", nil, orbsUserAddress[:], uint64(391))
+		m.MockServiceCallMethod(getTokenContract(), "asbCollectFee", nil, orbsUserAddress[:], uint64(9))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), orbsUserAddress[:], ethAddr, uint64(391), _getTokenAsbAddrBytes(DEFAULT_TOKEN), uint64(0))
+
+		// call
+		transferOut(ethAddr, 400)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 9, getCollectedFees(DEFAULT_TOKEN, DIRECTION_OUT))
+		_, _, orbsAmount, _, _ := getTransferOut(DEFAULT_TOKEN, 1)
+		require.EqualValues(t, 391, orbsAmount)
+		require.Panics(t, func() {
+			transferOut(ethAddr, 5)
+		}, "should panic because amount does not cover the fee")
+		require.Panics(t, func() {
+			setFees(DEFAULT_TOKEN, DIRECTION_OUT, 0, maxFeeBasisPoints+1)
+		}, "should panic because basis points exceed the whole amount")
+	})
+}
+
+func TestTransferInToken_RoutedByEmittingContract(t *testing.T) {
+	txid := "cccc"
+	otherAsbAddr := "0x00000000000000000000000000000000000000B1"
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		registerToken(otherAsbAddr, "OtherToken", 18, 6)
+		token := _normalizeToken(otherAsbAddr)
+		m.MockEthereumGetBlockNumber(defaultRequiredConfirmations)
+		m.MockEthereumLog("0x00000000000000000000000000000000000000b1", getAsbAbi(), txid, "EthTransferredOut", 0, 0, func(out interface{}) {
+			v := out.(*EthTransferredOut)
+			v.Tuid = big.NewInt(42)
+			v.To = orbsUserAddress
+			v.Value = big.NewInt(5000000000007)
+		})
+		m.MockServiceCallMethod("OtherToken", "asbMint", nil, orbsUserAddress[:], uint64(5))
+
+		// call
+		transferInToken(otherAsbAddr, txid)
+
+		// assert
+		m.VerifyMocks()
+		require.True(t, isInTuidExists(token, 42))
+		require.False(t, isInTuidExists(DEFAULT_TOKEN, 42), "tuids are kept per token")
+		require.EqualValues(t, 42, getInTuidMax(token))
+		require.EqualValues(t, 0, getInTuidMax(DEFAULT_TOKEN))
+		require.Equal(t, "7", getDust(otherAsbAddr, orbsUserAddress[:]))
+		require.Equal(t, "0", getDust(DEFAULT_TOKEN, orbsUserAddress[:]))
+		require.EqualValues(t, 1, getTransfersInCount(otherAsbAddr, orbsUserAddress[:]))
+		require.EqualValues(t, 0, getTransfersInCount(DEFAULT_TOKEN, orbsUserAddress[:]))
+	})
+}
+
+func TestTransferOutToken(t *testing.T) {
+	ethAddr := AnAddress()
+	otherAsbAddr := "0x00000000000000000000000000000000000000b1"
+	orbsUserAddress := createOrbsAccount()
+
+	InServiceScope(orbsUserAddress[:], nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		registerToken(otherAsbAddr, "OtherToken", 18, 18)
+		setOutTuid(DEFAULT_TOKEN, 9)
+		otherAsbAddrBytes, _ := hex.DecodeString(otherAsbAddr[2:])
+		m.MockServiceCallMethod("OtherToken", "asbBurn", nil, orbsUserAddress[:], uint64(5))
+		m.MockEmitEvent(OrbsTransferredOut, uint64(1), orbsUserAddress[:], ethAddr, uint64(5), otherAsbAddrBytes, uint64(0))
+
+		// call
+		transferOutToken(otherAsbAddr, ethAddr, 5)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 1, getOutTuid(_normalizeToken(otherAsbAddr)))
+		require.EqualValues(t, 9, getOutTuid(DEFAULT_TOKEN), "tuids are kept per token")
+	})
+}
+
+func TestRegisterToken_Rejections(t *testing.T) {
+	txid := "cccc"
+	owner := createOrbsAccount()
+	defaultAsbAddr := "0x00000000000000000000000000000000000000a1"
+	otherAsbAddr := "0x00000000000000000000000000000000000000b1"
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+		setAsbAddr(defaultAsbAddr)
+		registerToken(otherAsbAddr, "OtherToken", 18, 18)
+
+		// call
+		require.Panics(t, func() {
+			registerToken(otherAsbAddr, "AnotherToken", 18, 18)
+		}, "should panic because the ethereum asb is already registered")
+		require.Panics(t, func() {
+			registerToken(strings.ToUpper(defaultAsbAddr[2:]), "AnotherToken", 18, 18)
+		}, "should panic because the ethereum asb is the bound asb")
+		require.Panics(t, func() {
+			registerToken("0x1234", "AnotherToken", 18, 18)
+		}, "should panic because token is not an ethereum address")
+		require.Panics(t, func() {
+			setAsbAddr(otherAsbAddr)
+		}, "should panic because the ethereum asb is already registered")
+		require.Panics(t, func() {
+			transferInToken("0x00000000000000000000000000000000000000c1", txid)
+		}, "should panic because token is not registered")
+
+		setTokenEnabled(otherAsbAddr, 0)
+		require.EqualValues(t, 0, isTokenEnabled(otherAsbAddr))
+		require.EqualValues(t, 1, isTokenEnabled(DEFAULT_TOKEN))
+		require.Equal(t, "OtherToken", getRegisteredTokenContract(otherAsbAddr))
+		require.Panics(t, func() {
+			transferInToken(otherAsbAddr, txid)
+		}, "should panic because token is disabled")
+		require.Panics(t, func() {
+			transferOutToken(otherAsbAddr, AnAddress(), 1)
+		}, "should panic because token is disabled")
+		setTokenEnabled(otherAsbAddr, 1)
+		require.EqualValues(t, 1, isTokenEnabled(otherAsbAddr))
+	})
+}
+
+func TestInit_SignerIsOwner(t *testing.T) {
+	owner := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		// call
+		_init()
+
+		// assert
+		require.Equal(t, owner[:], getOwner())
+		require.EqualValues(t, 1, hasRole(ROLE_ADMIN, owner[:]))
+		require.EqualValues(t, 1, hasRole(ROLE_OPERATOR, owner[:]))
+	})
+}
+
+func TestAccessControl_UnauthorizedSignerRejected(t *testing.T) {
+	owner := createOrbsAccount()
+	stranger := createOrbsAccount()
+	operator := createOrbsAccount()
+	defer _setLayoutMigrationOwnerInTests(owner)()
+	tests := []struct {
+		name   string
+		signer [20]byte
+		call   func()
+		only   string
+	}{
+		{"setAsbAddr by stranger", stranger, func() { setAsbAddr("0xe46Dd32d653C1dea4F8272b83656534374883654") }, "only admin can call setAsbAddr"},
+		{"setTokenContract by stranger", stranger, func() { setTokenContract("OtherToken") }, "only admin can call setTokenContract"},
+		{"resetContract by stranger", stranger, func() { resetContract() }, "only admin can call resetContract"},
+		{"transferOwnership by stranger", stranger, func() { transferOwnership(stranger[:]) }, "only owner can call transferOwnership"},
+		{"claimOwnership by stranger", stranger, func() { claimOwnership() }, "only pending owner can call claimOwnership"},
+		{"grantRole by stranger", stranger, func() { grantRole(ROLE_ADMIN, stranger[:]) }, "only owner can call grantRole"},
+		{"revokeRole by stranger", stranger, func() { revokeRole(ROLE_ADMIN, owner[:]) }, "only owner can call revokeRole"},
+		{"setAsbAddr by operator", operator, func() { setAsbAddr("0xe46Dd32d653C1dea4F8272b83656534374883654") }, "only admin can call setAsbAddr"},
+		{"setTokenContract by operator", operator, func() { setTokenContract("OtherToken") }, "only admin can call setTokenContract"},
+		{"resetContract by operator", operator, func() { resetContract() }, "only admin can call resetContract"},
+		{"grantRole by operator", operator, func() { grantRole(ROLE_OPERATOR, stranger[:]) }, "only owner can call grantRole"},
+		{"registerToken by stranger", stranger, func() { registerToken("0x00000000000000000000000000000000000000b1", "OtherToken", 18, 18) }, "only admin can call registerToken"},
+		{"setTokenEnabled by operator", operator, func() { setTokenEnabled(DEFAULT_TOKEN, 0) }, "only admin can call setTokenEnabled"},
+		{"setRequiredConfirmations by operator", operator, func() { setRequiredConfirmations(0) }, "only admin can call setRequiredConfirmations"},
+		{"setFees by operator", operator, func() { setFees(DEFAULT_TOKEN, DIRECTION_OUT, 1, 0) }, "only admin can call setFees"},
+		{"setDecimals by operator", operator, func() { setDecimals(DEFAULT_TOKEN, 18, 6) }, "only admin can call setDecimals"},
+		{"migrateStateLayout by stranger", stranger, func() { migrateStateLayout() }, "only layout migration owner can call migrateStateLayout"},
+		{"pauseTransfers by stranger", stranger, func() { pauseTransfers(DIRECTION_IN) }, "only operator or admin can call pauseTransfers"},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			InServiceScope(cTest.signer[:], nil, func(m Mockery) {
+				state.WriteBytes(OWNER_KEY, owner[:])
+				state.WriteUint32(genRoleKey(ROLE_OPERATOR, operator[:]), 1)
+				state.WriteString(ASB_ETH_ADDR_KEY, "0x0")
+				state.WriteString(TOKEN_CONTRACT_KEY, defaultTokenContract)
+				setOutTuid(DEFAULT_TOKEN, 5)
+
+				// call
+				require.PanicsWithValue(t, fmt.Sprintf("%s, signer %x", cTest.only, cTest.signer), cTest.call, "should panic because signer is not authorized")
+
+				// assert
+				require.Equal(t, owner[:], getOwner())
+				require.Empty(t, getPendingOwner())
+				require.Equal(t, "0x0", getAsbAddr())
+				require.Equal(t, defaultTokenContract, getTokenContract())
+				require.EqualValues(t, 5, getOutTuid(DEFAULT_TOKEN))
+				require.EqualValues(t, 0, hasRole(ROLE_ADMIN, stranger[:]))
+				require.EqualValues(t, 0, isPaused(DIRECTION_IN))
+				require.EqualValues(t, 0, getStateLayoutVersion())
+			})
+		})
+	}
+}
+
+func TestAccessControl_AdminCanConfigureButNotGrant(t *testing.T) {
+	owner := createOrbsAccount()
+	admin := createOrbsAccount()
+
+	InServiceScope(admin[:], nil, func(m Mockery) {
+		state.WriteBytes(OWNER_KEY, owner[:])
+		state.WriteUint32(genRoleKey(ROLE_ADMIN, admin[:]), 1)
+
+		// call
+		setAsbAddr("0xe46Dd32d653C1dea4F8272b83656534374883654")
+		setTokenContract("OtherToken")
+
+		// assert
+		require.Equal(t, "0xe46Dd32d653C1dea4F8272b83656534374883654", getAsbAddr())
+		require.Equal(t, "OtherToken", getTokenContract())
+		require.EqualValues(t, 0, hasRole(ROLE_OPERATOR, admin[:]), "roles are distinct")
+		require.Panics(t, func() {
+			grantRole(ROLE_ADMIN, AnAddress())
+		}, "should panic because only owner grants roles")
+	})
+}
+
+func TestAccessControl_GrantAndRevokeRole(t *testing.T) {
+	owner := createOrbsAccount()
+	admin := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+
+		// call
+		grantRole(ROLE_ADMIN, admin[:])
+		require.EqualValues(t, 1, hasRole(ROLE_ADMIN, admin[:]))
+		revokeRole(ROLE_ADMIN, admin[:])
+
+		// assert
+		require.EqualValues(t, 0, hasRole(ROLE_ADMIN, admin[:]))
+		require.Panics(t, func() {
+			grantRole("superuser", admin[:])
+		}, "should panic because role is unknown")
+	})
+}
+
+func TestAccessControl_TwoStepOwnershipTransfer(t *testing.T) {
+	owner := createOrbsAccount()
+	newOwner := createOrbsAccount()
+
+	InServiceScope(owner[:], nil, func(m Mockery) {
+		_init()
+
+		// call
+		transferOwnership(newOwner[:])
+
+		// assert
+		require.Equal(t, owner[:], getOwner(), "owner is kept until the new owner claims")
+		require.Equal(t, newOwner[:], getPendingOwner())
+		require.Panics(t, func() {
+			claimOwnership()
+		}, "should panic because only the pending owner claims")
+	})
+
+	InServiceScope(newOwner[:], nil, func(m Mockery) {
+		state.WriteBytes(OWNER_KEY, owner[:])
+		state.WriteBytes(PENDING_OWNER_KEY, newOwner[:])
+
+		// call
+		claimOwnership()
+
+		// assert
+		require.Equal(t, newOwner[:], getOwner())
+		require.Empty(t, getPendingOwner())
+		require.EqualValues(t, 0, hasRole(ROLE_ADMIN, owner[:]), "previous owner loses its implicit roles")
+	})
+}
+
+func _mockTreasuryInTests(m Mockery, treasury []byte) {
+	m.MockServiceCallMethod(getTokenContract(), "getTreasury", []interface{}{treasury})
+}
+
+// returns a func restoring the previous owner
+func _setLayoutMigrationOwnerInTests(migrationOwner [20]byte) func() {
+	previous := LAYOUT_MIGRATION_OWNER_ADDR
+	LAYOUT_MIGRATION_OWNER_ADDR = hex.EncodeToString(migrationOwner[:])
+	return func() {
+		LAYOUT_MIGRATION_OWNER_ADDR = previous
+	}
+}
+
+// calls a method by its name in the exports of the contract, like a transaction does
+func callExported(exports []interface{}, methodName string, args ...interface{}) (out []interface{}) {
+	for _, method := range exports {
+		value := reflect.ValueOf(method)
+		fullName := runtime.FuncForPC(value.Pointer()).Name()
+		if fullName[strings.LastIndex(fullName, ".")+1:] != methodName {
+			continue
+		}
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			in[i] = reflect.ValueOf(arg)
+		}
+		for _, result := range value.Call(in) {
+			out = append(out, result.Interface())
+		}
+		return out
+	}
+	panic(fmt.Sprintf("method %s is not exported", methodName))
+}
+
 // TODO(v1): talkol - I will move this to be part of the test framework
 func createOrbsAccount() [20]byte {
 	orbsUser, err := orbsClient.CreateAccount()
@@ -14,7 +14,8 @@ import (
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 )
 
-var PUBLIC = sdk.Export(totalSupply, balanceOf, transfer, approve, allowance, transferFrom, asbBind, asbGetAddress, asbMint, asbBurn)
+var PUBLIC = sdk.Export(totalSupply, balanceOf, transfer, approve, allowance, transferFrom, asbBind, asbGetAddress, asbMint, asbBurn,
+	setTreasury, getTreasury, asbMintFee, asbCollectFee)
 var SYSTEM = sdk.Export(_init)
 
 // defaults
@@ -24,6 +25,7 @@ const TOTAL_SUPPLY = 0
 var OWNER_KEY = []byte("_OWNER_KEY_")
 var TOTAL_SUPPLY_KEY = []byte("_TOTAL_SUPPLY_KEY_")
 var ASB_ADDR_KEY = []byte("_ASB_ADDR_KEY_")
+var TREASURY_KEY = []byte("_TREASURY_KEY_")
 
 func _init() {
 	ownerAddress := address.GetSignerAddress()
@@ -119,6 +121,49 @@ func asbBurn(targetAddress []byte, amount uint64) {
 	state.WriteUint64(TOTAL_SUPPLY_KEY, total-amount)
 }
 
+// bridge fees are credited to the treasury, an inbound fee is minted to it and an outbound fee is moved to it instead of burned
+func asbMintFee(amount uint64) {
+	if !bytes.Equal(asbGetAddress(), address.GetCallerAddress()) {
+		panic("only asb contract can call asbMintFee")
+	}
+	treasury := _requireTreasury()
+	targetBalance := state.ReadUint64(treasury)
+	state.WriteUint64(treasury, targetBalance+amount)
+	total := state.ReadUint64(TOTAL_SUPPLY_KEY)
+	state.WriteUint64(TOTAL_SUPPLY_KEY, total+amount)
+}
+
+func asbCollectFee(fromAddress []byte, amount uint64) {
+	if !bytes.Equal(asbGetAddress(), address.GetCallerAddress()) {
+		panic("only asb contract can call asbCollectFee")
+	}
+	address.ValidateAddress(fromAddress)
+	_transferImpl(fromAddress, _requireTreasury(), amount)
+}
+
+func setTreasury(treasuryAddress []byte) {
+	owner := state.ReadBytes(OWNER_KEY)
+	caller := address.GetCallerAddress()
+	if !bytes.Equal(owner, caller) {
+		panic("only owner can call setTreasury")
+	}
+	address.ValidateAddress(treasuryAddress)
+
+	state.WriteBytes(TREASURY_KEY, treasuryAddress)
+}
+
+func getTreasury() []byte {
+	return state.ReadBytes(TREASURY_KEY)
+}
+
+func _requireTreasury() []byte {
+	treasury := getTreasury()
+	if len(treasury) == 0 {
+		panic("bridge fee failed since no treasury is set")
+	}
+	return treasury
+}
+
 func asbBind(asbAddress string) {
 	owner := state.ReadBytes(OWNER_KEY)
 	caller := address.GetCallerAddress()
@@ -295,6 +295,114 @@ func TestBurn_BadAddress(t *testing.T) {
 	})
 }
 
+func TestMintFee_SupplyMatchesBalances(t *testing.T) {
+	owner := createOrbsAddress()
+	asbcontract := createOrbsAddress()
+	target := createOrbsAddress()
+	treasury := createOrbsAddress()
+
+	InServiceScope(owner, asbcontract, func(m Mockery) {
+		state.WriteBytes(ASB_ADDR_KEY, asbcontract)
+		state.WriteBytes(TREASURY_KEY, treasury)
+
+		// call
+		asbMint(target, 972)
+		asbMintFee(28)
+
+		// assert
+		require.EqualValues(t, 972, state.ReadUint64(target))
+		require.EqualValues(t, 28, state.ReadUint64(treasury))
+		require.EqualValues(t, 1000, state.ReadUint64(TOTAL_SUPPLY_KEY), "supply is the bridged amount")
+	})
+}
+
+func TestCollectFee_SupplyMatchesBalances(t *testing.T) {
+	owner := createOrbsAddress()
+	asbcontract := createOrbsAddress()
+	target := createOrbsAddress()
+	treasury := createOrbsAddress()
+
+	InServiceScope(owner, asbcontract, func(m Mockery) {
+		state.WriteUint64(TOTAL_SUPPLY_KEY, 1000)
+		state.WriteUint64(target, 1000)
+		state.WriteBytes(ASB_ADDR_KEY, asbcontract)
+		state.WriteBytes(TREASURY_KEY, treasury)
+
+		// call
+		asbBurn(target, 391)
+		asbCollectFee(target, 9)
+
+		// assert
+		require.EqualValues(t, 600, state.ReadUint64(target))
+		require.EqualValues(t, 9, state.ReadUint64(treasury))
+		require.EqualValues(t, 609, state.ReadUint64(TOTAL_SUPPLY_KEY), "only the net amount leaves the supply")
+		require.Panics(t, func() {
+			asbCollectFee(target, 601)
+		}, "should panic not enough")
+	})
+}
+
+func TestFees_NoTreasury(t *testing.T) {
+	owner := createOrbsAddress()
+	asbcontract := createOrbsAddress()
+	target := createOrbsAddress()
+
+	InServiceScope(owner, asbcontract, func(m Mockery) {
+		state.WriteUint64(target, 10)
+		state.WriteBytes(ASB_ADDR_KEY, asbcontract)
+
+		// call
+		require.Panics(t, func() {
+			asbMintFee(10)
+		}, "should panic no treasury")
+		require.Panics(t, func() {
+			asbCollectFee(target, 10)
+		}, "should panic no treasury")
+		require.EqualValues(t, 0, state.ReadUint64(TOTAL_SUPPLY_KEY))
+	})
+}
+
+func TestFees_WrongCaller(t *testing.T) {
+	owner := createOrbsAddress()
+	asbcontract := createOrbsAddress()
+	caller := createOrbsAddress()
+
+	InServiceScope(owner, caller, func(m Mockery) {
+		state.WriteBytes(OWNER_KEY, owner)
+		state.WriteBytes(ASB_ADDR_KEY, asbcontract)
+		state.WriteBytes(TREASURY_KEY, caller)
+
+		// call
+		require.Panics(t, func() {
+			asbMintFee(10)
+		}, "should panic bad caller")
+		require.Panics(t, func() {
+			asbCollectFee(owner, 10)
+		}, "should panic bad caller")
+		require.Panics(t, func() {
+			setTreasury(caller)
+		}, "should panic bad caller")
+	})
+}
+
+func TestSetTreasury_AllGood(t *testing.T) {
+	owner := createOrbsAddress()
+	treasury := createOrbsAddress()
+
+	InServiceScope(owner, owner, func(m Mockery) {
+		_init()
+
+		// call
+		setTreasury(treasury)
+
+		// assert
+		require.Equal(t, treasury, getTreasury())
+		require.Panics(t, func() {
+			setTreasury([]byte{0, 0, 4, 5})
+		}, "should panic bad address")
+	})
+}
+
 // TODO v1 - talkol when the testing sdk is better this can be uncommented.
 /*func TestBindAsb_AllGood(t *testing.T) {
 	owner := createOrbsAddress()
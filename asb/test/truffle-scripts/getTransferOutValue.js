@@ -0,0 +1,34 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+const tuid = process.env.TUID;
+
+module.exports = async function(done) {
+  try {
+
+    if (!tuid) {
+      throw("missing env variable TUID");
+    }
+
+    const AutonomousSwapBridge = artifacts.require('AutonomousSwapBridge.sol');
+    let instance = await AutonomousSwapBridge.deployed();
+
+    let events = await instance.getPastEvents('EthTransferredOut', {filter: {tuid: tuid}, fromBlock: 0, toBlock: 'latest'});
+    let value = events.length > 0 ? events[0].returnValues.value.toString() : "0";
+
+    console.log(JSON.stringify({
+      Value: value
+    }, null, 2));
+
+    done();
+
+  } catch (e) {
+    console.log(e);
+    done(e);
+  }
+};
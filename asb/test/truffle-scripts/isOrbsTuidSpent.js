@@ -0,0 +1,33 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+const tuid = process.env.TUID;
+
+module.exports = async function(done) {
+  try {
+
+    if (!tuid) {
+      throw("missing env variable TUID");
+    }
+
+    const AutonomousSwapBridge = artifacts.require('AutonomousSwapBridge.sol');
+    let instance = await AutonomousSwapBridge.deployed();
+
+    let spent = await instance.spentOrbsTuids(tuid);
+
+    console.log(JSON.stringify({
+      Spent: spent
+    }, null, 2));
+
+    done();
+
+  } catch (e) {
+    console.log(e);
+    done(e);
+  }
+};
@@ -27,14 +27,19 @@ async function mine() {
     });
 }
 
+// the orbs asb accepts an ethereum log only once this many blocks were mined after it
+const REQUIRED_CONFIRMATIONS = parseInt(process.env.REQUIRED_CONFIRMATIONS || "12");
+
 module.exports = async function(done) {
     console.log("Moving Ganache clock to current time by mining a block every 10 seconds")
     try {
         const now = new Date().getTime() / 1000;
         let latestBlockTime = 0;
-        while (latestBlockTime < now) {
+        let minedBlocks = 0;
+        while (latestBlockTime < now || minedBlocks < REQUIRED_CONFIRMATIONS) {
             await moveTimeBy(10);
             await mine();
+            minedBlocks++;
 
             let latestBlock = await web3.eth.getBlock("latest");
             latestBlockTime = latestBlock.timestamp;
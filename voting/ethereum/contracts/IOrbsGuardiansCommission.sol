@@ -0,0 +1,15 @@
+pragma solidity 0.4.25;
+
+
+interface IOrbsGuardiansCommission {
+
+    event CommissionUpdated(address indexed guardian, uint commissionBasisPoints);
+
+    /// @dev set the share of delegators' participation rewards taken by the guardian. only msg.sender can set it's own commission.
+    /// @param commissionBasisPoints uint the commission in basis points (1/100 of a percent)
+    function setCommission(uint commissionBasisPoints) external;
+
+    /// @dev Returns the commission of a specific guardian in basis points.
+    /// @param guardian address the guardian address
+    function getCommission(address guardian) external view returns (uint);
+}
@@ -0,0 +1,18 @@
+pragma solidity 0.4.25;
+
+
+interface IOrbsRewardRecipients {
+
+    event RewardRecipientSet(address indexed stakeholder, address indexed recipient);
+
+    /// @dev Select the address that will receive your rewards.
+    /// @param recipient address the address that will receive the rewards. If you want to receive them yourself - set it to yourself.
+    function setRewardRecipient(address recipient) external;
+
+    /// @dev returns the address that receives the rewards of the stakeholder, zero if the stakeholder receives them
+    /// @param stakeholder address the address of the stakeholder
+    function getRewardRecipient(address stakeholder)
+        external
+        view
+        returns (address);
+}
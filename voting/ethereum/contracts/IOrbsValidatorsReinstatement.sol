@@ -0,0 +1,25 @@
+pragma solidity 0.4.25;
+
+
+interface IOrbsValidatorsReinstatement {
+
+    event ReinstateVote(address indexed voter, address[] validators, uint voteCounter);
+
+    /// @dev Voting method to select which banned validators you want to reinstate before their vote out ban ends.
+    /// @param validators address[] an array of validators addresses you want to reinstate. In case you want to withdraw your reinstate vote, send an empty array.
+    function voteReinstate(address[] validators) external;
+
+    /// @dev returns reinstate vote pair - validators list and the block number the vote was set.
+    /// @param guardian address the address of the guardian
+    function getCurrentReinstateVote(address guardian)
+        external
+        view
+        returns (address[] validators, uint blockNumber);
+
+    /// @dev returns reinstate vote pair - validators list and the block number the vote was set.
+    ///      same as getCurrentReinstateVote but returns addresses represented as byte20.
+    function getCurrentReinstateVoteBytes20(address guardian)
+        external
+        view
+        returns (bytes20[] validatorsBytes20, uint blockNumber);
+}
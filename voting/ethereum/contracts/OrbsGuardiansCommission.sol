@@ -0,0 +1,56 @@
+pragma solidity 0.4.25;
+
+
+import "./IOrbsGuardians.sol";
+import "./IOrbsGuardiansCommission.sol";
+
+
+contract OrbsGuardiansCommission is IOrbsGuardiansCommission {
+
+    // The version of the current Guardians Commission smart contract.
+    uint public constant VERSION = 1;
+
+    // The maximal commission a guardian can set, in basis points (1/100 of a percent).
+    uint public constant MAX_COMMISSION_BASIS_POINTS = 10000;
+
+    // The guardians contract, commission is kept apart from it so guardians stay registered where they are.
+    IOrbsGuardians public orbsGuardians;
+
+    // Mapping between address and the guardian commission in basis points.
+    mapping(address => uint) internal guardiansCommission;
+
+    /// @dev Check that the caller is a guardian.
+    modifier onlyGuardian() {
+        require(orbsGuardians.isGuardian(msg.sender), "You must be a registered guardian");
+        _;
+    }
+
+    /// @dev Constructor that binds the commission to the guardians contract.
+    /// @param guardians_ IOrbsGuardians The address of the guardians contract.
+    constructor(IOrbsGuardians guardians_) public {
+        require(guardians_ != IOrbsGuardians(0), "Guardians contract address 0");
+        orbsGuardians = guardians_;
+    }
+
+    /// @dev set the share of delegators' participation rewards taken by the guardian. only msg.sender can set it's own commission.
+    /// @param commissionBasisPoints uint the commission in basis points (1/100 of a percent)
+    function setCommission(uint commissionBasisPoints) external onlyGuardian {
+        require(commissionBasisPoints <= MAX_COMMISSION_BASIS_POINTS, "Commission must not exceed MAX_COMMISSION_BASIS_POINTS");
+
+        address sender = msg.sender;
+        guardiansCommission[sender] = commissionBasisPoints;
+
+        emit CommissionUpdated(sender, commissionBasisPoints);
+    }
+
+    /// @dev Returns the commission of a specific guardian in basis points.
+    /// @param guardian address the guardian address
+    function getCommission(address guardian)
+        external
+        view
+        returns (uint)
+    {
+        require(orbsGuardians.isGuardian(guardian), "Please provide a listed Guardian");
+        return guardiansCommission[guardian];
+    }
+}
@@ -0,0 +1,39 @@
+pragma solidity 0.4.25;
+
+
+import "./IOrbsRewardRecipients.sol";
+
+
+contract OrbsRewardRecipients is IOrbsRewardRecipients {
+
+    // The version of the current Reward Recipients smart contract.
+    uint public constant VERSION = 1;
+
+    // Internal mapping to keep track of the reward recipients.
+    mapping(address => address) internal rewardRecipients;
+
+    /// @dev Select the address that will receive your rewards.
+    /// @param recipient address the address that will receive the rewards. If you want to receive them yourself - set it to yourself.
+    function setRewardRecipient(address recipient) external {
+        address sender = msg.sender;
+        require(recipient != address(0), "must set recipient to non 0");
+
+        if (recipient == sender) {
+            delete rewardRecipients[sender];
+        } else {
+            rewardRecipients[sender] = recipient;
+        }
+
+        emit RewardRecipientSet(sender, recipient);
+    }
+
+    /// @dev returns the address that receives the rewards of the stakeholder, zero if the stakeholder receives them
+    /// @param stakeholder address the address of the stakeholder
+    function getRewardRecipient(address stakeholder)
+        public
+        view
+        returns (address)
+    {
+        return rewardRecipients[stakeholder];
+    }
+}
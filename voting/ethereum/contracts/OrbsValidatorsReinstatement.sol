@@ -0,0 +1,97 @@
+pragma solidity 0.4.25;
+
+
+import "./IOrbsValidatorsReinstatement.sol";
+
+
+contract OrbsValidatorsReinstatement is IOrbsValidatorsReinstatement {
+
+    // A reinstate vote is a pair of block number and list of validators. The vote's block
+    // number is used to determine the vote qualification for an election event, like a vote out.
+    struct VotingRecord {
+        uint blockNumber;
+        address[] validators;
+    }
+
+    // The version of the current Reinstatement smart contract.
+    uint public constant VERSION = 1;
+
+    // Var to see that voting is moving forward. Is used to emit events to test for completeness.
+    uint internal voteCounter;
+
+    // The amount of validators you can vote to reinstate in each election round.
+    uint public maxReinstateCount;
+
+    // Internal mapping to keep track of the reinstate votes.
+    mapping(address => VotingRecord) internal votes;
+
+    /// @dev Constructor that initializes the Reinstatement contract.
+    constructor(uint maxReinstateCount_) public {
+        require(maxReinstateCount_ > 0, "maxReinstateCount_ must be positive");
+        maxReinstateCount = maxReinstateCount_;
+    }
+
+    /// @dev Voting method to select which banned validators you want to reinstate before their vote out ban ends.
+    /// @param validators address[] an array of validators addresses you want to reinstate. In case you want to withdraw your reinstate vote, send an empty array.
+    function voteReinstate(address[] validators) external {
+        address sender = msg.sender;
+        require(validators.length <= maxReinstateCount, "Validators list is over the allowed length");
+        sanitizeValidators(validators);
+
+        voteCounter++;
+
+        votes[sender] = VotingRecord({
+            blockNumber: block.number,
+            validators: validators
+        });
+
+        emit ReinstateVote(sender, validators, voteCounter);
+    }
+
+    /// @dev returns reinstate vote pair - validators list and the block number the vote was set.
+    ///      same as getCurrentReinstateVote but returns addresses represented as byte20.
+    function getCurrentReinstateVoteBytes20(address guardian)
+        public
+        view
+        returns (bytes20[] memory validatorsBytes20, uint blockNumber)
+    {
+        address[] memory validatorAddresses;
+        (validatorAddresses, blockNumber) = getCurrentReinstateVote(guardian);
+
+        uint validatorAddressesLength = validatorAddresses.length;
+
+        validatorsBytes20 = new bytes20[](validatorAddressesLength);
+
+        for (uint i = 0; i < validatorAddressesLength; i++) {
+            validatorsBytes20[i] = bytes20(validatorAddresses[i]);
+        }
+    }
+
+    /// @dev returns reinstate vote pair - validators list and the block number the vote was set.
+    /// @param guardian address the address of the guardian
+    function getCurrentReinstateVote(address guardian)
+        public
+        view
+        returns (address[] memory validators, uint blockNumber)
+    {
+        VotingRecord storage lastVote = votes[guardian];
+
+        blockNumber = lastVote.blockNumber;
+        validators = lastVote.validators;
+    }
+
+    /// @dev check that the validators array is unique and non zero.
+    /// @param validators address[]
+    function sanitizeValidators(address[] validators)
+        private
+        pure
+    {
+        uint validatorsLength = validators.length;
+        for (uint i = 0; i < validatorsLength; i++) {
+            require(validators[i] != address(0), "All validator addresses must be non 0");
+            for (uint j = i + 1; j < validatorsLength; j++) {
+                require(validators[j] != validators[i], "Duplicate Validators");
+            }
+        }
+    }
+}
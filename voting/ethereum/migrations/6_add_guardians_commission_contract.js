@@ -0,0 +1,15 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+var Guardians = artifacts.require("./OrbsGuardians.sol");
+var GuardiansCommission = artifacts.require("./OrbsGuardiansCommission.sol");
+
+// commission is bound to the guardians contract already deployed, registered guardians set it without registering again
+module.exports = function(deployer) {
+  deployer.deploy(GuardiansCommission, Guardians.address);
+};
@@ -11,6 +11,9 @@ const OrbsValidators = artifacts.require('OrbsValidators');
 const OrbsValidatorsRegistry = artifacts.require('OrbsValidatorsRegistry');
 const OrbsVoting = artifacts.require('OrbsVoting');
 const OrbsGuardians = artifacts.require('OrbsGuardians');
+const OrbsValidatorsReinstatement = artifacts.require('OrbsValidatorsReinstatement');
+const OrbsGuardiansCommission = artifacts.require('OrbsGuardiansCommission');
+const OrbsRewardRecipients = artifacts.require('OrbsRewardRecipients');
 
 module.exports.numToAddress = (num) => {
     return web3.utils.toChecksumAddress(web3.utils.padLeft(web3.utils.toHex(num), 40));
@@ -29,6 +32,17 @@ module.exports.Driver = class {
         }
         this.OrbsVoting = await OrbsVoting.new(maxVoteOutNodes);
     }
+
+    async deployReinstatement(maxReinstateNodes) {
+        if (isNaN(maxReinstateNodes)) {
+            maxReinstateNodes = 3;
+        }
+        this.OrbsValidatorsReinstatement = await OrbsValidatorsReinstatement.new(maxReinstateNodes);
+    }
+    async deployRewardRecipients() {
+        this.OrbsRewardRecipients = await OrbsRewardRecipients.new();
+    }
+
     async deployRegistry() {
         this.OrbsRegistry = await OrbsValidatorsRegistry.new();
     };
@@ -47,6 +61,13 @@ module.exports.Driver = class {
         this.OrbsGuardians = await OrbsGuardians.new(this.registrationDeposit,registrationMinTime);
     };
 
+    async deployGuardiansCommission() {
+        if (this.OrbsGuardians === undefined) {
+            await this.deployGuardians();
+        }
+        this.OrbsGuardiansCommission = await OrbsGuardiansCommission.new(this.OrbsGuardians.address);
+    };
+
     async deployValidatorsWithRegistry(maxValidators) {
         await this.deployRegistry();
         await this.deployValidators(maxValidators)
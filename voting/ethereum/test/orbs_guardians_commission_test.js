@@ -0,0 +1,77 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+
+const {Driver} = require('./driver');
+const {assertResolve, assertReject} = require('./assertExtensions');
+
+contract('OrbsGuardiansCommission', accounts => {
+    let driver;
+
+    beforeEach(() => {
+        driver = new Driver();
+    });
+
+    describe('is not payable', () => {
+        it('rejects payments', async () => {
+            await driver.deployGuardiansCommission();
+            await assertReject(web3.eth.sendTransaction({
+                to: driver.OrbsGuardiansCommission.address,
+                from: accounts[0],
+                value: 1
+            }), "expected payment to fail");
+        });
+    });
+
+    describe('when setting commission', () => {
+        it('should reflect the commission in getCommission() and emit CommissionUpdated', async () => {
+            await driver.deployGuardiansCommission();
+
+            await driver.OrbsGuardians.register("some name", "some website", driver.depositOptions(accounts[1]));
+            assert.equal((await driver.OrbsGuardiansCommission.getCommission(accounts[1])).toNumber(), 0, "expected commission to start at zero");
+
+            const res = await driver.OrbsGuardiansCommission.setCommission(1500, {from: accounts[1]});
+            assert.equal((await driver.OrbsGuardiansCommission.getCommission(accounts[1])).toNumber(), 1500, "expected commission to be updated");
+
+            const e = res.logs.find(log => log.event === "CommissionUpdated");
+            assert.isOk(e, "expected CommissionUpdated event");
+            assert.equal(e.args.guardian, accounts[1]);
+            assert.equal(e.args.commissionBasisPoints.toNumber(), 1500);
+        });
+
+        it('should fail if sender is not a guardian of the bound guardians contract', async () => {
+            await driver.deployGuardiansCommission();
+
+            await assertReject(driver.OrbsGuardiansCommission.setCommission(1500, {from: accounts[1]}), "expected setCommission to fail if not registered");
+        });
+
+        it('should reject commission above 100 percent', async () => {
+            await driver.deployGuardiansCommission();
+
+            await driver.OrbsGuardians.register("some name", "some website", driver.depositOptions(accounts[1]));
+            await assertReject(driver.OrbsGuardiansCommission.setCommission(10001, {from: accounts[1]}), "expected commission above max to be rejected");
+            await assertResolve(driver.OrbsGuardiansCommission.setCommission(10000, {from: accounts[1]}), "expected commission of exactly max to succeed");
+        });
+
+        it('should keep guardians registered before it was deployed', async () => {
+            await driver.deployGuardians();
+            await driver.OrbsGuardians.register("some name", "some website", driver.depositOptions(accounts[1]));
+            await driver.deployGuardiansCommission();
+
+            await assertResolve(driver.OrbsGuardiansCommission.setCommission(500, {from: accounts[1]}), "expected an existing guardian to set commission");
+        });
+    });
+
+    describe('when getCommission() is called', () => {
+        it('should fail for an address that is not a guardian', async () => {
+            await driver.deployGuardiansCommission();
+
+            await assertReject(driver.OrbsGuardiansCommission.getCommission(accounts[1]), "expected getCommission to fail for a non guardian");
+        });
+    });
+});
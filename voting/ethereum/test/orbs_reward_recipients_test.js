@@ -0,0 +1,62 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+
+const {Driver, numToAddress} = require('./driver');
+const {assertReject} = require('./assertExtensions');
+
+contract('OrbsRewardRecipients', accounts => {
+    let driver;
+
+    beforeEach(() => {
+        driver = new Driver();
+    });
+
+    describe('is not payable', () => {
+        it('rejects payments', async () => {
+            await driver.deployRewardRecipients();
+            await assertReject(web3.eth.sendTransaction({
+                to: driver.OrbsRewardRecipients.address,
+                from: accounts[0],
+                value: 1
+            }), "expected payment to fail");
+        });
+    });
+
+    describe('when calling the setRewardRecipient(recipient) function', () => {
+        it('should emit one RewardRecipientSet event and reflect it in getRewardRecipient()', async () => {
+            await driver.deployRewardRecipients();
+            const stakeholder = accounts[3];
+            const recipient = numToAddress(1);
+
+            const receipt = await driver.OrbsRewardRecipients.setRewardRecipient(recipient, {from: stakeholder});
+
+            const e = receipt.logs[0];
+            assert.equal(e.event, "RewardRecipientSet");
+            assert.equal(e.args.stakeholder, stakeholder);
+            assert.equal(e.args.recipient, recipient);
+            assert.equal(await driver.OrbsRewardRecipients.getRewardRecipient(stakeholder), recipient);
+        });
+
+        it('should clear the recipient when set to self', async () => {
+            await driver.deployRewardRecipients();
+            const stakeholder = accounts[3];
+
+            await driver.OrbsRewardRecipients.setRewardRecipient(numToAddress(1), {from: stakeholder});
+            await driver.OrbsRewardRecipients.setRewardRecipient(stakeholder, {from: stakeholder});
+
+            assert.equal(await driver.OrbsRewardRecipients.getRewardRecipient(stakeholder), numToAddress(0), "recipient should be zero after setting to self");
+        });
+
+        it('should reject recipient 0 address', async () => {
+            await driver.deployRewardRecipients();
+
+            await assertReject(driver.OrbsRewardRecipients.setRewardRecipient(numToAddress(0)), "expected setting zero address as recipient to fail");
+        });
+    });
+});
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+
+const {Driver, numToAddress} = require('./driver');
+const {assertResolve, assertReject} = require('./assertExtensions');
+
+contract('Reinstatement', accounts => {
+    let driver;
+
+    const bannedNodes = [accounts[1], accounts[2], accounts[3]];
+    beforeEach(() => {
+        driver = new Driver();
+    });
+
+    describe('is not payable', () => {
+        it('rejects payments', async () => {
+            await driver.deployReinstatement();
+            await assertReject(web3.eth.sendTransaction({
+                to: driver.OrbsValidatorsReinstatement.address,
+                from: accounts[0],
+                value: 1
+            }), "expected payment to fail");
+        });
+    });
+
+    describe('when calling the voteReinstate() function', () => {
+        it('should emit one ReinstateVote event', async () => {
+            await driver.deployReinstatement();
+
+            const receipt = await driver.OrbsValidatorsReinstatement.voteReinstate(bannedNodes);
+
+            const e = receipt.logs[0];
+            assert.equal(e.event, "ReinstateVote");
+            assert.equal(e.args.voter, accounts[0]);
+            assert.equal(e.args.voteCounter, 1);
+            assert.deepEqual(e.args.validators, bannedNodes);
+        });
+
+        it('should allow withdrawing a reinstate vote', async () => {
+            await driver.deployReinstatement();
+
+            let receipt = await assertResolve(driver.OrbsValidatorsReinstatement.voteReinstate([]), "voting to reinstate no one should succeed");
+
+            const e = receipt.logs[0];
+            assert.equal(e.event, "ReinstateVote");
+            assert.deepEqual(e.args.validators, []);
+        });
+
+        it('should disallow voting for too many nodes', async () => {
+            await driver.deployReinstatement(bannedNodes.length - 1);
+
+            await assertReject(driver.OrbsValidatorsReinstatement.voteReinstate(bannedNodes), "voting for too many nodes should fail");
+        });
+
+        it('should disallow voting to same address twice', async () => {
+            await driver.deployReinstatement(5);
+
+            await assertReject(driver.OrbsValidatorsReinstatement.voteReinstate([accounts[1], accounts[3], accounts[1]]), "Should not vote for the same node twice");
+        });
+
+        it('should reject calls with 0 address', async () => {
+            await driver.deployReinstatement();
+
+            await assertReject(driver.OrbsValidatorsReinstatement.voteReinstate([numToAddress(1), numToAddress(0)]));
+        });
+    });
+
+    describe('when fetching current reinstate vote', () => {
+        [
+            {funcName: "getCurrentReinstateVote", fieldName: "validators"},
+            {funcName: "getCurrentReinstateVoteBytes20", fieldName: "validatorsBytes20"}
+        ].forEach((getlastVoteVariation) => {
+
+            context(`with ${getlastVoteVariation.funcName}()`, async () => {
+                let functionUnderTest;
+                let validatorsReturnFieldName;
+                beforeEach(async () => {
+                    await driver.deployReinstatement();
+                    functionUnderTest = driver.OrbsValidatorsReinstatement[getlastVoteVariation.funcName];
+                    validatorsReturnFieldName = getlastVoteVariation.fieldName;
+                });
+
+                it('returns the last vote made by a voter', async () => {
+                    const firstVote = [numToAddress(6), numToAddress(7)];
+                    const secondVote = [numToAddress(8)];
+
+                    const firstVoteBlockNumber = await driver.OrbsValidatorsReinstatement.voteReinstate(firstVote).then(r => r.receipt.blockNumber);
+                    const reportedFirstVote = await functionUnderTest(accounts[0]);
+
+                    assert.deepEqual(reportedFirstVote[validatorsReturnFieldName].map(a => web3.utils.toChecksumAddress(a)), firstVote);
+                    assert.equal(reportedFirstVote.blockNumber.toNumber(), firstVoteBlockNumber);
+
+                    const secondVoteBlockNumber = await driver.OrbsValidatorsReinstatement.voteReinstate(secondVote).then(r => r.receipt.blockNumber);
+                    const reportedSecondVote = await functionUnderTest(accounts[0]);
+
+                    assert.deepEqual(reportedSecondVote[validatorsReturnFieldName].map(a => web3.utils.toChecksumAddress(a)), secondVote);
+                    assert.equal(reportedSecondVote.blockNumber.toNumber(), secondVoteBlockNumber);
+                });
+
+                it('returns defaults if guardian never voted', async () => {
+                    const defaults = await functionUnderTest(numToAddress(654));
+                    assert.equal(defaults[validatorsReturnFieldName].length, 0, "expected to get empty nodes array if never voted");
+                    assert.equal(defaults.blockNumber, 0, "expected to get zero block number if never voted");
+                });
+            });
+        });
+    });
+});
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/env"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 )
@@ -77,7 +78,9 @@ func _setElectedValidators(elected [][20]byte, electionTime uint64, electionBloc
 	_setElectedValidatorsOrbsAddressAtIndex(index, electedOrbsAddresses)
 	electedEthereumAddresses := _concatElectedEthereumAddresses(elected)
 	_setElectedValidatorsEthereumAddressAtIndex(index, electedEthereumAddresses)
+	_setElectedValidatorsTopologyAtIndex(index, _concatElectedTopology(index, elected))
 	_setNumberOfElections(index)
+	events.EmitEvent(ElectionCompleted, index, electionBlockNumber, electedEthereumAddresses)
 }
 
 func _concatElectedEthereumAddresses(elected [][20]byte) []byte {
@@ -58,6 +58,7 @@ func TestOrbsElectionResultsContract_updateElectionResults(t *testing.T) {
 		_setNumberOfElections(currIndex)
 		_setValidatorOrbsAddress(newElected[0][:], newElectedOrbs[0][:])
 		m.MockEnvBlockHeight(5000000)
+		m.MockEmitEvent(ElectionCompleted, currIndex+1, newBlockNumber, _concatElectedEthereumAddresses(newElected))
 
 		// call
 		_setElectedValidators(newElected, newTime, newBlockNumber)
@@ -86,6 +87,7 @@ func TestOrbsElectionResultsContract_updateElectionResults_Empty(t *testing.T) {
 		_init()
 		_setValidatorOrbsAddress(newElected[0][:], newElectedOrbs[0][:])
 		m.MockEnvBlockHeight(5000000)
+		m.MockEmitEvent(ElectionCompleted, uint32(1), newBlockNumber, _concatElectedEthereumAddresses(newElected))
 
 		// call
 		_setElectedValidators(newElected, newTime, newBlockNumber)
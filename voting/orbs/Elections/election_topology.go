@@ -0,0 +1,93 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+)
+
+/***
+ * Election topology : the validators registry record read at the election block is kept per election,
+ * so the committee and its network endpoints come from the same snapshot.
+ * the topology of an election is its elected validators in order, each as orbs address (20 bytes) followed by ipv4 address (4 bytes).
+ */
+const TOPOLOGY_ENTRY_LENGTH = 24
+
+type ValidatorData struct {
+	Name        string
+	IpAddress   [4]byte
+	Website     string
+	OrbsAddress [20]byte
+}
+
+func _setValidatorDataAtIndex(index uint32, validator []byte, data *ValidatorData) {
+	state.WriteString(_formatElectionValidatorName(index, validator), data.Name)
+	state.WriteBytes(_formatElectionValidatorIp(index, validator), data.IpAddress[:])
+	state.WriteString(_formatElectionValidatorWebsite(index, validator), data.Website)
+	state.WriteBytes(_formatElectionValidatorRegisteredOrbsAddress(index, validator), data.OrbsAddress[:])
+}
+
+func _concatElectedTopology(index uint32, elected [][20]byte) []byte {
+	topology := make([]byte, 0, len(elected)*TOPOLOGY_ENTRY_LENGTH)
+	for _, validator := range elected {
+		orbsAddress := _getValidatorOrbsAddress(validator[:])
+		var ip [4]byte
+		copy(ip[:], getValidatorIpByIndex(validator[:], index))
+		topology = append(topology, orbsAddress[:]...)
+		topology = append(topology, ip[:]...)
+	}
+	return topology
+}
+
+/***
+ * Election topology - data struct
+ */
+func _formatElectionValidatorName(index uint32, validator []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Validator_%s_Name", index, hex.EncodeToString(validator)))
+}
+
+func _formatElectionValidatorIp(index uint32, validator []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Validator_%s_Ip", index, hex.EncodeToString(validator)))
+}
+
+func _formatElectionValidatorWebsite(index uint32, validator []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Validator_%s_Website", index, hex.EncodeToString(validator)))
+}
+
+func _formatElectionValidatorRegisteredOrbsAddress(index uint32, validator []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Validator_%s_OrbsAddress", index, hex.EncodeToString(validator)))
+}
+
+func _formatElectionTopology(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Topology", index))
+}
+
+func getValidatorNameByIndex(validator []byte, index uint32) string {
+	return state.ReadString(_formatElectionValidatorName(index, validator))
+}
+
+func getValidatorIpByIndex(validator []byte, index uint32) []byte {
+	return state.ReadBytes(_formatElectionValidatorIp(index, validator))
+}
+
+func getValidatorWebsiteByIndex(validator []byte, index uint32) string {
+	return state.ReadString(_formatElectionValidatorWebsite(index, validator))
+}
+
+func getValidatorOrbsAddressByIndex(validator []byte, index uint32) []byte {
+	return state.ReadBytes(_formatElectionValidatorRegisteredOrbsAddress(index, validator))
+}
+
+func getElectedValidatorsTopologyByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatElectionTopology(index))
+}
+
+func _setElectedValidatorsTopologyAtIndex(index uint32, topology []byte) {
+	state.WriteBytes(_formatElectionTopology(index), topology)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"fmt"
+	"strings"
+)
+
+/***
+ * Errors : rejections are panicked as electionsError so tools can act on a stable code instead of the message text.
+ * output format is CODE{resubmit=true|false,field=value,...}: human readable message
+ * resubmit=true means the same tx may succeed in a later election (or later in this one), false means drop it.
+ */
+const ERROR_MIRROR_AFTER_ELECTION = "ERR_MIRROR_AFTER_ELECTION"
+const ERROR_MIRROR_STALE_ORDERING = "ERR_MIRROR_STALE_ORDERING"
+const ERROR_MIRROR_WRONG_TRANSFER_VALUE = "ERR_MIRROR_WRONG_TRANSFER_VALUE"
+const ERROR_MIRROR_DELEGATE_OVERRIDES_TRANSFER = "ERR_MIRROR_DELEGATE_OVERRIDES_TRANSFER"
+const ERROR_MIRROR_WRONG_COMMISSION_VALUE = "ERR_MIRROR_WRONG_COMMISSION_VALUE"
+const ERROR_PROCESSING_STARTED = "ERR_PROCESSING_STARTED"
+const ERROR_MIRROR_PERIOD_NOT_ENDED = "ERR_MIRROR_PERIOD_NOT_ENDED"
+const ERROR_NOT_GOVERNANCE = "ERR_NOT_GOVERNANCE"
+const ERROR_COMMISSION_ABOVE_MAX = "ERR_COMMISSION_ABOVE_MAX"
+
+var errorCodeResubmit = map[string]bool{
+	ERROR_MIRROR_AFTER_ELECTION:              true,
+	ERROR_MIRROR_STALE_ORDERING:              false,
+	ERROR_MIRROR_WRONG_TRANSFER_VALUE:        false,
+	ERROR_MIRROR_DELEGATE_OVERRIDES_TRANSFER: false,
+	ERROR_MIRROR_WRONG_COMMISSION_VALUE:      false,
+	ERROR_PROCESSING_STARTED:                 true,
+	ERROR_MIRROR_PERIOD_NOT_ENDED:            true,
+	ERROR_NOT_GOVERNANCE:                     false,
+	ERROR_COMMISSION_ABOVE_MAX:               false,
+}
+
+type errorField struct {
+	name  string
+	value interface{}
+}
+
+type electionsError struct {
+	code    string
+	fields  []errorField
+	message string
+}
+
+func _newError(code string, message string, fields ...errorField) *electionsError {
+	return &electionsError{code: code, fields: fields, message: message}
+}
+
+func _errorAddressField(name string, address []byte) errorField {
+	return errorField{name, fmt.Sprintf("%x", address)}
+}
+
+func (e *electionsError) Error() string {
+	fields := make([]string, 0, len(e.fields)+1)
+	fields = append(fields, fmt.Sprintf("resubmit=%t", e.isResubmittable()))
+	for _, f := range e.fields {
+		fields = append(fields, fmt.Sprintf("%s=%v", f.name, f.value))
+	}
+	return fmt.Sprintf("%s{%s}: %s", e.code, strings.Join(fields, ","), e.message)
+}
+
+func (e *electionsError) Code() string {
+	return e.code
+}
+
+func (e *electionsError) Field(name string) interface{} {
+	for _, f := range e.fields {
+		if f.name == name {
+			return f.value
+		}
+	}
+	return nil
+}
+
+func (e *electionsError) isResubmittable() bool {
+	return errorCodeResubmit[e.code]
+}
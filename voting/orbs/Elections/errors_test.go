@@ -0,0 +1,109 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOrbsVotingContract_errors_Format(t *testing.T) {
+	err := _newError(ERROR_MIRROR_STALE_ORDERING, "some message",
+		_errorAddressField("delegator", []byte{0x01, 0xab}), errorField{"eventBlockNumber", uint64(100)})
+
+	require.Equal(t, "ERR_MIRROR_STALE_ORDERING{resubmit=false,delegator=01ab,eventBlockNumber=100}: some message", err.Error())
+	require.Equal(t, ERROR_MIRROR_STALE_ORDERING, err.Code())
+	require.EqualValues(t, 100, err.Field("eventBlockNumber"))
+	require.Nil(t, err.Field("noSuchField"))
+}
+
+func TestOrbsVotingContract_errors_Resubmit(t *testing.T) {
+	tests := []struct {
+		code     string
+		resubmit bool
+	}{
+		{ERROR_MIRROR_AFTER_ELECTION, true},
+		{ERROR_PROCESSING_STARTED, true},
+		{ERROR_MIRROR_PERIOD_NOT_ENDED, true},
+		{ERROR_MIRROR_STALE_ORDERING, false},
+		{ERROR_MIRROR_WRONG_TRANSFER_VALUE, false},
+		{ERROR_MIRROR_DELEGATE_OVERRIDES_TRANSFER, false},
+	}
+	for _, cTest := range tests {
+		require.Equal(t, cTest.resubmit, _newError(cTest.code, "").isResubmittable(), "wrong resubmit for %s", cTest.code)
+	}
+}
+
+func TestOrbsVotingContract_errors_StaleOrderingFields(t *testing.T) {
+	delegatorAddr := []byte{0x01}
+	agentAddr := []byte{0x02}
+	eventBlockNumber := uint64(100000)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		state.WriteString(_formatDelegatorMethod(delegatorAddr), DELEGATION_NAME)
+		state.WriteUint64(_formatDelegatorBlockNumberKey(delegatorAddr), eventBlockNumber+1)
+		state.WriteUint32(_formatDelegatorBlockTxIndexKey(delegatorAddr), 3)
+
+		// call
+		err := recoverElectionsError(func() {
+			_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, 10, DELEGATION_NAME)
+		})
+
+		// assert
+		require.NotNil(t, err)
+		require.Equal(t, ERROR_MIRROR_STALE_ORDERING, err.Code())
+		require.EqualValues(t, eventBlockNumber, err.Field("eventBlockNumber"))
+		require.EqualValues(t, eventBlockNumber+1, err.Field("currentBlockNumber"))
+		require.EqualValues(t, 3, err.Field("currentTxIndex"))
+		require.Equal(t, "01", err.Field("delegator"))
+	})
+}
+
+func TestOrbsVotingContract_errors_processVotingMirrorPeriodNotEnded(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		_setCurrentElectionBlockNumber_InTests(50000)
+		m.MockEthereumGetBlockNumber(50000)
+
+		// call
+		err := recoverElectionsError(func() {
+			processVoting()
+		})
+
+		// assert
+		require.NotNil(t, err)
+		require.Equal(t, ERROR_MIRROR_PERIOD_NOT_ENDED, err.Code())
+		require.EqualValues(t, 1, err.Field("electionIndex"))
+	})
+}
+
+func recoverElectionsError(f func()) (err *electionsError) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(*electionsError); ok {
+				err = e
+			} else {
+				panic(r)
+			}
+		}
+	}()
+	f()
+	return nil
+}
+
+func requirePanicsWithErrorCode(t *testing.T, code string, f func(), msgAndArgs ...interface{}) {
+	err := recoverElectionsError(f)
+	require.NotNil(t, err, msgAndArgs...)
+	require.Equal(t, code, err.Code(), msgAndArgs...)
+}
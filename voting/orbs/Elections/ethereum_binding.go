@@ -15,6 +15,9 @@ var ETHEREUM_VOTING_ADDR = "0x30f855afb78758Aa4C2dc706fb0fA3A98c865d2d"
 var ETHEREUM_VALIDATORS_ADDR = "0x240fAa45557c61B6959162660E324Bb90984F00f"
 var ETHEREUM_VALIDATORS_REGISTRY_ADDR = "0x56A6895FD37f358c17cbb3F14A864ea5Fe871F0a"
 var ETHEREUM_GUARDIANS_ADDR = "0xD64B1BF6fCAb5ADD75041C89F61816c2B3d5E711"
+var ETHEREUM_VALIDATORS_REINSTATEMENT_ADDR = "" // empty until deployed, vote out bans then always run their full length
+var ETHEREUM_GUARDIANS_COMMISSION_ADDR = ""     // empty until deployed, commission is then only set natively by governance
+var ETHEREUM_REWARD_RECIPIENTS_ADDR = ""        // empty until deployed, rewards are then not redirected
 
 func getTokenEthereumContractAddress() string {
 	return ETHEREUM_TOKEN_ADDR
@@ -63,3 +66,27 @@ func getValidatorsRegistryEthereumContractAddress() string {
 func getValidatorsRegistryAbi() string {
 	return `[{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"}],"name":"ValidatorLeft","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"}],"name":"ValidatorRegistered","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"}],"name":"ValidatorUpdated","type":"event"},{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"ipAddress","type":"bytes4"},{"name":"website","type":"string"},{"name":"orbsAddress","type":"bytes20"}],"name":"register","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"ipAddress","type":"bytes4"},{"name":"website","type":"string"},{"name":"orbsAddress","type":"bytes20"}],"name":"update","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[],"name":"leave","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getValidatorData","outputs":[{"name":"name","type":"string"},{"name":"ipAddress","type":"bytes4"},{"name":"website","type":"string"},{"name":"orbsAddress","type":"bytes20"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getRegistrationBlockNumber","outputs":[{"name":"registeredOn","type":"uint256"},{"name":"lastUpdatedOn","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"isValidator","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getOrbsAddress","outputs":[{"name":"orbsAddress","type":"bytes20"}],"payable":false,"stateMutability":"view","type":"function"}]`
 }
+
+func getValidatorsReinstatementEthereumContractAddress() string {
+	return ETHEREUM_VALIDATORS_REINSTATEMENT_ADDR
+}
+
+func getValidatorsReinstatementAbi() string {
+	return `[{"anonymous":false,"inputs":[{"indexed":true,"name":"voter","type":"address"},{"indexed":false,"name":"validators","type":"address[]"},{"indexed":false,"name":"voteCounter","type":"uint256"}],"name":"ReinstateVote","type":"event"},{"constant":false,"inputs":[{"name":"validators","type":"address[]"}],"name":"voteReinstate","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"guardian","type":"address"}],"name":"getCurrentReinstateVote","outputs":[{"name":"validators","type":"address[]"},{"name":"blockNumber","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"guardian","type":"address"}],"name":"getCurrentReinstateVoteBytes20","outputs":[{"name":"validatorsBytes20","type":"bytes20[]"},{"name":"blockNumber","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+}
+
+func getGuardiansCommissionEthereumContractAddress() string {
+	return ETHEREUM_GUARDIANS_COMMISSION_ADDR
+}
+
+func getGuardiansCommissionAbi() string {
+	return `[{"anonymous":false,"inputs":[{"indexed":true,"name":"guardian","type":"address"},{"indexed":false,"name":"commissionBasisPoints","type":"uint256"}],"name":"CommissionUpdated","type":"event"},{"constant":false,"inputs":[{"name":"commissionBasisPoints","type":"uint256"}],"name":"setCommission","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"guardian","type":"address"}],"name":"getCommission","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+}
+
+func getRewardRecipientsEthereumContractAddress() string {
+	return ETHEREUM_REWARD_RECIPIENTS_ADDR
+}
+
+func getRewardRecipientsAbi() string {
+	return `[{"anonymous":false,"inputs":[{"indexed":true,"name":"stakeholder","type":"address"},{"indexed":true,"name":"recipient","type":"address"}],"name":"RewardRecipientSet","type":"event"},{"constant":false,"inputs":[{"name":"recipient","type":"address"}],"name":"setRewardRecipient","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"stakeholder","type":"address"}],"name":"getRewardRecipient","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}]`
+}
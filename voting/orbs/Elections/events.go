@@ -0,0 +1,94 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1"
+)
+
+var EVENTS = sdk.Export(DelegationMirrored, GuardianCommissionMirrored, GuardianCommissionSet, RewardRecipientMirrored, ProcessingStageAdvanced, ValidatorVotedOut, ValidatorExcluded, ElectionCompleted, RewardAssigned, RewardRedirected)
+
+const REWARD_CATEGORY_PARTICIPATION = "Participation"
+const REWARD_CATEGORY_GUARDIAN_EXCELLENCE = "GuardianExcellence"
+const REWARD_CATEGORY_VALIDATOR = "Validator"
+const REWARD_CATEGORY_GUARDIAN_COMMISSION = "GuardianCommission"
+
+/***
+ * Events
+ */
+func DelegationMirrored(
+	delegator []byte,
+	agent []byte,
+	method string,
+	ethereumBlockNumber uint64,
+	ethereumTxIndex uint32) {
+}
+
+func GuardianCommissionMirrored(
+	guardian []byte,
+	commissionBasisPoints uint64,
+	effectiveElectionIndex uint32,
+	ethereumBlockNumber uint64,
+	ethereumTxIndex uint32) {
+}
+
+func GuardianCommissionSet(
+	guardian []byte,
+	commissionBasisPoints uint64,
+	effectiveElectionIndex uint32) {
+}
+
+func RewardRecipientMirrored(
+	stakeholder []byte,
+	recipient []byte,
+	effectiveElectionIndex uint32,
+	ethereumBlockNumber uint64,
+	ethereumTxIndex uint32) {
+}
+
+func ProcessingStageAdvanced(
+	electionIndex uint32,
+	electionBlockNumber uint64,
+	stage string) {
+}
+
+func ValidatorVotedOut(
+	electionIndex uint32,
+	validator []byte,
+	voteOutWeight uint64,
+	voteOutThreshold uint64,
+	voteOutWeightInWei string,
+	voteOutThresholdInWei string) {
+}
+
+func ValidatorExcluded(
+	electionIndex uint32,
+	validator []byte,
+	reason string) {
+}
+
+func ElectionCompleted(
+	electionIndex uint32,
+	electionBlockNumber uint64,
+	electedValidators []byte) {
+}
+
+func RewardAssigned(
+	category string,
+	address []byte,
+	amount uint64,
+	electionIndex uint32,
+	amountInWei string) {
+}
+
+func RewardRedirected(
+	category string,
+	address []byte,
+	recipient []byte,
+	electionIndex uint32,
+	amountInWei string) {
+}
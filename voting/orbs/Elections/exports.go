@@ -12,15 +12,29 @@ import (
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1"
 )
 
-var PUBLIC = sdk.Export(getTokenEthereumContractAddress, getStakingEthereumContractAddress, getGuardiansEthereumContractAddress, getVotingEthereumContractAddress, getValidatorsEthereumContractAddress, getValidatorsRegistryEthereumContractAddress,
-	mirrorDelegationByTransfer, mirrorDelegation,
+var PUBLIC = sdk.Export(getTokenEthereumContractAddress, getStakingEthereumContractAddress, getGuardiansEthereumContractAddress, getVotingEthereumContractAddress, getValidatorsEthereumContractAddress, getValidatorsRegistryEthereumContractAddress, getValidatorsReinstatementEthereumContractAddress, getGuardiansCommissionEthereumContractAddress, getRewardRecipientsEthereumContractAddress,
+	mirrorDelegationByTransfer, mirrorDelegation, mirrorGuardianCommission, mirrorRewardRecipient,
 	processVoting, isProcessingPeriod, hasProcessingStarted, processTrigger,
 	getNumberOfElections, isElectionOverdue,
 	getElectedValidatorsOrbsAddress, getElectedValidatorsEthereumAddress, getElectedValidatorsEthereumAddressByBlockNumber, getElectedValidatorsOrbsAddressByBlockHeight,
 	getElectedValidatorsOrbsAddressByIndex, getElectedValidatorsEthereumAddressByIndex, getElectedValidatorsBlockNumberByIndex, getElectedValidatorsBlockHeightByIndex,
+	getElectedValidatorsTopologyByIndex, getValidatorNameByIndex, getValidatorIpByIndex, getValidatorWebsiteByIndex, getValidatorOrbsAddressByIndex,
 	getCumulativeParticipationReward, getCumulativeGuardianExcellenceReward, getCumulativeValidatorReward,
 	getGuardianStake, getGuardianVotingWeight, getTotalStake, getValidatorStake, getValidatorVote, getExcellenceProgramGuardians,
-	getCurrentEthereumBlockNumber,
+	getCumulativeParticipationRewardInWei, getCumulativeGuardianExcellenceRewardInWei, getCumulativeValidatorRewardInWei,
+	getGuardianStakeInWei, getGuardianVotingWeightInWei, getTotalStakeInWei, getValidatorStakeInWei, getValidatorVoteInWei,
+	getDeferredVoteOutValidators, getDeferredVoteOutWeight, getDeferredVoteOutWeightInWei, getDeferredLeavingValidatorsByIndex, getDeferredJoiningValidatorsByIndex,
+	getValidatorVoteOutBanEndElectionIndex, isValidatorVoteOutBanned, getBannedValidatorsByIndex,
+	getGuardianCommission, setGuardianCommission, getGuardianCommissionEffectiveElectionIndex, getCumulativeGuardianCommissionReward, getCumulativeGuardianCommissionRewardInWei,
+	getGuardianCommissionRewardInWeiByIndex, getParticipantCommissionPaidInWeiByIndex,
+	getExcellenceProgramMaxNumber, getExcellenceProgramMinStake, getExcellenceProgramTiePolicy, setExcellenceProgram,
+	getExcellenceProgramGuardiansByIndex, getExcellenceProgramGuardianRankByIndex, getExcellenceProgramGuardianVoteInWeiByIndex,
+	getValidatorExclusionReasonByIndex, getGuardianIneligibilityReasonByIndex,
+	getValidatorsRewardedBlocksByIndex, getValidatorRewardInWeiByIndex, isElectionElectedByFallback, getFallbackVotedOutValidatorsByIndex,
+	getUnlockedStakeInWeiByIndex, getLockedStakeInWeiByIndex, getLockedStakeMultiplierPercentByIndex, getVotingWeightInWeiByIndex, getRewardWeightInWeiByIndex,
+	getRewardBudget, setRewardBudget, getRewardBudgetLastYear, getRewardBudgetSpentInWei, getRewardBudgetRemainingInWei,
+	getRewardRecipient, getRewardRecipientEffectiveElectionIndex, getOriginalRewardInWei, getRedirectedRewardInWei, getReceivedRedirectedRewardInWei,
+	getCurrentEthereumBlockNumber, getGovernanceOrbsAddress,
 
 	// block based
 	getElectionPeriod, getCurrentElectionBlockNumber, getNextElectionBlockNumber, getEffectiveElectionBlockNumber,
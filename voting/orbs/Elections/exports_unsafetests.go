@@ -17,19 +17,33 @@ import (
 	"time"
 )
 
-var PUBLIC = sdk.Export(getTokenEthereumContractAddress, getStakingEthereumContractAddress, getGuardiansEthereumContractAddress, getVotingEthereumContractAddress, getValidatorsEthereumContractAddress, getValidatorsRegistryEthereumContractAddress,
+var PUBLIC = sdk.Export(getTokenEthereumContractAddress, getStakingEthereumContractAddress, getGuardiansEthereumContractAddress, getVotingEthereumContractAddress, getValidatorsEthereumContractAddress, getValidatorsRegistryEthereumContractAddress, getValidatorsReinstatementEthereumContractAddress, getGuardiansCommissionEthereumContractAddress, getRewardRecipientsEthereumContractAddress,
 	unsafetests_setTokenEthereumContractAddress, unsafetests_setStakingEthereumContractAddress, unsafetests_setGuardiansEthereumContractAddress,
-	unsafetests_setVotingEthereumContractAddress, unsafetests_setValidatorsEthereumContractAddress, unsafetests_setValidatorsRegistryEthereumContractAddress,
-	unsafetests_setVariables, unsafetests_setElectedValidators, unsafetests_setCurrentElectedBlockNumber,
+	unsafetests_setVotingEthereumContractAddress, unsafetests_setValidatorsEthereumContractAddress, unsafetests_setValidatorsRegistryEthereumContractAddress, unsafetests_setValidatorsReinstatementEthereumContractAddress, unsafetests_setGuardiansCommissionEthereumContractAddress, unsafetests_setRewardRecipientsEthereumContractAddress,
+	unsafetests_setVariables, unsafetests_setMaxValidatorsChangePerElection, unsafetests_setVoteOutBan, unsafetests_setGuardianCommission, unsafetests_setMaxGuardianCommission, unsafetests_setExcellenceProgram, unsafetests_setValidatorRewards, unsafetests_setLockedStakeMultiplier, unsafetests_setRewardBudget, unsafetests_setGuardianEligibility, unsafetests_setElectedValidators, unsafetests_setCurrentElectedBlockNumber,
 	unsafetests_setCurrentElectionTimeNanos, unsafetests_setElectionMirrorPeriodInSeconds, unsafetests_setElectionVotePeriodInSeconds, unsafetests_setElectionPeriodInSeconds,
-	mirrorDelegationByTransfer, mirrorDelegation,
+	mirrorDelegationByTransfer, mirrorDelegation, mirrorGuardianCommission, mirrorRewardRecipient,
 	processVoting, isProcessingPeriod, hasProcessingStarted, processTrigger,
 	getElectionPeriod, getCurrentElectionBlockNumber, getNextElectionBlockNumber, getEffectiveElectionBlockNumber, getNumberOfElections,
-	getCurrentEthereumBlockNumber, getProcessingStartBlockNumber, isElectionOverdue, getMirroringEndBlockNumber,
+	getCurrentEthereumBlockNumber, getGovernanceOrbsAddress, getProcessingStartBlockNumber, isElectionOverdue, getMirroringEndBlockNumber,
 	getElectedValidatorsOrbsAddress, getElectedValidatorsEthereumAddress, getElectedValidatorsEthereumAddressByBlockNumber, getElectedValidatorsOrbsAddressByBlockHeight,
 	getElectedValidatorsOrbsAddressByIndex, getElectedValidatorsEthereumAddressByIndex, getElectedValidatorsBlockNumberByIndex, getElectedValidatorsBlockHeightByIndex,
+	getElectedValidatorsTopologyByIndex, getValidatorNameByIndex, getValidatorIpByIndex, getValidatorWebsiteByIndex, getValidatorOrbsAddressByIndex,
 	getCumulativeParticipationReward, getCumulativeGuardianExcellenceReward, getCumulativeValidatorReward,
 	getGuardianStake, getGuardianVotingWeight, getTotalStake, getValidatorStake, getValidatorVote, getExcellenceProgramGuardians,
+	getCumulativeParticipationRewardInWei, getCumulativeGuardianExcellenceRewardInWei, getCumulativeValidatorRewardInWei,
+	getGuardianStakeInWei, getGuardianVotingWeightInWei, getTotalStakeInWei, getValidatorStakeInWei, getValidatorVoteInWei,
+	getDeferredVoteOutValidators, getDeferredVoteOutWeight, getDeferredVoteOutWeightInWei, getDeferredLeavingValidatorsByIndex, getDeferredJoiningValidatorsByIndex,
+	getValidatorVoteOutBanEndElectionIndex, isValidatorVoteOutBanned, getBannedValidatorsByIndex,
+	getGuardianCommission, setGuardianCommission, getGuardianCommissionEffectiveElectionIndex, getCumulativeGuardianCommissionReward, getCumulativeGuardianCommissionRewardInWei,
+	getGuardianCommissionRewardInWeiByIndex, getParticipantCommissionPaidInWeiByIndex,
+	getExcellenceProgramMaxNumber, getExcellenceProgramMinStake, getExcellenceProgramTiePolicy, setExcellenceProgram,
+	getExcellenceProgramGuardiansByIndex, getExcellenceProgramGuardianRankByIndex, getExcellenceProgramGuardianVoteInWeiByIndex,
+	getValidatorExclusionReasonByIndex, getGuardianIneligibilityReasonByIndex,
+	getValidatorsRewardedBlocksByIndex, getValidatorRewardInWeiByIndex, isElectionElectedByFallback, getFallbackVotedOutValidatorsByIndex,
+	getUnlockedStakeInWeiByIndex, getLockedStakeInWeiByIndex, getLockedStakeMultiplierPercentByIndex, getVotingWeightInWeiByIndex, getRewardWeightInWeiByIndex,
+	getRewardBudget, setRewardBudget, getRewardBudgetLastYear, getRewardBudgetSpentInWei, getRewardBudgetRemainingInWei,
+	getRewardRecipient, getRewardRecipientEffectiveElectionIndex, getOriginalRewardInWei, getRedirectedRewardInWei, getReceivedRedirectedRewardInWei,
 	// time based
 	// switchToTimeBasedElections,
 	//getElectionPeriodInNanos, getEffectiveElectionTimeInNanos, getCurrentElectionTimeInNanos, getNextElectionTimeInNanos,
@@ -47,6 +61,51 @@ func unsafetests_setVariables(voteMirrorPeriod uint64, voteValidPeriod uint64, e
 	MIN_ELECTED_VALIDATORS = int(minElectedValidators)
 }
 
+func unsafetests_setMaxValidatorsChangePerElection(maxChange uint32) {
+	MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = int(maxChange)
+}
+
+func unsafetests_setVoteOutBan(lengthInElections uint32, liftWeightPercent uint64) {
+	VOTE_OUT_BAN_LENGTH_IN_ELECTIONS = lengthInElections
+	VOTE_OUT_BAN_LIFT_WEIGHT_PERCENT = liftWeightPercent
+}
+
+func unsafetests_setGuardianCommission(guardian []byte, commission uint64) {
+	_setGuardianCommission(guardian, commission, _getProcessCurrentElectionIndex()+1)
+}
+
+func unsafetests_setMaxGuardianCommission(maxCommission uint64) {
+	MAX_GUARDIAN_COMMISSION_BASIS_POINTS = maxCommission
+}
+
+func unsafetests_setExcellenceProgram(maxNumber uint32, minStake uint64, tiePolicy string) {
+	_setExcellenceProgramParameters(maxNumber, minStake, tiePolicy)
+}
+
+func unsafetests_setValidatorRewards(electionPeriodInOrbsBlocks uint64, fallbackPolicy string) {
+	if electionPeriodInOrbsBlocks == 0 {
+		panic("election period in orbs blocks must be positive")
+	}
+	ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS = electionPeriodInOrbsBlocks
+	VALIDATOR_FALLBACK_REWARD_POLICY = fallbackPolicy
+}
+
+func unsafetests_setLockedStakeMultiplier(multiplierPercent uint64, forVoting uint32, forRewards uint32) {
+	LOCKED_STAKE_MULTIPLIER_PERCENT = multiplierPercent
+	LOCKED_STAKE_MULTIPLIER_FOR_VOTING = forVoting == 1
+	LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = forRewards == 1
+}
+
+func unsafetests_setRewardBudget(category string, annualBudget uint64) {
+	_setRewardBudget(category, annualBudget)
+}
+
+func unsafetests_setGuardianEligibility(minSelfStake uint64, minAccumulatedStake uint64, ineligibleDelegatorsPolicy string) {
+	GUARDIAN_MIN_SELF_STAKE = minSelfStake
+	GUARDIAN_MIN_ACCUMULATED_STAKE = minAccumulatedStake
+	INELIGIBLE_GUARDIAN_DELEGATORS_POLICY = ineligibleDelegatorsPolicy
+}
+
 func unsafetests_setElectedValidators(joinedAddresses []byte) {
 	index := getNumberOfElections()
 	if index == 0 {
@@ -85,6 +144,18 @@ func unsafetests_setGuardiansEthereumContractAddress(addr string) {
 	ETHEREUM_GUARDIANS_ADDR = addr
 }
 
+func unsafetests_setValidatorsReinstatementEthereumContractAddress(addr string) {
+	ETHEREUM_VALIDATORS_REINSTATEMENT_ADDR = addr
+}
+
+func unsafetests_setGuardiansCommissionEthereumContractAddress(addr string) {
+	ETHEREUM_GUARDIANS_COMMISSION_ADDR = addr
+}
+
+func unsafetests_setRewardRecipientsEthereumContractAddress(addr string) {
+	ETHEREUM_REWARD_RECIPIENTS_ADDR = addr
+}
+
 func unsafetests_setCurrentElectionTimeNanos(time uint64) {
 	fmt.Printf("elections : set electiontime to %d period %d\n", time, getElectionPeriodInNanos())
 	_setElectedValidatorsTimeInNanosAtIndex(getNumberOfElections(), safeuint64.Sub(time, getElectionPeriodInNanos()))
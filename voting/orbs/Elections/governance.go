@@ -0,0 +1,42 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/address"
+)
+
+/***
+ * Governance : parameters kept in state are set by the governance orbs address without a contract upgrade.
+ * an empty governance address means nobody can set them, the defaults stay in force.
+ * parameters cannot change once processing of the current election has started, so an election is processed with one set of them.
+ */
+var ELECTIONS_GOVERNANCE_ORBS_ADDR = ""
+
+func getGovernanceOrbsAddress() []byte {
+	governance, err := hex.DecodeString(ELECTIONS_GOVERNANCE_ORBS_ADDR)
+	if err != nil {
+		panic(fmt.Sprintf("governance orbs address %s is not hex encoded", ELECTIONS_GOVERNANCE_ORBS_ADDR))
+	}
+	return governance
+}
+
+func _requireGovernance(method string) {
+	governance := getGovernanceOrbsAddress()
+	signer := address.GetSignerAddress()
+	if len(governance) == 0 || !bytes.Equal(governance, signer) {
+		panic(_newError(ERROR_NOT_GOVERNANCE, fmt.Sprintf("only the governance address can call %s, signer %x", method, signer),
+			errorField{"method", method}, _errorAddressField("signer", signer)))
+	}
+	if hasProcessingStarted() == 1 {
+		panic(_newError(ERROR_PROCESSING_STARTED, fmt.Sprintf("proccessing has started cannot call %s now, resubmit next election", method),
+			errorField{"method", method}, errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
+	}
+}
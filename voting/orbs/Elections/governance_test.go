@@ -0,0 +1,70 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func _setGovernanceInTests(t *testing.T, governance []byte) {
+	previous := ELECTIONS_GOVERNANCE_ORBS_ADDR
+	ELECTIONS_GOVERNANCE_ORBS_ADDR = hex.EncodeToString(governance)
+	t.Cleanup(func() {
+		ELECTIONS_GOVERNANCE_ORBS_ADDR = previous
+	})
+}
+
+func TestOrbsVotingContract_governance_NoGovernanceAddress(t *testing.T) {
+	_setGovernanceInTests(t, nil)
+
+	InServiceScope([]byte{0x01}, nil, func(m Mockery) {
+		_init()
+
+		requirePanicsWithErrorCode(t, ERROR_NOT_GOVERNANCE, func() {
+			_requireGovernance("someMethod")
+		}, "should panic because no one governs")
+	})
+}
+
+func TestOrbsVotingContract_governance_OnlyGovernanceSigner(t *testing.T) {
+	governance := []byte{0x01, 0x02}
+	_setGovernanceInTests(t, governance)
+
+	InServiceScope([]byte{0x03}, nil, func(m Mockery) {
+		_init()
+
+		requirePanicsWithErrorCode(t, ERROR_NOT_GOVERNANCE, func() {
+			_requireGovernance("someMethod")
+		}, "should panic because signer is not the governance address")
+	})
+
+	InServiceScope(governance, nil, func(m Mockery) {
+		_init()
+
+		require.NotPanics(t, func() {
+			_requireGovernance("someMethod")
+		})
+		require.Equal(t, governance, getGovernanceOrbsAddress())
+	})
+}
+
+func TestOrbsVotingContract_governance_NotDuringProcessing(t *testing.T) {
+	governance := []byte{0x01, 0x02}
+	_setGovernanceInTests(t, governance)
+
+	InServiceScope(governance, nil, func(m Mockery) {
+		_init()
+		_setVotingProcessState(VOTING_PROCESS_STATE_GUARDIANS)
+
+		requirePanicsWithErrorCode(t, ERROR_PROCESSING_STARTED, func() {
+			_requireGovernance("someMethod")
+		}, "should panic because processing has started")
+	})
+}
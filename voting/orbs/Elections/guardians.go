@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
 )
 
 /***
@@ -42,8 +43,10 @@ func _clearGuardians() {
 		state.Clear(_formatGuardianIterator(i))
 		state.Clear(_formatGuardianCandidateKey(guardian))
 		state.Clear(_formatGuardianStakeKey(guardian))
+		state.Clear(_formatGuardianStakeInWeiKey(guardian))
 		state.Clear(_formatGuardianVoteBlockNumberKey(guardian))
 		state.Clear(_formatGuardianVoteWeightKey(guardian))
+		state.Clear(_formatGuardianVoteWeightInWeiKey(guardian))
 	}
 	_setNumberOfGuardians(0)
 }
@@ -92,7 +95,23 @@ func getGuardianStake(guardian []byte) uint64 {
 }
 
 func _setGuardianStake(guardian []byte, stake uint64) {
-	state.WriteUint64(_formatGuardianStakeKey(guardian), stake)
+	_setGuardianStakeInWei(guardian, _toWei(stake))
+}
+
+func _formatGuardianStakeInWeiKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_StakeInWei", hex.EncodeToString(guardian)))
+}
+
+func getGuardianStakeInWei(guardian []byte) string {
+	return _getGuardianStakeInWei(guardian).String()
+}
+
+func _getGuardianStakeInWei(guardian []byte) *big.Int {
+	return _readWeiOrLegacy(_formatGuardianStakeInWeiKey(guardian), _formatGuardianStakeKey(guardian))
+}
+
+func _setGuardianStakeInWei(guardian []byte, stake *big.Int) {
+	_writeWeiAndLegacy(_formatGuardianStakeInWeiKey(guardian), _formatGuardianStakeKey(guardian), stake)
 }
 
 func _formatGuardianVoteBlockNumberKey(guardian []byte) []byte {
@@ -139,5 +158,21 @@ func getGuardianVotingWeight(guardian []byte) uint64 {
 }
 
 func _setGuardianVotingWeight(guardian []byte, weight uint64) {
-	state.WriteUint64(_formatGuardianVoteWeightKey(guardian), weight)
+	_setGuardianVotingWeightInWei(guardian, _toWei(weight))
+}
+
+func _formatGuardianVoteWeightInWeiKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_WeightInWei", hex.EncodeToString(guardian)))
+}
+
+func getGuardianVotingWeightInWei(guardian []byte) string {
+	return _readWeiOrLegacy(_formatGuardianVoteWeightInWeiKey(guardian), _formatGuardianVoteWeightKey(guardian)).String()
+}
+
+func _getGuardianVotingWeightInWei(guardian []byte) *big.Int {
+	return _readWeiOrLegacy(_formatGuardianVoteWeightInWeiKey(guardian), _formatGuardianVoteWeightKey(guardian))
+}
+
+func _setGuardianVotingWeightInWei(guardian []byte, weight *big.Int) {
+	_writeWeiAndLegacy(_formatGuardianVoteWeightInWeiKey(guardian), _formatGuardianVoteWeightKey(guardian), weight)
 }
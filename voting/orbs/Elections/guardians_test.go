@@ -111,7 +111,7 @@ func TestOrbsVotingContract_processVote_clearGuardians_AfterOneElection(t *testi
 
 	var g1 = h.addGuardian(100)
 	g1.vote(h.electionBlock-1, v1)
-	h.addDelegator(500, g1.address)
+	d1 := h.addDelegator(500, g1.address)
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
@@ -119,11 +119,15 @@ func TestOrbsVotingContract_processVote_clearGuardians_AfterOneElection(t *testi
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
 		h.setupEthereumStateBeforeProcess(m)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g1.address: "682", d1.address: "3411"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, g1.address, 1, "5118")
 
 		expectedNumOfStateTransitions := len(h.guardians) + len(h.delegators) + len(h.validators) + 2
 		elected, _ := h.runProcessVoteMachineNtimes(expectedNumOfStateTransitions)
 
 		// check election was "done"
+		m.VerifyMocks()
 		require.EqualValues(t, "", _getVotingProcessState())
 		require.NotEmpty(t, elected)
 		require.True(t, 0 != getGuardianVotingWeight(g1.address[:]))
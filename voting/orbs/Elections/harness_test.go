@@ -78,13 +78,29 @@ func (d *delegator) withLockedStake(lockedStake int) *delegator {
 
 type validator struct {
 	actor
-	orbsAddress [20]byte
+	orbsAddress  [20]byte
+	ipAddress    [4]byte
+	name         string
+	website      string
+	isRegistered bool
+}
+
+func (v *validator) withOrbsAddress(orbsAddress [20]byte) *validator {
+	v.orbsAddress = orbsAddress
+	return v
+}
+
+func (v *validator) withIsRegistered(isRegistered bool) *validator {
+	v.isRegistered = isRegistered
+	return v
 }
 
 func newHarness(isTime bool) *harness {
 	ETHEREUM_STAKE_FACTOR = big.NewInt(int64(10000))
 	MIN_ELECTED_VALIDATORS = 3
 	MAX_ELECTED_VALIDATORS = 10
+	MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 7
+	VOTE_OUT_BAN_LENGTH_IN_ELECTIONS = 3
 	return &harness{isTimeBased: isTime, nextGuardianAddress: 0xa1, nextDelegatorAddress: 0xb1, nextValidatorAddress: 0xd1, nextValidatorOrbsAddress: 0xe1}
 }
 
@@ -114,7 +130,8 @@ func (f *harness) addValidator() *validator {
 	return f.addValidatorWithStake(0)
 }
 func (f *harness) addValidatorWithStake(stake int) *validator {
-	v := &validator{actor: actor{stake: stake, address: [20]byte{f.nextValidatorAddress}}, orbsAddress: [20]byte{f.nextValidatorOrbsAddress}}
+	v := &validator{actor: actor{stake: stake, address: [20]byte{f.nextValidatorAddress}}, orbsAddress: [20]byte{f.nextValidatorOrbsAddress},
+		ipAddress: [4]byte{10, 0, 0, f.nextValidatorAddress}, name: fmt.Sprintf("validator %x", f.nextValidatorAddress), website: "https://orbs.network", isRegistered: true}
 	f.nextValidatorAddress++
 	f.nextValidatorOrbsAddress++
 	f.validators = append(f.validators, v)
@@ -210,7 +227,8 @@ func (f *harness) setupEthereumValidatorsBeforeProcess(m Mockery) {
 		for i, a := range f.validators {
 			validatorAddresses[i] = a.address
 			mockStakedAndLockedInEthereum(m, f.electionBlock, a.address, a.stake, a.lockedStake)
-			mockValidatorOrbsAddressInEthereum(m, f.electionBlock, a.address, a.orbsAddress)
+			mockValidatorRegisteredInEthereum(m, f.electionBlock, a.address, a.isRegistered)
+			mockValidatorDataInEthereum(m, f.electionBlock, a)
 		}
 		mockValidatorsInEthereum(m, f.electionBlock, validatorAddresses)
 	}
@@ -242,6 +260,21 @@ func mockGuardianVoteInEthereum(m Mockery, blockNumber uint64, address [20]byte,
 	}, address)
 }
 
+func mockReinstateVoteInEthereum(m Mockery, blockNumber uint64, address [20]byte, validators [][20]byte, voteBlockNumber uint64) {
+	vote := Vote{
+		ValidatorsBytes20: validators,
+		BlockNumber:       big.NewInt(int64(voteBlockNumber)),
+	}
+	m.MockEthereumCallMethodAtBlock(blockNumber, getValidatorsReinstatementEthereumContractAddress(), getValidatorsReinstatementAbi(), "getCurrentReinstateVoteBytes20", func(out interface{}) {
+		i, ok := out.(*Vote)
+		if ok {
+			*i = vote
+		} else {
+			panic(fmt.Sprintf("wrong something %s", out))
+		}
+	}, address)
+}
+
 func mockGuardiansInEthereum(m Mockery, blockNumber uint64, guardians []*guardian) {
 	addresses := make([][20]byte, 0, len(guardians))
 	for _, g := range guardians {
@@ -284,18 +317,33 @@ func mockValidatorsInEthereum(m Mockery, blockNumber uint64, addresses [][20]byt
 	})
 }
 
-func mockValidatorOrbsAddressInEthereum(m Mockery, blockNumber uint64, validatorAddress [20]byte, orbsValidatorAddress [20]byte) {
+func mockValidatorRegisteredInEthereum(m Mockery, blockNumber uint64, validatorAddress [20]byte, isRegistered bool) {
 	m.MockEthereumCallMethodAtBlock(blockNumber, getValidatorsRegistryEthereumContractAddress(), getValidatorsRegistryAbi(),
-		"getOrbsAddress", func(out interface{}) {
-			orbsAddress, ok := out.(*[20]byte)
+		"isValidator", func(out interface{}) {
+			registered, ok := out.(*bool)
 			if ok {
-				*orbsAddress = orbsValidatorAddress
+				*registered = isRegistered
 			} else {
 				panic(fmt.Sprintf("wrong something %s", out))
 			}
 		}, validatorAddress)
 }
 
+func mockValidatorDataInEthereum(m Mockery, blockNumber uint64, v *validator) {
+	m.MockEthereumCallMethodAtBlock(blockNumber, getValidatorsRegistryEthereumContractAddress(), getValidatorsRegistryAbi(),
+		"getValidatorData", func(out interface{}) {
+			data, ok := out.(*ValidatorData)
+			if ok {
+				data.Name = v.name
+				data.IpAddress = v.ipAddress
+				data.Website = v.website
+				data.OrbsAddress = v.orbsAddress
+			} else {
+				panic(fmt.Sprintf("wrong something %s", out))
+			}
+		}, v.address)
+}
+
 func mockStakedAndLockedInEthereum(m Mockery, blockNumber uint64, address [20]byte, stake int, lockedStake int) {
 	mockStakeInEthereum(m, blockNumber, address, stake)
 	mockLockedStakeInEthereum(m, blockNumber, address, lockedStake)
@@ -327,9 +375,49 @@ func mockLockedStakeInEthereum(m Mockery, blockNumber uint64, address [20]byte,
 	}, address)
 }
 
+func weiStakes(stakes map[[20]byte]uint64) map[[20]byte]*big.Int {
+	weis := make(map[[20]byte]*big.Int, len(stakes))
+	for address, stake := range stakes {
+		weis[address] = _toWei(stake)
+	}
+	return weis
+}
+
 func startTimeBasedGetElectionTime() uint64 {
 	switchToTimeBasedElections()
 	electionDate := 2 * ELECTION_PERIOD_LENGTH_IN_NANOS
 	_setElectedValidatorsTimeInNanosAtIndex(0, electionDate)
 	return electionDate + ELECTION_PERIOD_LENGTH_IN_NANOS
 }
+
+/***
+ * events : every event a test emits is mocked, so an unexpected event panics and an expected one that is not emitted fails VerifyMocks.
+ * emits with the same arguments are matched by a single mock.
+ */
+func mockProcessingStagesEvents(m Mockery, electionIndex uint32, electionBlockNumber uint64) {
+	for _, stage := range []string{VOTING_PROCESS_STATE_GUARDIANS, VOTING_PROCESS_STATE_VALIDATORS, VOTING_PROCESS_STATE_GUARDIANS_DATA, VOTING_PROCESS_STATE_DELEGATORS, VOTING_PROCESS_STATE_CALCULATIONS} {
+		m.MockEmitEvent(ProcessingStageAdvanced, electionIndex, electionBlockNumber, stage)
+	}
+}
+
+func mockElectionCompletedEvent(m Mockery, electionIndex uint32, electionBlockNumber uint64, elected ...[20]byte) {
+	m.MockEmitEvent(ElectionCompleted, electionIndex, electionBlockNumber, _concatElectedEthereumAddresses(elected))
+}
+
+func mockValidatorVotedOutEvent(m Mockery, electionIndex uint32, validator [20]byte, voteOutWeightInWei *big.Int, voteOutThresholdInWei *big.Int) {
+	m.MockEmitEvent(ValidatorVotedOut, electionIndex, validator[:], _toOrbs(voteOutWeightInWei), _toOrbs(voteOutThresholdInWei), voteOutWeightInWei.String(), voteOutThresholdInWei.String())
+}
+
+func mockRewardAssignedEvent(m Mockery, category string, address [20]byte, electionIndex uint32, amountInWei string) {
+	amount, ok := new(big.Int).SetString(amountInWei, 10)
+	if !ok {
+		panic(fmt.Sprintf("reward %s is not a decimal amount in wei", amountInWei))
+	}
+	m.MockEmitEvent(RewardAssigned, category, address[:], _toOrbs(amount), electionIndex, amountInWei)
+}
+
+func mockRewardAssignedEvents(m Mockery, category string, electionIndex uint32, amountsInWei map[[20]byte]string) {
+	for address, amountInWei := range amountsInWei {
+		mockRewardAssignedEvent(m, category, address, electionIndex, amountInWei)
+	}
+}
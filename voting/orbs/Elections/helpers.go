@@ -11,6 +11,7 @@ import (
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
 )
 
 /***
@@ -22,6 +23,69 @@ func _addressSliceToArray(a []byte) [20]byte {
 	return array
 }
 
+func _splitAddresses(concatenated []byte) [][20]byte {
+	numAddresses := len(concatenated) / 20
+	addresses := make([][20]byte, numAddresses)
+	for i := 0; i < numAddresses; i++ {
+		copy(addresses[i][:], concatenated[i*20:i*20+20])
+	}
+	return addresses
+}
+
+func _addressSet(addresses [][20]byte) map[[20]byte]bool {
+	set := make(map[[20]byte]bool, len(addresses))
+	for _, address := range addresses {
+		set[address] = true
+	}
+	return set
+}
+
+func _boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+/***
+ * Helpers : wei precision.
+ * Stakes, weights and rewards are kept in wei (1/ETHEREUM_STAKE_FACTOR of an ORBS) as big ints in state,
+ * the uint64 values in whole ORBS are kept alongside for older readers and are always the wei value truncated.
+ */
+func _toOrbs(wei *big.Int) uint64 {
+	return new(big.Int).Div(wei, ETHEREUM_STAKE_FACTOR).Uint64()
+}
+
+func _toWei(orbs uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(orbs), ETHEREUM_STAKE_FACTOR)
+}
+
+func _readWei(key []byte) *big.Int {
+	return new(big.Int).SetBytes(state.ReadBytes(key))
+}
+
+// values written before precision was added only exist as whole ORBS
+func _readWeiOrLegacy(key []byte, legacyKey []byte) *big.Int {
+	value := state.ReadBytes(key)
+	if len(value) == 0 {
+		return _toWei(state.ReadUint64(legacyKey))
+	}
+	return new(big.Int).SetBytes(value)
+}
+
+func _writeWeiAndLegacy(key []byte, legacyKey []byte, wei *big.Int) {
+	if wei.Sign() == 0 { // zero is written so it is not mistaken for a legacy value
+		state.WriteBytes(key, []byte{0})
+	} else {
+		state.WriteBytes(key, wei.Bytes())
+	}
+	state.WriteUint64(legacyKey, _toOrbs(wei))
+}
+
+func _addWei(a *big.Int, b *big.Int) *big.Int {
+	return new(big.Int).Add(a, b)
+}
+
 func _formatIsTimeBasedElections() []byte {
 	return []byte("Is_Time_Based_Elections")
 }
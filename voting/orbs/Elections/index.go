@@ -17,14 +17,36 @@ const METHOD_GET_ELECTED_VALIDATORS = "getElectedValidatorsOrbsAddress"
 const METHOD_GET_ELECTED_VALIDATORS_BY_BLOCK_HEIGHT = "getElectedValidatorsOrbsAddressByBlockHeight"
 const METHOD_PROCESS_TRIGGER = "processTrigger"
 
+// validators elected by the min-validators fallback are rewarded all, all but the voted out, or none
+const VALIDATOR_FALLBACK_REWARD_POLICY_FULL = "Full"
+const VALIDATOR_FALLBACK_REWARD_POLICY_EXCLUDE_VOTED_OUT = "ExcludeVotedOut"
+const VALIDATOR_FALLBACK_REWARD_POLICY_NONE = "None"
+
+// delegators of a guardian that is not eligible this election do not participate, or participate on their own without a vote
+const INELIGIBLE_GUARDIAN_DELEGATORS_NOT_PARTICIPATING = "NotParticipating"
+const INELIGIBLE_GUARDIAN_DELEGATORS_DIRECT_PARTICIPATION = "DirectParticipation"
+
 // parameters
 var DELEGATION_NAME = "Delegate"
 var DELEGATION_BY_TRANSFER_NAME = "Transfer"
+var GUARDIAN_COMMISSION_NAME = "CommissionUpdated"
+var REWARD_RECIPIENT_NAME = "RewardRecipientSet"
 var DELEGATION_BY_TRANSFER_VALUE = big.NewInt(70000000000000000)
 var ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 var MAX_ELECTED_VALIDATORS = 22
 var MIN_ELECTED_VALIDATORS = 7
+var MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 7 // keep committee change under a third of the committee
 var VOTE_OUT_WEIGHT_PERCENT = uint64(70)
+var VOTE_OUT_BAN_LENGTH_IN_ELECTIONS = uint32(3)
+var VOTE_OUT_BAN_LIFT_WEIGHT_PERCENT = uint64(70)
+var VALIDATOR_FALLBACK_REWARD_POLICY = VALIDATOR_FALLBACK_REWARD_POLICY_EXCLUDE_VOTED_OUT
+var LOCKED_STAKE_MULTIPLIER_PERCENT = uint64(100) // weight of locked stake relative to liquid balance
+var LOCKED_STAKE_MULTIPLIER_FOR_VOTING = false
+var LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = false
+var GUARDIAN_MIN_SELF_STAKE = uint64(0)        // ORBS
+var GUARDIAN_MIN_ACCUMULATED_STAKE = uint64(0) // ORBS, self stake and delegated stake
+var INELIGIBLE_GUARDIAN_DELEGATORS_POLICY = INELIGIBLE_GUARDIAN_DELEGATORS_NOT_PARTICIPATING
+var MAX_GUARDIAN_COMMISSION_BASIS_POINTS = uint64(2000) // 20% of the delegators participation reward
 
 // block based
 var VOTE_MIRROR_PERIOD_LENGTH_IN_BLOCKS = uint64(545)
@@ -32,6 +54,8 @@ var VOTE_VALID_PERIOD_LENGTH_IN_BLOCKS = uint64(45500)
 var ELECTION_PERIOD_LENGTH_IN_BLOCKS = uint64(20000)
 var TRANSITION_PERIOD_LENGTH_IN_BLOCKS = uint64(1)
 var FIRST_ELECTION_BLOCK = uint64(7528900)
+var ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS = uint64(20000)                           // orbs blocks that earn one election's share of the annual validator reward
+var ETHEREUM_BLOCK_TIME_IN_NANOS = uint64((13460 * time.Millisecond).Nanoseconds()) // block rate assumed until two elections are recorded
 
 // time based
 var FIRST_ELECTION_TIME_IN_NANOS = uint64(1569920400000000000) // 09:00:00 Oct 1 2019 GMT
@@ -0,0 +1,136 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+)
+
+/***
+ * Mirror : guardian commission
+ * a mirrored commission takes effect from the next election, so delegators get a full election period to move away from it.
+ * commission is set on the guardians commission contract, bound to the guardians contract so registered guardians keep their registration.
+ * governance can also set it natively, up to MAX_GUARDIAN_COMMISSION_BASIS_POINTS, as an orbs signer cannot be tied to a guardian's ethereum address.
+ */
+type CommissionUpdated struct {
+	Guardian              [20]byte
+	CommissionBasisPoints *big.Int
+}
+
+func mirrorGuardianCommission(hexEncodedEthTxHash string) {
+	_initCurrentElection()
+	if hasProcessingStarted() == 1 {
+		panic(_newError(ERROR_PROCESSING_STARTED, "proccessing has started cannot mirror now, resubmit next election",
+			errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
+	}
+
+	if getGuardiansCommissionEthereumContractAddress() == "" {
+		panic("guardians commission contract is not deployed, there is no commission to mirror")
+	}
+
+	e := &CommissionUpdated{}
+	eventBlockNumber, eventBlockTxIndex := ethereum.GetTransactionLog(getGuardiansCommissionEthereumContractAddress(), getGuardiansCommissionAbi(), hexEncodedEthTxHash, GUARDIAN_COMMISSION_NAME, e)
+
+	if _isMirrorDelegationDataAfterElection(eventBlockNumber) {
+		panic(_newError(ERROR_MIRROR_AFTER_ELECTION, fmt.Sprintf("commission of guardian %x failed since it happened in block number %d which is after election date, resubmit next election",
+			e.Guardian, eventBlockNumber),
+			errorField{"method", GUARDIAN_COMMISSION_NAME}, _errorAddressField("guardian", e.Guardian[:]),
+			errorField{"eventBlockNumber", eventBlockNumber}))
+	}
+	if e.CommissionBasisPoints == nil || !e.CommissionBasisPoints.IsUint64() || e.CommissionBasisPoints.Uint64() > COMMISSION_BASIS_POINTS_DENOMINATOR {
+		panic(_newError(ERROR_MIRROR_WRONG_COMMISSION_VALUE, fmt.Sprintf("commission of guardian %x failed since %d is not a valid commission in basis points", e.Guardian, e.CommissionBasisPoints),
+			_errorAddressField("guardian", e.Guardian[:]), errorField{"value", e.CommissionBasisPoints}))
+	}
+
+	_mirrorGuardianCommissionData(e.Guardian[:], e.CommissionBasisPoints.Uint64(), eventBlockNumber, eventBlockTxIndex)
+}
+
+func _mirrorGuardianCommissionData(guardian []byte, commission uint64, eventBlockNumber uint64, eventBlockTxIndex uint32) {
+	stateBlockNumber := state.ReadUint64(_formatGuardianCommissionBlockNumberKey(guardian))
+	stateBlockTxIndex := state.ReadUint32(_formatGuardianCommissionBlockTxIndexKey(guardian))
+	if stateBlockNumber > eventBlockNumber || (stateBlockNumber == eventBlockNumber && stateBlockTxIndex >= eventBlockTxIndex) {
+		panic(_newError(ERROR_MIRROR_STALE_ORDERING, fmt.Sprintf("commission of guardian %x with block-height %d and tx-index %d failed since current commission is from block-height %d and tx-index %d",
+			guardian, eventBlockNumber, eventBlockTxIndex, stateBlockNumber, stateBlockTxIndex),
+			errorField{"method", GUARDIAN_COMMISSION_NAME}, _errorAddressField("guardian", guardian),
+			errorField{"eventBlockNumber", eventBlockNumber}, errorField{"eventTxIndex", eventBlockTxIndex},
+			errorField{"currentBlockNumber", stateBlockNumber}, errorField{"currentTxIndex", stateBlockTxIndex}))
+	}
+
+	effectiveElectionIndex := _getProcessCurrentElectionIndex() + 1
+	_setGuardianCommission(guardian, commission, effectiveElectionIndex)
+	state.WriteUint64(_formatGuardianCommissionBlockNumberKey(guardian), eventBlockNumber)
+	state.WriteUint32(_formatGuardianCommissionBlockTxIndexKey(guardian), eventBlockTxIndex)
+	events.EmitEvent(GuardianCommissionMirrored, guardian, commission, effectiveElectionIndex, eventBlockNumber, eventBlockTxIndex)
+}
+
+func setGuardianCommission(guardian []byte, commission uint64) {
+	_requireGovernance("setGuardianCommission")
+	if commission > MAX_GUARDIAN_COMMISSION_BASIS_POINTS {
+		panic(_newError(ERROR_COMMISSION_ABOVE_MAX, fmt.Sprintf("commission of guardian %x failed since %d is above the maximum of %d basis points", guardian, commission, MAX_GUARDIAN_COMMISSION_BASIS_POINTS),
+			_errorAddressField("guardian", guardian), errorField{"value", commission}, errorField{"max", MAX_GUARDIAN_COMMISSION_BASIS_POINTS}))
+	}
+	effectiveElectionIndex := _getProcessCurrentElectionIndex() + 1
+	_setGuardianCommission(guardian, commission, effectiveElectionIndex)
+	events.EmitEvent(GuardianCommissionSet, guardian, commission, effectiveElectionIndex)
+}
+
+/***
+ * Guardian commission - data struct
+ * only the latest commission and the one it replaced are kept, elections are processed in order so no older one is needed.
+ */
+func _formatGuardianCommissionKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_Commission", hex.EncodeToString(guardian)))
+}
+
+func _formatGuardianPreviousCommissionKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_PreviousCommission", hex.EncodeToString(guardian)))
+}
+
+func _formatGuardianCommissionEffectiveElectionKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_CommissionEffectiveElection", hex.EncodeToString(guardian)))
+}
+
+func _formatGuardianCommissionBlockNumberKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_CommissionBlockNumber", hex.EncodeToString(guardian)))
+}
+
+func _formatGuardianCommissionBlockTxIndexKey(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_CommissionBlockTxIndex", hex.EncodeToString(guardian)))
+}
+
+func getGuardianCommission(guardian []byte) uint64 {
+	return state.ReadUint64(_formatGuardianCommissionKey(guardian))
+}
+
+func getGuardianCommissionEffectiveElectionIndex(guardian []byte) uint32 {
+	return state.ReadUint32(_formatGuardianCommissionEffectiveElectionKey(guardian))
+}
+
+func _setGuardianCommission(guardian []byte, commission uint64, effectiveElectionIndex uint32) {
+	if getGuardianCommissionEffectiveElectionIndex(guardian) <= _getProcessCurrentElectionIndex() { // current one is in force, keep it for the elections before the new one
+		state.WriteUint64(_formatGuardianPreviousCommissionKey(guardian), getGuardianCommission(guardian))
+	}
+	state.WriteUint64(_formatGuardianCommissionKey(guardian), commission)
+	state.WriteUint32(_formatGuardianCommissionEffectiveElectionKey(guardian), effectiveElectionIndex)
+}
+
+// commission in force at the given election, capped by MAX_GUARDIAN_COMMISSION_BASIS_POINTS
+func _getGuardianCommissionAtElection(guardian []byte, electionIndex uint32) uint64 {
+	commission := getGuardianCommission(guardian)
+	if getGuardianCommissionEffectiveElectionIndex(guardian) > electionIndex {
+		commission = state.ReadUint64(_formatGuardianPreviousCommissionKey(guardian))
+	}
+	if commission > MAX_GUARDIAN_COMMISSION_BASIS_POINTS {
+		return MAX_GUARDIAN_COMMISSION_BASIS_POINTS
+	}
+	return commission
+}
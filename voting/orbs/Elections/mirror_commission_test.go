@@ -0,0 +1,183 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func _setGuardiansCommissionAddressInTests(t *testing.T) {
+	previous := ETHEREUM_GUARDIANS_COMMISSION_ADDR
+	ETHEREUM_GUARDIANS_COMMISSION_ADDR = "0x9999999999999999999999999999999999999999"
+	t.Cleanup(func() {
+		ETHEREUM_GUARDIANS_COMMISSION_ADDR = previous
+	})
+}
+
+func TestOrbsVotingContract_mirrorGuardianCommission(t *testing.T) {
+	_setGuardiansCommissionAddressInTests(t)
+	txHex := "0xabcd"
+	guardianAddr := [20]byte{0x01}
+	blockNumber := 100000
+	txIndex := 10
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		electionTime := startTimeBasedGetElectionTime()
+		m.MockEthereumGetBlockTimeByNumber(blockNumber, int(electionTime)-10)
+		m.MockEthereumLog(getGuardiansCommissionEthereumContractAddress(), getGuardiansCommissionAbi(), txHex, GUARDIAN_COMMISSION_NAME, blockNumber, txIndex, func(out interface{}) {
+			v := out.(*CommissionUpdated)
+			v.Guardian = guardianAddr
+			v.CommissionBasisPoints = big.NewInt(1500)
+		})
+		effectiveElectionIndex := _getProcessCurrentElectionIndex() + 1
+		m.MockEmitEvent(GuardianCommissionMirrored, guardianAddr[:], uint64(1500), effectiveElectionIndex, uint64(blockNumber), uint32(txIndex))
+
+		mirrorGuardianCommission(txHex)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 1500, getGuardianCommission(guardianAddr[:]))
+		require.EqualValues(t, effectiveElectionIndex, getGuardianCommissionEffectiveElectionIndex(guardianAddr[:]))
+	})
+}
+
+func TestOrbsVotingContract_mirrorGuardianCommission_WrongValue(t *testing.T) {
+	_setGuardiansCommissionAddressInTests(t)
+	txHex := "0xabcd"
+	guardianAddr := [20]byte{0x01}
+	blockNumber := 100000
+	txIndex := 10
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		electionTime := startTimeBasedGetElectionTime()
+		m.MockEthereumGetBlockTimeByNumber(blockNumber, int(electionTime)-10)
+		m.MockEthereumLog(getGuardiansCommissionEthereumContractAddress(), getGuardiansCommissionAbi(), txHex, GUARDIAN_COMMISSION_NAME, blockNumber, txIndex, func(out interface{}) {
+			v := out.(*CommissionUpdated)
+			v.Guardian = guardianAddr
+			v.CommissionBasisPoints = big.NewInt(int64(COMMISSION_BASIS_POINTS_DENOMINATOR + 1))
+		})
+
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_WRONG_COMMISSION_VALUE, func() {
+			mirrorGuardianCommission(txHex)
+		}, "should panic because commission is above 100 percent")
+	})
+}
+
+func TestOrbsVotingContract_mirrorGuardianCommission_ContractNotDeployed(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		startTimeBasedGetElectionTime()
+
+		require.PanicsWithValue(t, "guardians commission contract is not deployed, there is no commission to mirror", func() {
+			mirrorGuardianCommission("0xabcd")
+		}, "should panic because there is no commission contract to read the event from")
+	})
+}
+
+func TestOrbsVotingContract_mirrorGuardianCommissionDataTwice(t *testing.T) {
+	guardianAddr := []byte{0x01}
+	eventBlockNumber := uint64(100000)
+	eventBlockTxIndex := uint32(10)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEmitEvent(GuardianCommissionMirrored, guardianAddr, uint64(1000), _getProcessCurrentElectionIndex()+1, eventBlockNumber, eventBlockTxIndex)
+
+		// call
+		_mirrorGuardianCommissionData(guardianAddr, 1000, eventBlockNumber, eventBlockTxIndex)
+
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_STALE_ORDERING, func() {
+			_mirrorGuardianCommissionData(guardianAddr, 2000, eventBlockNumber, eventBlockTxIndex)
+		}, "should panic because same info twice")
+		m.VerifyMocks()
+		require.EqualValues(t, 1000, getGuardianCommission(guardianAddr))
+	})
+}
+
+func TestOrbsVotingContract_mirrorGuardianCommissionData_EffectiveFromNextElection(t *testing.T) {
+	guardianAddr := []byte{0x01}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_setNumberOfElections(4)
+		current := _getProcessCurrentElectionIndex()
+		m.MockEmitEvent(GuardianCommissionMirrored, guardianAddr, uint64(1000), current+1, uint64(100), uint32(1))
+		m.MockEmitEvent(GuardianCommissionMirrored, guardianAddr, uint64(1500), current+1, uint64(101), uint32(1))
+		m.MockEmitEvent(GuardianCommissionMirrored, guardianAddr, uint64(500), current+2, uint64(102), uint32(1))
+
+		// call
+		_mirrorGuardianCommissionData(guardianAddr, 1000, 100, 1)
+		_mirrorGuardianCommissionData(guardianAddr, 1500, 101, 1) // replaces a commission not yet in force
+
+		// assert
+		require.EqualValues(t, 0, _getGuardianCommissionAtElection(guardianAddr, current), "current election keeps the commission delegators knew")
+		require.EqualValues(t, 1500, _getGuardianCommissionAtElection(guardianAddr, current+1))
+
+		// next election
+		_setNumberOfElections(current)
+		_mirrorGuardianCommissionData(guardianAddr, 500, 102, 1)
+
+		// assert
+		require.EqualValues(t, 1500, _getGuardianCommissionAtElection(guardianAddr, current+1), "commission in force is kept until the new one is effective")
+		require.EqualValues(t, 500, _getGuardianCommissionAtElection(guardianAddr, current+2))
+		m.VerifyMocks()
+	})
+}
+
+func TestOrbsVotingContract_getGuardianCommissionAtElection_Capped(t *testing.T) {
+	guardianAddr := []byte{0x01}
+	defer func(max uint64) { MAX_GUARDIAN_COMMISSION_BASIS_POINTS = max }(MAX_GUARDIAN_COMMISSION_BASIS_POINTS)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		MAX_GUARDIAN_COMMISSION_BASIS_POINTS = 2000
+		_setGuardianCommission(guardianAddr, 5000, _getProcessCurrentElectionIndex())
+
+		// assert
+		require.EqualValues(t, 5000, getGuardianCommission(guardianAddr), "mirrored value is kept as is")
+		require.EqualValues(t, 2000, _getGuardianCommissionAtElection(guardianAddr, _getProcessCurrentElectionIndex()))
+	})
+}
+
+func TestOrbsVotingContract_setGuardianCommission_ByGovernance(t *testing.T) {
+	governance := []byte{0x01, 0x02}
+	_setGovernanceInTests(t, governance)
+	guardianAddr := [20]byte{0x01}
+
+	InServiceScope(governance, nil, func(m Mockery) {
+		_init()
+		effectiveElectionIndex := _getProcessCurrentElectionIndex() + 1
+		m.MockEmitEvent(GuardianCommissionSet, guardianAddr[:], MAX_GUARDIAN_COMMISSION_BASIS_POINTS, effectiveElectionIndex)
+
+		// call
+		setGuardianCommission(guardianAddr[:], MAX_GUARDIAN_COMMISSION_BASIS_POINTS)
+
+		// assert
+		require.EqualValues(t, MAX_GUARDIAN_COMMISSION_BASIS_POINTS, getGuardianCommission(guardianAddr[:]))
+		require.EqualValues(t, effectiveElectionIndex, getGuardianCommissionEffectiveElectionIndex(guardianAddr[:]))
+		requirePanicsWithErrorCode(t, ERROR_COMMISSION_ABOVE_MAX, func() {
+			setGuardianCommission(guardianAddr[:], MAX_GUARDIAN_COMMISSION_BASIS_POINTS+1)
+		}, "should panic because commission is above the maximum")
+	})
+
+	InServiceScope([]byte{0x03}, nil, func(m Mockery) {
+		_init()
+
+		requirePanicsWithErrorCode(t, ERROR_NOT_GOVERNANCE, func() {
+			setGuardianCommission(guardianAddr[:], 500)
+		}, "should panic because signer is not the governance address")
+	})
+}
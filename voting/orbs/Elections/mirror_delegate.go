@@ -11,6 +11,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	"math/big"
 )
@@ -27,14 +28,17 @@ type Transfer struct {
 func mirrorDelegationByTransfer(hexEncodedEthTxHash string) {
 	_initCurrentElection()
 	if hasProcessingStarted() == 1 {
-		panic(fmt.Errorf("proccessing has started cannot mirror now, resubmit next election"))
+		panic(_newError(ERROR_PROCESSING_STARTED, "proccessing has started cannot mirror now, resubmit next election",
+			errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
 	}
 
 	e := &Transfer{}
 	eventBlockNumber, eventBlockTxIndex := ethereum.GetTransactionLog(getTokenEthereumContractAddress(), getTokenAbi(), hexEncodedEthTxHash, DELEGATION_BY_TRANSFER_NAME, e)
 
-	if DELEGATION_BY_TRANSFER_VALUE.Cmp(e.Value) != 0 {
-		panic(fmt.Errorf("mirrorDelegateByTransfer from %x to %x failed since %d is wrong delegation value", e.From, e.To, e.Value.Uint64()))
+	if e.Value == nil || DELEGATION_BY_TRANSFER_VALUE.Cmp(e.Value) != 0 {
+		panic(_newError(ERROR_MIRROR_WRONG_TRANSFER_VALUE, fmt.Sprintf("mirrorDelegateByTransfer from %x to %x failed since %d is wrong delegation value", e.From, e.To, e.Value),
+			_errorAddressField("delegator", e.From[:]), _errorAddressField("agent", e.To[:]),
+			errorField{"value", e.Value}, errorField{"expectedValue", DELEGATION_BY_TRANSFER_VALUE}))
 	}
 
 	_mirrorDelegateImpl(e.From[:], e.To[:], eventBlockNumber, eventBlockTxIndex, DELEGATION_BY_TRANSFER_NAME)
@@ -49,7 +53,8 @@ type Delegate struct {
 func mirrorDelegation(hexEncodedEthTxHash string) {
 	_initCurrentElection()
 	if hasProcessingStarted() == 1 {
-		panic(fmt.Errorf("proccessing has started cannot mirror now, resubmit next election"))
+		panic(_newError(ERROR_PROCESSING_STARTED, "proccessing has started cannot mirror now, resubmit next election",
+			errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
 	}
 
 	e := &Delegate{}
@@ -60,8 +65,10 @@ func mirrorDelegation(hexEncodedEthTxHash string) {
 
 func _mirrorDelegateImpl(delegator []byte, agent []byte, eventBlockNumber uint64, eventBlockTxIndex uint32, eventName string) {
 	if _isMirrorDelegationDataAfterElection(eventBlockNumber) {
-		panic(fmt.Errorf("delegate with medthod %s from %x to %x failed since it happened in block number %d which is after election date, resubmit next election",
-			eventName, delegator, agent, eventBlockNumber))
+		panic(_newError(ERROR_MIRROR_AFTER_ELECTION, fmt.Sprintf("delegate with medthod %s from %x to %x failed since it happened in block number %d which is after election date, resubmit next election",
+			eventName, delegator, agent, eventBlockNumber),
+			errorField{"method", eventName}, _errorAddressField("delegator", delegator), _errorAddressField("agent", agent),
+			errorField{"eventBlockNumber", eventBlockNumber}))
 	}
 	_mirrorDelegationData(delegator, agent, eventBlockNumber, eventBlockTxIndex, eventName)
 }
@@ -84,16 +91,21 @@ func _mirrorDelegationData(delegator []byte, agent []byte, eventBlockNumber uint
 	stateMethod := state.ReadString(_formatDelegatorMethod(delegator))
 	stateBlockNumber := uint64(0)
 	if stateMethod == DELEGATION_NAME && eventName == DELEGATION_BY_TRANSFER_NAME {
-		panic(fmt.Errorf("delegate with medthod %s from %x to %x failed since already have delegation with method %s",
-			eventName, delegator, agent, stateMethod))
+		panic(_newError(ERROR_MIRROR_DELEGATE_OVERRIDES_TRANSFER, fmt.Sprintf("delegate with medthod %s from %x to %x failed since already have delegation with method %s",
+			eventName, delegator, agent, stateMethod),
+			errorField{"method", eventName}, _errorAddressField("delegator", delegator), _errorAddressField("agent", agent),
+			errorField{"currentMethod", stateMethod}))
 	} else if stateMethod == DELEGATION_BY_TRANSFER_NAME && eventName == DELEGATION_NAME {
 		stateBlockNumber = eventBlockNumber
 	} else if stateMethod == eventName {
 		stateBlockNumber = state.ReadUint64(_formatDelegatorBlockNumberKey(delegator))
 		stateBlockTxIndex := state.ReadUint32(_formatDelegatorBlockTxIndexKey(delegator))
 		if stateBlockNumber > eventBlockNumber || (stateBlockNumber == eventBlockNumber && stateBlockTxIndex >= eventBlockTxIndex) {
-			panic(fmt.Errorf("delegate from %x to %x with block-height %d and tx-index %d failed since current delegation is from block-height %d and tx-index %d",
-				delegator, agent, eventBlockNumber, eventBlockTxIndex, stateBlockNumber, stateBlockTxIndex))
+			panic(_newError(ERROR_MIRROR_STALE_ORDERING, fmt.Sprintf("delegate from %x to %x with block-height %d and tx-index %d failed since current delegation is from block-height %d and tx-index %d",
+				delegator, agent, eventBlockNumber, eventBlockTxIndex, stateBlockNumber, stateBlockTxIndex),
+				errorField{"method", eventName}, _errorAddressField("delegator", delegator), _errorAddressField("agent", agent),
+				errorField{"eventBlockNumber", eventBlockNumber}, errorField{"eventTxIndex", eventBlockTxIndex},
+				errorField{"currentBlockNumber", stateBlockNumber}, errorField{"currentTxIndex", stateBlockTxIndex}))
 		}
 	}
 
@@ -111,6 +123,7 @@ func _mirrorDelegationData(delegator []byte, agent []byte, eventBlockNumber uint
 	state.WriteUint64(_formatDelegatorBlockNumberKey(delegator), eventBlockNumber)
 	state.WriteUint32(_formatDelegatorBlockTxIndexKey(delegator), eventBlockTxIndex)
 	state.WriteString(_formatDelegatorMethod(delegator), eventName)
+	events.EmitEvent(DelegationMirrored, delegator, agent, eventName, eventBlockNumber, eventBlockTxIndex)
 }
 
 /***
@@ -163,3 +176,7 @@ func _formatDelegatorMethod(delegator []byte) []byte {
 func _formatDelegatorStakeKey(delegator []byte) []byte {
 	return []byte(fmt.Sprintf("Delegator_%s_Stake", hex.EncodeToString(delegator)))
 }
+
+func _formatDelegatorStakeInWeiKey(delegator []byte) []byte {
+	return []byte(fmt.Sprintf("Delegator_%s_StakeInWei", hex.EncodeToString(delegator)))
+}
@@ -53,7 +53,7 @@ func TestOrbsVotingContract_mirrorDelegateImpl_EventBlockNumberAfterElectionBloc
 		_setCurrentElectionBlockNumber_InTests(eventBlockNumber - 500)
 
 		//assert
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_AFTER_ELECTION, func() {
 			_mirrorDelegateImpl(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 		}, "should panic because event is too new")
 	})
@@ -76,6 +76,7 @@ func TestOrbsVotingContract_mirrorDelegation_AllGood_blockBased(t *testing.T) {
 			v.Delegator = delegatorAddr
 			v.To = agentAddr
 		})
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr[:], agentAddr[:], DELEGATION_NAME, uint64(blockNumber), uint32(txIndex))
 
 		mirrorDelegation(txHex)
 
@@ -107,6 +108,7 @@ func TestOrbsVotingContract_mirrorDelegationByTransfer_AllGood_blockBased(t *tes
 			v.To = agentAddr
 			v.Value = value
 		})
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr[:], agentAddr[:], DELEGATION_BY_TRANSFER_NAME, uint64(blockNumber), uint32(txIndex))
 
 		// call
 		mirrorDelegationByTransfer(txHex)
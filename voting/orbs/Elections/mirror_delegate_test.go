@@ -23,6 +23,7 @@ func TestOrbsVotingContract_mirrorDelegationData(t *testing.T) {
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr, agentAddr, eventName, eventBlockNumber, eventBlockTxIndex)
 
 		// call
 		_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
@@ -44,11 +45,12 @@ func TestOrbsVotingContract_mirrorDelegationDataTwice(t *testing.T) {
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr, agentAddr, eventName, eventBlockNumber, eventBlockTxIndex)
 
 		// call
 		_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_STALE_ORDERING, func() {
 			_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 		}, "should panic because same infor twice")
 	})
@@ -67,7 +69,7 @@ func TestOrbsVotingContract_mirrorDelegationData_TransferDoesNotReplaceDelegate(
 		// prepare
 		state.WriteString(_formatDelegatorMethod(delegatorAddr), DELEGATION_NAME)
 
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_DELEGATE_OVERRIDES_TRANSFER, func() {
 			_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 		}, "should panic because newer delegate")
 	})
@@ -91,6 +93,7 @@ func TestOrbsVotingContract_mirrorDelegationData_DelegateReplacesTransfer(t *tes
 		state.WriteString(_formatDelegatorMethod(delegatorAddr), DELEGATION_BY_TRANSFER_NAME)
 		state.WriteUint64(_formatDelegatorBlockNumberKey(delegatorAddr), eventBlockNumber+5)
 		state.WriteUint32(_formatDelegatorBlockTxIndexKey(delegatorAddr), 50)
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr, agentAddr, eventName, eventBlockNumber, eventBlockTxIndex)
 
 		// call
 		_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
@@ -120,6 +123,7 @@ func TestOrbsVotingContract_mirrorDelegationData_DelegateReset(t *testing.T) {
 		state.WriteString(_formatDelegatorMethod(delegatorAddr), DELEGATION_NAME)
 		state.WriteUint64(_formatDelegatorBlockNumberKey(delegatorAddr), eventBlockNumber-5)
 		state.WriteUint32(_formatDelegatorBlockTxIndexKey(delegatorAddr), 1)
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr, emptyAddre[:], eventName, eventBlockNumber, eventBlockTxIndex)
 
 		// call
 		_mirrorDelegationData(delegatorAddr, delegatorAddr, eventBlockNumber, eventBlockTxIndex, eventName)
@@ -146,7 +150,7 @@ func TestOrbsVotingContract_mirrorDelegationData_AlreadyHaveNewerEventBlockNumbe
 		state.WriteString(_formatDelegatorMethod(delegatorAddr), eventName)
 		state.WriteUint64(_formatDelegatorBlockNumberKey(delegatorAddr), eventBlockNumber+1)
 
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_STALE_ORDERING, func() {
 			_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 		}, "should panic because newer block")
 	})
@@ -167,7 +171,7 @@ func TestOrbsVotingContract_mirrorDelegationData_AlreadyHaveNewerEventBlockTxInd
 		state.WriteUint64(_formatDelegatorBlockNumberKey(delegatorAddr), eventBlockNumber)
 		state.WriteUint32(_formatDelegatorBlockTxIndexKey(delegatorAddr), 50)
 
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_STALE_ORDERING, func() {
 			_mirrorDelegationData(delegatorAddr, agentAddr, eventBlockNumber, eventBlockTxIndex, eventName)
 		}, "should panic because newer tx index")
 	})
@@ -224,7 +228,7 @@ func TestOrbsVotingContract_mirrorDelegateImpl_EventAfterElectionTime(t *testing
 		m.MockEthereumGetBlockTimeByNumber(eventBlockNumber, int(electionTime)+10)
 
 		//assert
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_AFTER_ELECTION, func() {
 			_mirrorDelegateImpl(delegatorAddr, agentAddr, uint64(eventBlockNumber), eventBlockTxIndex, eventName)
 		}, "should panic because event is too new")
 	})
@@ -235,9 +239,10 @@ func TestOrbsVotingContract_mirrorDelegation_processStarted(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
 		// prepare
+		_setCurrentElectionBlockNumber_InTests(50000)
 		_setVotingProcessState("x")
 
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_PROCESSING_STARTED, func() {
 			mirrorDelegation(txHex)
 		}, "should panic because mirror period should have ended")
 	})
@@ -248,8 +253,9 @@ func TestOrbsVotingContract_mirrorDelegationByTransfer_processStarted(t *testing
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
 		// prepare
+		_setCurrentElectionBlockNumber_InTests(50000)
 		_setVotingProcessState("x")
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_PROCESSING_STARTED, func() {
 			mirrorDelegationByTransfer(txHex)
 		}, "should panic because mirror period should have ended")
 	})
@@ -273,6 +279,7 @@ func TestOrbsVotingContract_mirrorDelegation(t *testing.T) {
 			v.Delegator = delegatorAddr
 			v.To = agentAddr
 		})
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr[:], agentAddr[:], DELEGATION_NAME, uint64(blockNumber), uint32(txIndex))
 
 		mirrorDelegation(txHex)
 
@@ -305,6 +312,7 @@ func TestOrbsVotingContract_mirrorDelegationByTransfer(t *testing.T) {
 			v.To = agentAddr
 			v.Value = value
 		})
+		m.MockEmitEvent(DelegationMirrored, delegatorAddr[:], agentAddr[:], DELEGATION_BY_TRANSFER_NAME, uint64(blockNumber), uint32(txIndex))
 
 		// call
 		mirrorDelegationByTransfer(txHex)
@@ -333,7 +341,7 @@ func TestOrbsVotingContract_mirrorDelegationByTransfer_WrongValue(t *testing.T)
 		})
 
 		// call
-		require.Panics(t, func() {
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_WRONG_TRANSFER_VALUE, func() {
 			mirrorDelegationByTransfer(txHex)
 		}, "should panic because bad transfer value")
 	})
@@ -0,0 +1,119 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+)
+
+/***
+ * Mirror : reward recipient
+ * a mirrored recipient receives the rewards of the current election onward, recipients are not chained.
+ * recipients are set on the reward recipients contract, the deployed voting contract is left as is.
+ */
+type RewardRecipientSet struct {
+	Stakeholder [20]byte
+	Recipient   [20]byte
+}
+
+func mirrorRewardRecipient(hexEncodedEthTxHash string) {
+	_initCurrentElection()
+	if hasProcessingStarted() == 1 {
+		panic(_newError(ERROR_PROCESSING_STARTED, "proccessing has started cannot mirror now, resubmit next election",
+			errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
+	}
+
+	if getRewardRecipientsEthereumContractAddress() == "" {
+		panic("reward recipients contract is not deployed, there is no reward recipient to mirror")
+	}
+
+	e := &RewardRecipientSet{}
+	eventBlockNumber, eventBlockTxIndex := ethereum.GetTransactionLog(getRewardRecipientsEthereumContractAddress(), getRewardRecipientsAbi(), hexEncodedEthTxHash, REWARD_RECIPIENT_NAME, e)
+
+	if _isMirrorDelegationDataAfterElection(eventBlockNumber) {
+		panic(_newError(ERROR_MIRROR_AFTER_ELECTION, fmt.Sprintf("reward recipient of %x to %x failed since it happened in block number %d which is after election date, resubmit next election",
+			e.Stakeholder, e.Recipient, eventBlockNumber),
+			errorField{"method", REWARD_RECIPIENT_NAME}, _errorAddressField("stakeholder", e.Stakeholder[:]), _errorAddressField("recipient", e.Recipient[:]),
+			errorField{"eventBlockNumber", eventBlockNumber}))
+	}
+
+	_mirrorRewardRecipientData(e.Stakeholder[:], e.Recipient[:], eventBlockNumber, eventBlockTxIndex)
+}
+
+func _mirrorRewardRecipientData(stakeholder []byte, recipient []byte, eventBlockNumber uint64, eventBlockTxIndex uint32) {
+	stateBlockNumber := state.ReadUint64(_formatRewardRecipientBlockNumberKey(stakeholder))
+	stateBlockTxIndex := state.ReadUint32(_formatRewardRecipientBlockTxIndexKey(stakeholder))
+	if stateBlockNumber > eventBlockNumber || (stateBlockNumber == eventBlockNumber && stateBlockTxIndex >= eventBlockTxIndex) {
+		panic(_newError(ERROR_MIRROR_STALE_ORDERING, fmt.Sprintf("reward recipient of %x to %x with block-height %d and tx-index %d failed since current recipient is from block-height %d and tx-index %d",
+			stakeholder, recipient, eventBlockNumber, eventBlockTxIndex, stateBlockNumber, stateBlockTxIndex),
+			errorField{"method", REWARD_RECIPIENT_NAME}, _errorAddressField("stakeholder", stakeholder), _errorAddressField("recipient", recipient),
+			errorField{"eventBlockNumber", eventBlockNumber}, errorField{"eventTxIndex", eventBlockTxIndex},
+			errorField{"currentBlockNumber", stateBlockNumber}, errorField{"currentTxIndex", stateBlockTxIndex}))
+	}
+
+	emptyAddr := [20]byte{}
+	if bytes.Equal(stakeholder, recipient) {
+		recipient = emptyAddr[:]
+	}
+
+	effectiveElectionIndex := _getProcessCurrentElectionIndex()
+	state.WriteBytes(_formatRewardRecipientKey(stakeholder), recipient)
+	state.WriteUint32(_formatRewardRecipientEffectiveElectionKey(stakeholder), effectiveElectionIndex)
+	state.WriteUint64(_formatRewardRecipientBlockNumberKey(stakeholder), eventBlockNumber)
+	state.WriteUint32(_formatRewardRecipientBlockTxIndexKey(stakeholder), eventBlockTxIndex)
+	events.EmitEvent(RewardRecipientMirrored, stakeholder, recipient, effectiveElectionIndex, eventBlockNumber, eventBlockTxIndex)
+}
+
+/***
+ * Reward recipient - data struct
+ */
+func _formatRewardRecipientKey(stakeholder []byte) []byte {
+	return []byte(fmt.Sprintf("Address_%s_RewardRecipient", hex.EncodeToString(stakeholder)))
+}
+
+func _formatRewardRecipientEffectiveElectionKey(stakeholder []byte) []byte {
+	return []byte(fmt.Sprintf("Address_%s_RewardRecipientEffectiveElection", hex.EncodeToString(stakeholder)))
+}
+
+func _formatRewardRecipientBlockNumberKey(stakeholder []byte) []byte {
+	return []byte(fmt.Sprintf("Address_%s_RewardRecipientBlockNumber", hex.EncodeToString(stakeholder)))
+}
+
+func _formatRewardRecipientBlockTxIndexKey(stakeholder []byte) []byte {
+	return []byte(fmt.Sprintf("Address_%s_RewardRecipientBlockTxIndex", hex.EncodeToString(stakeholder)))
+}
+
+// returns the zero address when the stakeholder receives its own rewards
+func getRewardRecipient(stakeholder []byte) []byte {
+	recipient := _addressSliceToArray(state.ReadBytes(_formatRewardRecipientKey(stakeholder)))
+	return recipient[:]
+}
+
+func getRewardRecipientEffectiveElectionIndex(stakeholder []byte) uint32 {
+	return state.ReadUint32(_formatRewardRecipientEffectiveElectionKey(stakeholder))
+}
+
+func _getRewardRecipientOrSelf(stakeholder []byte) []byte {
+	recipient := _addressSliceToArray(state.ReadBytes(_formatRewardRecipientKey(stakeholder)))
+	if recipient == [20]byte{} {
+		return stakeholder
+	}
+	return recipient[:]
+}
+
+// a recipient is not used for the rewards of elections before the one it was mirrored in
+func _getRewardRecipientAtElection(stakeholder []byte, electionIndex uint32) []byte {
+	if getRewardRecipientEffectiveElectionIndex(stakeholder) > electionIndex {
+		return stakeholder
+	}
+	return _getRewardRecipientOrSelf(stakeholder)
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func _setRewardRecipientsAddressInTests(t *testing.T) {
+	previous := ETHEREUM_REWARD_RECIPIENTS_ADDR
+	ETHEREUM_REWARD_RECIPIENTS_ADDR = "0x8888888888888888888888888888888888888888"
+	t.Cleanup(func() {
+		ETHEREUM_REWARD_RECIPIENTS_ADDR = previous
+	})
+}
+
+func TestOrbsVotingContract_mirrorRewardRecipient(t *testing.T) {
+	_setRewardRecipientsAddressInTests(t)
+	txHex := "0xabcd"
+	stakeholderAddr := [20]byte{0x01}
+	recipientAddr := [20]byte{0x02}
+	blockNumber := 100000
+	txIndex := 10
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		electionTime := startTimeBasedGetElectionTime()
+		m.MockEthereumGetBlockTimeByNumber(blockNumber, int(electionTime)-10)
+		m.MockEthereumLog(getRewardRecipientsEthereumContractAddress(), getRewardRecipientsAbi(), txHex, REWARD_RECIPIENT_NAME, blockNumber, txIndex, func(out interface{}) {
+			v := out.(*RewardRecipientSet)
+			v.Stakeholder = stakeholderAddr
+			v.Recipient = recipientAddr
+		})
+		m.MockEmitEvent(RewardRecipientMirrored, stakeholderAddr[:], recipientAddr[:], _getProcessCurrentElectionIndex(), uint64(blockNumber), uint32(txIndex))
+
+		mirrorRewardRecipient(txHex)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, recipientAddr[:], getRewardRecipient(stakeholderAddr[:]))
+		require.EqualValues(t, _getProcessCurrentElectionIndex(), getRewardRecipientEffectiveElectionIndex(stakeholderAddr[:]))
+		require.EqualValues(t, recipientAddr[:], _getRewardRecipientOrSelf(stakeholderAddr[:]))
+	})
+}
+
+func TestOrbsVotingContract_mirrorRewardRecipient_ContractNotDeployed(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		startTimeBasedGetElectionTime()
+
+		require.PanicsWithValue(t, "reward recipients contract is not deployed, there is no reward recipient to mirror", func() {
+			mirrorRewardRecipient("0xabcd")
+		}, "should panic because there is no reward recipients contract to read the event from")
+	})
+}
+
+func TestOrbsVotingContract_mirrorRewardRecipientDataTwice(t *testing.T) {
+	stakeholderAddr := []byte{0x01}
+	recipientAddr := []byte{0x02}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEmitEvent(RewardRecipientMirrored, stakeholderAddr, recipientAddr, _getProcessCurrentElectionIndex(), uint64(100000), uint32(10))
+
+		// call
+		_mirrorRewardRecipientData(stakeholderAddr, recipientAddr, 100000, 10)
+
+		requirePanicsWithErrorCode(t, ERROR_MIRROR_STALE_ORDERING, func() {
+			_mirrorRewardRecipientData(stakeholderAddr, recipientAddr, 100000, 10)
+		}, "should panic because same info twice")
+		m.VerifyMocks()
+	})
+}
+
+func TestOrbsVotingContract_mirrorRewardRecipientData_SelfClearsRecipient(t *testing.T) {
+	stakeholderAddr := [20]byte{0x01}
+	recipientAddr := [20]byte{0x02}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEmitEvent(RewardRecipientMirrored, stakeholderAddr[:], recipientAddr[:], _getProcessCurrentElectionIndex(), uint64(100000), uint32(10))
+		m.MockEmitEvent(RewardRecipientMirrored, stakeholderAddr[:], make([]byte, 20), _getProcessCurrentElectionIndex(), uint64(100001), uint32(10))
+
+		// call
+		_mirrorRewardRecipientData(stakeholderAddr[:], recipientAddr[:], 100000, 10)
+		_mirrorRewardRecipientData(stakeholderAddr[:], stakeholderAddr[:], 100001, 10)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, make([]byte, 20), getRewardRecipient(stakeholderAddr[:]))
+		require.EqualValues(t, stakeholderAddr[:], _getRewardRecipientOrSelf(stakeholderAddr[:]))
+	})
+}
+
+func TestOrbsVotingContract_getRewardRecipientAtElection_NotBeforeEffective(t *testing.T) {
+	stakeholderAddr := [20]byte{0x01}
+	recipientAddr := [20]byte{0x02}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		current := _getProcessCurrentElectionIndex()
+		m.MockEmitEvent(RewardRecipientMirrored, stakeholderAddr[:], recipientAddr[:], current, uint64(100000), uint32(10))
+
+		// call
+		_mirrorRewardRecipientData(stakeholderAddr[:], recipientAddr[:], 100000, 10)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, stakeholderAddr[:], _getRewardRecipientAtElection(stakeholderAddr[:], current-1), "earlier elections keep their stakeholder")
+		require.EqualValues(t, recipientAddr[:], _getRewardRecipientAtElection(stakeholderAddr[:], current))
+		require.EqualValues(t, recipientAddr[:], _getRewardRecipientAtElection(stakeholderAddr[:], current+1))
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+	"sort"
+)
+
+/***
+ * Vote out ban : a validator that left the committee by vote out is not eligible for the next VOTE_OUT_BAN_LENGTH_IN_ELECTIONS elections.
+ * The ban is lifted early only by a fresh supermajority that reinstates it : guardians holding VOTE_OUT_BAN_LIFT_WEIGHT_PERCENT of the total
+ * voting stake of this election voted to reinstate it on ethereum within the valid vote period, so guardians that do not vote leave the ban in force.
+ * When too few validators are left banned validators are let back first (earliest ban end, then address) before falling back to all validators.
+ */
+func _removeBannedValidators(winners [][20]byte, totalVotes *big.Int) (stillWinners [][20]byte, banned [][20]byte) {
+	electionIndex := _getProcessCurrentElectionIndex()
+	stillWinners = make([][20]byte, 0, len(winners))
+	var reinstateVotes map[[20]byte]*big.Int
+	for _, validator := range winners {
+		if !_isValidatorBannedAtIndex(validator[:], electionIndex) {
+			stillWinners = append(stillWinners, validator)
+			continue
+		}
+		if reinstateVotes == nil {
+			reinstateVotes = _collectReinstateVotes()
+		}
+		if reinstateVote := _getStakeOrZero(reinstateVotes, validator); _isVoteOutBanLifted(reinstateVote, totalVotes) {
+			fmt.Printf("elections %10d: vote out ban of %x lifted by %d reinstate votes\n", _getProcessCurrentElectionBlockNumber(), validator, reinstateVote)
+			_clearValidatorVoteOutBan(validator[:])
+			stillWinners = append(stillWinners, validator)
+		} else {
+			fmt.Printf("elections %10d: candidate %x is banned until election %d\n", _getProcessCurrentElectionBlockNumber(), validator, getValidatorVoteOutBanEndElectionIndex(validator[:]))
+			banned = append(banned, validator)
+		}
+	}
+	return stillWinners, banned
+}
+
+func _isVoteOutBanLifted(reinstateVote *big.Int, totalVotes *big.Int) bool {
+	threshold := new(big.Int).Mul(totalVotes, new(big.Int).SetUint64(VOTE_OUT_BAN_LIFT_WEIGHT_PERCENT))
+	threshold.Div(threshold, big.NewInt(100))
+	return totalVotes.Sign() > 0 && reinstateVote.Cmp(threshold) >= 0
+}
+
+// a reinstate vote weighs as the voting weight of its guardian in this election, read only when a banned validator could be elected
+func _collectReinstateVotes() map[[20]byte]*big.Int {
+	reinstateVotes := make(map[[20]byte]*big.Int)
+	if getValidatorsReinstatementEthereumContractAddress() == "" {
+		return reinstateVotes
+	}
+	numOfGuardians := _getNumberOfGuardians()
+	for i := 0; i < numOfGuardians; i++ {
+		guardian := _getGuardianAtIndex(i)
+		votingWeight := _getGuardianVotingWeightInWei(guardian[:])
+		if votingWeight.Sign() == 0 {
+			continue
+		}
+		out := Vote{}
+		ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getValidatorsReinstatementEthereumContractAddress(), getValidatorsReinstatementAbi(), "getCurrentReinstateVoteBytes20", &out, guardian)
+		voteBlockNumber := out.BlockNumber.Uint64()
+		if voteBlockNumber == 0 || voteBlockNumber < _getProcessCurrentElectionEarliestValidVoteBlockNumber() {
+			continue
+		}
+		for _, validator := range out.ValidatorsBytes20 {
+			fmt.Printf("elections %10d: guardian %x, voted to reinstate %x\n", _getProcessCurrentElectionBlockNumber(), guardian, validator)
+			reinstateVotes[validator] = _addWei(_getStakeOrZero(reinstateVotes, validator), votingWeight)
+		}
+	}
+	return reinstateVotes
+}
+
+func _reinstateBannedValidators(winners [][20]byte, banned [][20]byte) ([][20]byte, [][20]byte) {
+	sort.Slice(banned, func(i, j int) bool { // earliest ban end first
+		iEnd, jEnd := getValidatorVoteOutBanEndElectionIndex(banned[i][:]), getValidatorVoteOutBanEndElectionIndex(banned[j][:])
+		return iEnd < jEnd || (iEnd == jEnd && bytes.Compare(banned[i][:], banned[j][:]) < 0)
+	})
+	for len(winners) < MIN_ELECTED_VALIDATORS && len(banned) > 0 {
+		fmt.Printf("elections %10d: banned candidate %x is needed to keep minimum committee size\n", _getProcessCurrentElectionBlockNumber(), banned[0])
+		winners = append(winners, banned[0])
+		banned = banned[1:]
+	}
+	return winners, banned
+}
+
+func _banVotedOutValidators(leaving [][20]byte, banned [][20]byte) {
+	if VOTE_OUT_BAN_LENGTH_IN_ELECTIONS == 0 {
+		return
+	}
+	isBanned := _addressSet(banned)
+	banEnd := _getProcessCurrentElectionIndex() + VOTE_OUT_BAN_LENGTH_IN_ELECTIONS
+	for _, validator := range leaving {
+		if !isBanned[validator] && getValidatorVoteOutBanEndElectionIndex(validator[:]) < banEnd {
+			_setValidatorVoteOutBanEndElectionIndex(validator[:], banEnd)
+		}
+	}
+}
+
+func _isValidatorBannedAtIndex(validator []byte, electionIndex uint32) bool {
+	return getValidatorVoteOutBanEndElectionIndex(validator) >= electionIndex
+}
+
+/***
+ * Vote out ban - data struct
+ */
+func _formatValidatorVoteOutBanEndKey(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_VoteOutBanEnd", hex.EncodeToString(validator)))
+}
+
+func getValidatorVoteOutBanEndElectionIndex(validator []byte) uint32 {
+	return state.ReadUint32(_formatValidatorVoteOutBanEndKey(validator))
+}
+
+func isValidatorVoteOutBanned(validator []byte) uint32 {
+	if _isValidatorBannedAtIndex(validator, getNumberOfElections()+1) {
+		return 1
+	}
+	return 0
+}
+
+func _setValidatorVoteOutBanEndElectionIndex(validator []byte, electionIndex uint32) {
+	state.WriteUint32(_formatValidatorVoteOutBanEndKey(validator), electionIndex)
+}
+
+func _clearValidatorVoteOutBan(validator []byte) {
+	state.Clear(_formatValidatorVoteOutBanEndKey(validator))
+}
+
+func _formatElectionBannedValidators(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_BannedValidators", index))
+}
+
+func getBannedValidatorsByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatElectionBannedValidators(index))
+}
+
+func _setElectionBannedValidatorsAtIndex(index uint32, validators [][20]byte) {
+	state.WriteBytes(_formatElectionBannedValidators(index), _concatElectedEthereumAddresses(validators))
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func _runValidatorsSelection_InTests(m Mockery, candidateVotes map[[20]byte]uint64, totalVotes uint64) [][20]byte {
+	elected := _processValidatorsSelection(weiStakes(candidateVotes), _toWei(totalVotes))
+	electionBlockNumber := uint64(getNumberOfElections()+1) * ELECTION_PERIOD_LENGTH_IN_BLOCKS
+	mockElectionCompletedEvent(m, getNumberOfElections()+1, electionBlockNumber, elected...)
+	_setElectedValidators(elected, 0, electionBlockNumber)
+	return elected
+}
+
+func _setBanTestVariables(minElected int, banLength uint32) {
+	MIN_ELECTED_VALIDATORS = minElected
+	MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 7
+	VOTE_OUT_BAN_LENGTH_IN_ELECTIONS = banLength
+	VOTE_OUT_BAN_LIFT_WEIGHT_PERCENT = 70
+}
+
+func TestOrbsVotingContract_processBan_StaysBannedWhenVotesExpire(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 2)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		require.Equal(t, [][20]byte{v1, v3}, elected)
+		require.EqualValues(t, 3, getValidatorVoteOutBanEndElectionIndex(v2[:]))
+		require.EqualValues(t, 1, isValidatorVoteOutBanned(v2[:]))
+
+		elected = _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 0)
+		require.Equal(t, [][20]byte{v1, v3}, elected, "still banned in first election after vote out")
+		require.Equal(t, v2[:], getBannedValidatorsByIndex(2))
+
+		elected = _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 200)
+		require.Equal(t, [][20]byte{v1, v3}, elected, "small fresh vote does not lift the ban")
+
+		elected = _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 0)
+		require.Equal(t, [][20]byte{v1, v2, v3}, elected, "ban over after two elections")
+		require.EqualValues(t, 0, isValidatorVoteOutBanned(v2[:]))
+		m.VerifyMocks()
+	})
+}
+
+func _setReinstateTestGuardians(votingWeights map[[20]byte]uint64, guardians ...[20]byte) func() {
+	ETHEREUM_VALIDATORS_REINSTATEMENT_ADDR = "0x0000000000000000000000000000000000000777"
+	_setProcessCurrentElection(0, 1000, 500)
+	_setGuardians(guardians)
+	for i := range guardians {
+		_setGuardianVotingWeight(guardians[i][:], votingWeights[guardians[i]])
+	}
+	return func() {
+		ETHEREUM_VALIDATORS_REINSTATEMENT_ADDR = ""
+	}
+}
+
+func TestOrbsVotingContract_processBan_LiftedByReinstateSupermajority(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+	g1, g2, g3 := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xa3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		defer _setReinstateTestGuardians(map[[20]byte]uint64{g1: 500, g2: 200, g3: 300}, g1, g2, g3)()
+		mockReinstateVoteInEthereum(m, 1000, g1, [][20]byte{v2}, 800)
+		mockReinstateVoteInEthereum(m, 1000, g2, [][20]byte{v1, v2}, 600)
+		mockReinstateVoteInEthereum(m, 1000, g3, [][20]byte{}, 0)
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v2, v3}, elected)
+		require.Zero(t, getValidatorVoteOutBanEndElectionIndex(v2[:]))
+		require.Empty(t, getBannedValidatorsByIndex(2))
+	})
+}
+
+func TestOrbsVotingContract_processBan_NotLiftedWithoutReinstateVote(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+	g1, g2 := [20]byte{0xa1}, [20]byte{0xa2}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		defer _setReinstateTestGuardians(map[[20]byte]uint64{g1: 700, g2: 300}, g1, g2)()
+		mockReinstateVoteInEthereum(m, 1000, g1, [][20]byte{}, 0)
+		mockReinstateVoteInEthereum(m, 1000, g2, [][20]byte{}, 0)
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v3}, elected, "guardians that forget to vote it out again do not lift the ban")
+		require.EqualValues(t, 6, getValidatorVoteOutBanEndElectionIndex(v2[:]))
+	})
+}
+
+func TestOrbsVotingContract_processBan_NotLiftedBelowReinstateSupermajority(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+	g1, g2 := [20]byte{0xa1}, [20]byte{0xa2}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		defer _setReinstateTestGuardians(map[[20]byte]uint64{g1: 699, g2: 301}, g1, g2)()
+		mockReinstateVoteInEthereum(m, 1000, g1, [][20]byte{v2}, 800)
+		mockReinstateVoteInEthereum(m, 1000, g2, [][20]byte{}, 0)
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v3}, elected)
+		require.EqualValues(t, 6, getValidatorVoteOutBanEndElectionIndex(v2[:]), "ban is not extended without a new vote out")
+	})
+}
+
+func TestOrbsVotingContract_processBan_NotLiftedByExpiredReinstateVote(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+	g1, g2 := [20]byte{0xa1}, [20]byte{0xa2}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		defer _setReinstateTestGuardians(map[[20]byte]uint64{g1: 700, g2: 300}, g1, g2)()
+		mockReinstateVoteInEthereum(m, 1000, g1, [][20]byte{v2}, 400)
+		mockReinstateVoteInEthereum(m, 1000, g2, [][20]byte{v2}, 800)
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v3}, elected, "a reinstate vote older than the valid vote period does not count")
+		require.EqualValues(t, 6, getValidatorVoteOutBanEndElectionIndex(v2[:]))
+	})
+}
+
+func TestOrbsVotingContract_processBan_ExtendedByNewVoteOut(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 2)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		mockValidatorVotedOutEvent(m, 2, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+		require.EqualValues(t, 3, getValidatorVoteOutBanEndElectionIndex(v2[:]))
+
+		// call
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 4, getValidatorVoteOutBanEndElectionIndex(v2[:]), "fresh vote out restarts the ban")
+	})
+}
+
+func TestOrbsVotingContract_processBan_BannedReinstatedToKeepMinimum(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(3, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v4, _toWei(800), _toWei(700))
+		mockValidatorVotedOutEvent(m, 2, v1, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3, v4})
+
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v4: 800}, 1000)
+		require.EqualValues(t, 1, isValidatorVoteOutBanned(v4[:]))
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{v1: 800, v4: 400}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v2, v3, v4}, elected)
+		require.EqualValues(t, 1, isValidatorVoteOutBanned(v1[:]))
+		require.EqualValues(t, 1, isValidatorVoteOutBanned(v4[:]), "reinstated validator keeps its ban")
+		require.Empty(t, getBannedValidatorsByIndex(2))
+	})
+}
+
+func TestOrbsVotingContract_processBan_FallbackDoesNotBan(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(3, 5)
+		_init()
+		m.MockEnvBlockHeight(100)
+		_setValidators([][20]byte{v1, v2, v3})
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{v1: 800}, 1000)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v2, v3}, elected)
+		require.EqualValues(t, 0, isValidatorVoteOutBanned(v1[:]))
+	})
+}
+
+func TestOrbsVotingContract_processBan_Disabled(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 0)
+		_init()
+		m.MockEnvBlockHeight(100)
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		_setValidators([][20]byte{v1, v2, v3})
+
+		_runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800}, 1000)
+
+		// call
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 0)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v2, v3}, elected)
+	})
+}
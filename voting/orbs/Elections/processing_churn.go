@@ -0,0 +1,232 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+	"sort"
+)
+
+/***
+ * Committee churn : at most MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION validators leave and at most as many join per election.
+ * Leaving validators are chosen by highest vote-out weight, joining validators by lowest vote-out weight, ties by address.
+ * Vote-outs that did not fit are kept in state and applied in the next election even if they are not voted again,
+ * unless that election falls back to all validators, which clears them.
+ */
+func _addDeferredVoteOuts(winners [][20]byte, votedOut [][20]byte, voteOutWeights map[[20]byte]*big.Int) ([][20]byte, [][20]byte) {
+	deferred := _getDeferredVoteOuts()
+	if len(deferred) == 0 {
+		return winners, votedOut
+	}
+	stillWinners := make([][20]byte, 0, len(winners))
+	for _, validator := range winners {
+		if weight, ok := deferred[validator]; ok {
+			if weight.Cmp(_getStakeOrZero(voteOutWeights, validator)) > 0 {
+				voteOutWeights[validator] = weight
+			}
+			fmt.Printf("elections %10d: validator %x has a deferred vote out with weight %d\n", _getProcessCurrentElectionBlockNumber(), validator, voteOutWeights[validator])
+			votedOut = append(votedOut, validator)
+		} else {
+			stillWinners = append(stillWinners, validator)
+		}
+	}
+	return stillWinners, votedOut
+}
+
+func _limitCommitteeChurn(validators [][20]byte, winners [][20]byte, votedOut [][20]byte, voteOutWeights map[[20]byte]*big.Int) (elected [][20]byte, leaving [][20]byte) {
+	previous := _getPreviousElectedValidators()
+	if len(previous) == 0 {
+		_setDeferredVoteOuts(nil, nil)
+		return winners, votedOut
+	}
+
+	isValidator := _addressSet(validators)
+	forcedLeaves := 0
+	for validator := range previous {
+		if !isValidator[validator] {
+			forcedLeaves++
+		}
+	}
+	leaveBudget := MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION - forcedLeaves
+	if leaveBudget < 0 {
+		leaveBudget = 0
+	}
+
+	leaveCandidates := make(validatorVoteOutArray, 0, len(votedOut))
+	for _, validator := range votedOut {
+		if previous[validator] {
+			leaveCandidates = append(leaveCandidates, &validatorVoteOut{validator, _getStakeOrZero(voteOutWeights, validator)})
+		} else {
+			leaving = append(leaving, validator) // never was in committee so not a change
+		}
+	}
+	sort.Sort(leaveCandidates)
+	deferredLeaves := make(map[[20]byte]bool)
+	for i, candidate := range leaveCandidates {
+		if i < leaveBudget {
+			leaving = append(leaving, candidate.address)
+		} else {
+			fmt.Printf("elections %10d: churn limit reached, vote out of %x (weight %d) deferred to next election\n", _getProcessCurrentElectionBlockNumber(), candidate.address, candidate.weight)
+			deferredLeaves[candidate.address] = true
+		}
+	}
+
+	joinCandidates := make(validatorVoteOutArray, 0, len(winners))
+	for _, validator := range winners {
+		if !previous[validator] {
+			joinCandidates = append(joinCandidates, &validatorVoteOut{validator, _getStakeOrZero(voteOutWeights, validator)})
+		}
+	}
+	sort.Slice(joinCandidates, func(i, j int) bool { // least voted out first
+		weightOrder := joinCandidates[i].weight.Cmp(joinCandidates[j].weight)
+		return weightOrder < 0 || (weightOrder == 0 && bytes.Compare(joinCandidates[i].address[:], joinCandidates[j].address[:]) < 0)
+	})
+	deferredJoins := make(map[[20]byte]bool)
+	for i, candidate := range joinCandidates {
+		if i >= MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION {
+			fmt.Printf("elections %10d: churn limit reached, joining of %x deferred to next election\n", _getProcessCurrentElectionBlockNumber(), candidate.address)
+			deferredJoins[candidate.address] = true
+		}
+	}
+
+	isWinner := _addressSet(winners)
+	numElected := 0
+	for _, validator := range validators {
+		if (isWinner[validator] && !deferredJoins[validator]) || deferredLeaves[validator] {
+			numElected++
+		}
+	}
+	for i := MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION; i < len(joinCandidates) && numElected < MIN_ELECTED_VALIDATORS; i++ {
+		fmt.Printf("elections %10d: joining of %x is needed to keep minimum committee size\n", _getProcessCurrentElectionBlockNumber(), joinCandidates[i].address)
+		delete(deferredJoins, joinCandidates[i].address)
+		numElected++
+	}
+
+	elected = make([][20]byte, 0, numElected)
+	for _, validator := range validators {
+		if (isWinner[validator] && !deferredJoins[validator]) || deferredLeaves[validator] {
+			elected = append(elected, validator)
+		}
+	}
+
+	_setDeferredVoteOuts(_filterAddresses(leaveCandidates, deferredLeaves), voteOutWeights)
+	_setElectionDeferredLeavingValidatorsAtIndex(_getProcessCurrentElectionIndex(), _filterAddresses(leaveCandidates, deferredLeaves))
+	_setElectionDeferredJoiningValidatorsAtIndex(_getProcessCurrentElectionIndex(), _filterAddresses(joinCandidates, deferredJoins))
+	return elected, leaving
+}
+
+func _getPreviousElectedValidators() map[[20]byte]bool {
+	return _addressSet(_splitAddresses(getElectedValidatorsEthereumAddressByIndex(getNumberOfElections())))
+}
+
+func _filterAddresses(candidates validatorVoteOutArray, filter map[[20]byte]bool) [][20]byte {
+	filtered := make([][20]byte, 0, len(filter))
+	for _, candidate := range candidates {
+		if filter[candidate.address] {
+			filtered = append(filtered, candidate.address)
+		}
+	}
+	return filtered
+}
+
+type validatorVoteOut struct {
+	address [20]byte
+	weight  *big.Int
+}
+type validatorVoteOutArray []*validatorVoteOut
+
+func (s validatorVoteOutArray) Len() int {
+	return len(s)
+}
+
+func (s validatorVoteOutArray) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s validatorVoteOutArray) Less(i, j int) bool {
+	weightOrder := s[i].weight.Cmp(s[j].weight)
+	return weightOrder > 0 || (weightOrder == 0 && bytes.Compare(s[i].address[:], s[j].address[:]) < 0)
+}
+
+/***
+ * Committee churn - data struct
+ */
+func _formatDeferredVoteOuts() []byte {
+	return []byte("Deferred_Vote_Outs")
+}
+
+func _formatDeferredVoteOutWeightKey(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_DeferredVoteOut", hex.EncodeToString(validator)))
+}
+
+func _formatDeferredVoteOutWeightInWeiKey(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_DeferredVoteOutInWei", hex.EncodeToString(validator)))
+}
+
+func getDeferredVoteOutValidators() []byte {
+	return state.ReadBytes(_formatDeferredVoteOuts())
+}
+
+func getDeferredVoteOutWeight(validator []byte) uint64 {
+	return state.ReadUint64(_formatDeferredVoteOutWeightKey(validator))
+}
+
+func getDeferredVoteOutWeightInWei(validator []byte) string {
+	return _getDeferredVoteOutWeightInWei(validator).String()
+}
+
+func _getDeferredVoteOutWeightInWei(validator []byte) *big.Int {
+	return _readWeiOrLegacy(_formatDeferredVoteOutWeightInWeiKey(validator), _formatDeferredVoteOutWeightKey(validator))
+}
+
+func _getDeferredVoteOuts() map[[20]byte]*big.Int {
+	validators := _splitAddresses(getDeferredVoteOutValidators())
+	deferred := make(map[[20]byte]*big.Int, len(validators))
+	for _, validator := range validators {
+		deferred[validator] = _getDeferredVoteOutWeightInWei(validator[:])
+	}
+	return deferred
+}
+
+func _setDeferredVoteOuts(validators [][20]byte, voteOutWeights map[[20]byte]*big.Int) {
+	for _, validator := range _splitAddresses(getDeferredVoteOutValidators()) {
+		state.Clear(_formatDeferredVoteOutWeightKey(validator[:]))
+		state.Clear(_formatDeferredVoteOutWeightInWeiKey(validator[:]))
+	}
+	for _, validator := range validators {
+		_writeWeiAndLegacy(_formatDeferredVoteOutWeightInWeiKey(validator[:]), _formatDeferredVoteOutWeightKey(validator[:]), _getStakeOrZero(voteOutWeights, validator))
+	}
+	state.WriteBytes(_formatDeferredVoteOuts(), _concatElectedEthereumAddresses(validators))
+}
+
+func _formatElectionDeferredLeavingValidators(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_DeferredLeaving", index))
+}
+
+func getDeferredLeavingValidatorsByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatElectionDeferredLeavingValidators(index))
+}
+
+func _setElectionDeferredLeavingValidatorsAtIndex(index uint32, validators [][20]byte) {
+	state.WriteBytes(_formatElectionDeferredLeavingValidators(index), _concatElectedEthereumAddresses(validators))
+}
+
+func _formatElectionDeferredJoiningValidators(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_DeferredJoining", index))
+}
+
+func getDeferredJoiningValidatorsByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatElectionDeferredJoiningValidators(index))
+}
+
+func _setElectionDeferredJoiningValidatorsAtIndex(index uint32, validators [][20]byte) {
+	state.WriteBytes(_formatElectionDeferredJoiningValidators(index), _concatElectedEthereumAddresses(validators))
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func _setPreviousCommittee_InTests(committee [][20]byte) {
+	_setNumberOfElections(1)
+	_setElectedValidatorsEthereumAddressAtIndex(1, _concatElectedEthereumAddresses(committee))
+}
+
+func TestOrbsVotingContract_processChurn_NoPreviousCommitteeNoLimit(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		_setValidators([][20]byte{v1, v2, v3, v4})
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(800), _toWei(700))
+		mockValidatorVotedOutEvent(m, 1, v3, _toWei(900), _toWei(700))
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v2: 800, v3: 900}), _toWei(1000))
+
+		// assert
+		m.VerifyMocks()
+		require.ElementsMatch(t, [][20]byte{v1, v4}, elected)
+		require.Empty(t, getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_LeavingLimitedByVoteOutWeight(t *testing.T) {
+	v1, v2, v3, v4, v5 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}, [20]byte{0xc5}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 2
+		_init()
+		_setValidators([][20]byte{v1, v2, v3, v4, v5})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3, v4, v5})
+		m.MockEmitEvent(ValidatorVotedOut, uint32(2), v4[:], uint64(950), uint64(700), _toWei(950).String(), _toWei(700).String())
+		m.MockEmitEvent(ValidatorVotedOut, uint32(2), v2[:], uint64(800), uint64(700), _toWei(800).String(), _toWei(700).String())
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v2: 800, v3: 750, v4: 950}), _toWei(1000))
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v3, v5}, elected)
+		require.Equal(t, v3[:], getDeferredVoteOutValidators())
+		require.EqualValues(t, 750, getDeferredVoteOutWeight(v3[:]))
+		require.Equal(t, _toWei(750).String(), getDeferredVoteOutWeightInWei(v3[:]))
+		require.Equal(t, v3[:], getDeferredLeavingValidatorsByIndex(2))
+		require.Empty(t, getDeferredJoiningValidatorsByIndex(2))
+	})
+}
+
+func TestOrbsVotingContract_processChurn_LeavingTieBrokenByAddress(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		_setValidators([][20]byte{v1, v2, v3, v4})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3, v4})
+		mockValidatorVotedOutEvent(m, 2, v2, _toWei(800), _toWei(700))
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v3: 800, v2: 800}), _toWei(1000))
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, [][20]byte{v1, v3, v4}, elected)
+		require.Equal(t, v3[:], getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_RemovedValidatorsUseBudget(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		_setValidators([][20]byte{v1, v2, v3})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3, v4})
+
+		// call (an unmocked event would panic)
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v2: 800}), _toWei(1000))
+
+		// assert
+		require.Equal(t, [][20]byte{v1, v2, v3}, elected)
+		require.Equal(t, v2[:], getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_JoiningLimited(t *testing.T) {
+	v1, v2, v3, v4, v5 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}, [20]byte{0xc5}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 2
+		_init()
+		_setValidators([][20]byte{v1, v2, v3, v4, v5})
+		_setPreviousCommittee_InTests([][20]byte{v1})
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v2: 300, v4: 100}), _toWei(1000))
+
+		// assert
+		require.Equal(t, [][20]byte{v1, v3, v5}, elected)
+		require.Equal(t, append(v4[:], v2[:]...), getDeferredJoiningValidatorsByIndex(2))
+		require.Empty(t, getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_DeferredJoinsKeepMinimumCommittee(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 3
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		_setValidators([][20]byte{v2, v3, v4})
+		_setPreviousCommittee_InTests([][20]byte{v1})
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{}), _toWei(1000))
+
+		// assert
+		require.Equal(t, [][20]byte{v2, v3, v4}, elected)
+		require.Empty(t, getDeferredJoiningValidatorsByIndex(2))
+	})
+}
+
+func TestOrbsVotingContract_processChurn_DeferredVoteOutCarriedToNextElection(t *testing.T) {
+	v1, v2, v3, v4 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 0)
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		m.MockEnvBlockHeight(100)
+		_setValidators([][20]byte{v1, v2, v3, v4})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3, v4})
+		mockValidatorVotedOutEvent(m, 2, v3, _toWei(900), _toWei(700))
+		mockElectionCompletedEvent(m, 2, 10000, v1, v2, v4)
+		mockValidatorVotedOutEvent(m, 3, v2, _toWei(800), _toWei(700))
+
+		// first election v3 leaves, v2 deferred
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v2: 800, v3: 900}), _toWei(1000))
+		require.Equal(t, [][20]byte{v1, v2, v4}, elected)
+		_setElectedValidators(elected, 0, 10000)
+
+		// second election v2 leaves without new votes and v3 is no longer voted out so it rejoins
+		elected = _processValidatorsSelection(weiStakes(map[[20]byte]uint64{}), _toWei(1000))
+		require.Equal(t, [][20]byte{v1, v3, v4}, elected)
+		require.Zero(t, getValidatorVote(v2[:]), "vote reflects this election only")
+		require.Empty(t, getDeferredVoteOutValidators())
+		require.Zero(t, getDeferredVoteOutWeight(v2[:]))
+		m.VerifyMocks()
+	})
+}
+
+func TestOrbsVotingContract_processChurn_VoteMachine(t *testing.T) {
+	h := newHarnessBlockBased()
+	h.electionBlock = uint64(60000)
+	aRecentVoteBlock := h.electionBlock - 1
+
+	var v1, v2, v3, v4 = h.addValidator(), h.addValidator(), h.addValidator(), h.addValidator()
+	var g1, g2 = h.addGuardian(100), h.addGuardian(100)
+
+	g1.vote(aRecentVoteBlock, v1, v2)
+	g2.vote(aRecentVoteBlock, v1, v2)
+	d1 := h.addDelegator(500, g1.address)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 1
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		h.setupEthereumStateBeforeProcess(m)
+		h.setupOrbsStateBeforeProcessMachine()
+		_setPreviousCommittee_InTests(getValidatorAddresses([]*validator{v1, v2, v3, v4}))
+		mockProcessingStagesEvents(m, 2, h.electionBlock)
+		mockValidatorVotedOutEvent(m, 2, v1.address, big.NewInt(7000000), big.NewInt(4900000))
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 2, map[[20]byte]string{g1.address: "682", g2.address: "682", d1.address: "3411"})
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 2, map[[20]byte]string{g1.address: "5118", g2.address: "853"})
+
+		// call
+		elected, _ := h.runProcessVoteMachineNtimes(100)
+
+		// assert
+		m.VerifyMocks()
+		require.Len(t, elected, 3)
+		require.NotContains(t, elected, v1.address)
+		require.Contains(t, elected, v2.address)
+		require.Equal(t, v2.address[:], getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_FallbackJoiningLimited(t *testing.T) {
+	v1, v2, v3, v4, v5 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}, [20]byte{0xc5}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(3, 3)
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		_setValidators([][20]byte{v1, v2, v3, v4, v5})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3})
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v1: 900, v2: 800, v3: 750}), _toWei(1000))
+
+		// assert
+		require.Equal(t, [][20]byte{v1, v2, v3, v4}, elected)
+		require.EqualValues(t, 1, isElectionElectedByFallback(2))
+		require.Equal(t, _concatElectedEthereumAddresses([][20]byte{v1, v2, v3}), getFallbackVotedOutValidatorsByIndex(2))
+		require.Equal(t, v5[:], getDeferredJoiningValidatorsByIndex(2))
+		require.Empty(t, getDeferredVoteOutValidators())
+	})
+}
+
+func TestOrbsVotingContract_processChurn_FallbackClearsDeferredVoteOuts(t *testing.T) {
+	v1, v2, v3, v4, v5 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}, [20]byte{0xc4}, [20]byte{0xc5}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_setBanTestVariables(1, 3)
+		MAX_ELECTED_VALIDATORS_CHANGE_PER_ELECTION = 1
+		_init()
+		m.MockEnvBlockHeight(100)
+		_setValidators([][20]byte{v1, v2, v3, v4, v5})
+		_setPreviousCommittee_InTests([][20]byte{v1, v2, v3, v4, v5})
+		mockValidatorVotedOutEvent(m, 2, v2, _toWei(800), _toWei(700))
+
+		// first election v2 leaves, v3 deferred
+		elected := _runValidatorsSelection_InTests(m, map[[20]byte]uint64{v2: 800, v3: 750}, 1000)
+		require.Equal(t, [][20]byte{v1, v3, v4, v5}, elected)
+		require.Equal(t, v3[:], getDeferredVoteOutValidators())
+
+		// second election falls back, v3 stays and is no longer deferred, v2 joins back
+		MIN_ELECTED_VALIDATORS = 5
+		elected = _runValidatorsSelection_InTests(m, map[[20]byte]uint64{v1: 900}, 1000)
+		require.Equal(t, [][20]byte{v1, v2, v3, v4, v5}, elected)
+		require.EqualValues(t, 1, isElectionElectedByFallback(3))
+		require.Empty(t, getDeferredVoteOutValidators())
+		require.Zero(t, getDeferredVoteOutWeight(v3[:]))
+
+		// third election v2 leaves again as it is still banned, the cleared vote out of v3 is not applied
+		MIN_ELECTED_VALIDATORS = 1
+		elected = _runValidatorsSelection_InTests(m, map[[20]byte]uint64{}, 1000)
+		require.Equal(t, [][20]byte{v1, v3, v4, v5}, elected)
+		m.VerifyMocks()
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+	"sort"
+)
+
+/***
+ * Excellence program - parameters : kept in state so governance can change them without a contract upgrade, unset means the default.
+ * tie policy decides what happens with guardians tied on the vote of the last place in the program:
+ * include all of them, exclude all of them, or take them in sort order (higher address first) up to the max number.
+ */
+const ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER = 10
+const EXCELLENCE_TIE_POLICY_INCLUDE_ALL = "IncludeAll"
+const EXCELLENCE_TIE_POLICY_EXCLUDE_ALL = "ExcludeAll"
+const EXCELLENCE_TIE_POLICY_ADDRESS_ORDERED = "AddressOrdered"
+
+func _formatExcellenceProgramMaxNumber() []byte {
+	return []byte("Excellence_Program_Max_Number")
+}
+
+func _formatExcellenceProgramMinStake() []byte {
+	return []byte("Excellence_Program_Min_Stake")
+}
+
+func _formatExcellenceProgramTiePolicy() []byte {
+	return []byte("Excellence_Program_Tie_Policy")
+}
+
+func getExcellenceProgramMaxNumber() uint32 {
+	if len(state.ReadBytes(_formatExcellenceProgramMaxNumber())) == 0 {
+		return ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER
+	}
+	return state.ReadUint32(_formatExcellenceProgramMaxNumber())
+}
+
+// minimal accumulated stake in ORBS
+func getExcellenceProgramMinStake() uint64 {
+	return state.ReadUint64(_formatExcellenceProgramMinStake())
+}
+
+func getExcellenceProgramTiePolicy() string {
+	tiePolicy := state.ReadString(_formatExcellenceProgramTiePolicy())
+	if tiePolicy == "" {
+		return EXCELLENCE_TIE_POLICY_INCLUDE_ALL
+	}
+	return tiePolicy
+}
+
+func setExcellenceProgram(maxNumber uint32, minStake uint64, tiePolicy string) {
+	_requireGovernance("setExcellenceProgram")
+	_setExcellenceProgramParameters(maxNumber, minStake, tiePolicy)
+}
+
+func _setExcellenceProgramParameters(maxNumber uint32, minStake uint64, tiePolicy string) {
+	if tiePolicy != EXCELLENCE_TIE_POLICY_INCLUDE_ALL && tiePolicy != EXCELLENCE_TIE_POLICY_EXCLUDE_ALL && tiePolicy != EXCELLENCE_TIE_POLICY_ADDRESS_ORDERED {
+		panic(fmt.Sprintf("unknown excellence program tie policy %s", tiePolicy))
+	}
+	state.WriteUint32(_formatExcellenceProgramMaxNumber(), maxNumber)
+	state.WriteUint64(_formatExcellenceProgramMinStake(), minStake)
+	state.WriteString(_formatExcellenceProgramTiePolicy(), tiePolicy)
+}
+
+/***
+ * Excellence program - membership : latest election and per election with rank and vote.
+ * tied guardians share a rank and the next rank skips accordingly (1, 2, 2, 4).
+ */
+func _formatExcellenceProgramGuardians() []byte {
+	return []byte("Excellence_Program_Guardians")
+}
+
+func _formatExcellenceProgramGuardiansAtIndex(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Excellence_Program_Guardians", index))
+}
+
+func _formatExcellenceProgramGuardianRankAtIndex(index uint32, guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Excellence_Program_Guardian_%s_Rank", index, hex.EncodeToString(guardian)))
+}
+
+func _formatExcellenceProgramGuardianVoteAtIndex(index uint32, guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Excellence_Program_Guardian_%s_VoteInWei", index, hex.EncodeToString(guardian)))
+}
+
+func getExcellenceProgramGuardians() []byte {
+	return state.ReadBytes(_formatExcellenceProgramGuardians())
+}
+
+func getExcellenceProgramGuardiansByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatExcellenceProgramGuardiansAtIndex(index))
+}
+
+// zero when the guardian was not in the program
+func getExcellenceProgramGuardianRankByIndex(guardian []byte, index uint32) uint32 {
+	return state.ReadUint32(_formatExcellenceProgramGuardianRankAtIndex(index, guardian))
+}
+
+func getExcellenceProgramGuardianVoteInWeiByIndex(guardian []byte, index uint32) string {
+	return _readWei(_formatExcellenceProgramGuardianVoteAtIndex(index, guardian)).String()
+}
+
+func _setExcellenceProgramGuardians(guardians guardianArray) {
+	index := _getProcessCurrentElectionIndex()
+	guardiansForSave := make([]byte, 0, len(guardians)*20)
+	rank := 0
+	for i, guardian := range guardians {
+		guardiansForSave = append(guardiansForSave, guardian.address[:]...)
+		if i == 0 || guardian.vote.Cmp(guardians[i-1].vote) != 0 {
+			rank = i + 1
+		}
+		state.WriteUint32(_formatExcellenceProgramGuardianRankAtIndex(index, guardian.address[:]), uint32(rank))
+		state.WriteBytes(_formatExcellenceProgramGuardianVoteAtIndex(index, guardian.address[:]), guardian.vote.Bytes())
+	}
+	state.WriteBytes(_formatExcellenceProgramGuardians(), guardiansForSave)
+	state.WriteBytes(_formatExcellenceProgramGuardiansAtIndex(index), guardiansForSave)
+}
+
+/***
+ * Excellence program: Sort top guardians using sort.Interface
+ */
+func _getTopGuardians(guardiansAccumulatedStake map[[20]byte]*big.Int) (topGuardiansStake guardianArray, totalVotes *big.Int) {
+	totalVotes = big.NewInt(0)
+	maxNumber := int(getExcellenceProgramMaxNumber())
+	minStake := _toWei(getExcellenceProgramMinStake())
+	tiePolicy := getExcellenceProgramTiePolicy()
+
+	guardianList := make(guardianArray, 0, len(guardiansAccumulatedStake))
+	for guardian, vote := range guardiansAccumulatedStake {
+		if vote.Cmp(minStake) >= 0 {
+			guardianList = append(guardianList, &guardianVote{guardian, vote})
+		} else {
+			fmt.Printf("elections %10d rewards: guardian %x, has %d votes which is below minimum %d\n", _getProcessCurrentElectionBlockNumber(), guardian, vote, minStake)
+		}
+	}
+	sort.Sort(guardianList)
+
+	cut := len(guardianList)
+	if cut > maxNumber {
+		cut = maxNumber
+		if cut > 0 && tiePolicy != EXCELLENCE_TIE_POLICY_ADDRESS_ORDERED && guardianList[cut].vote.Cmp(guardianList[cut-1].vote) == 0 {
+			tiedVote := guardianList[cut-1].vote
+			if tiePolicy == EXCELLENCE_TIE_POLICY_INCLUDE_ALL {
+				for cut < len(guardianList) && guardianList[cut].vote.Cmp(tiedVote) == 0 {
+					cut++
+				}
+			} else {
+				for cut > 0 && guardianList[cut-1].vote.Cmp(tiedVote) == 0 {
+					cut--
+				}
+			}
+			fmt.Printf("elections %10d rewards: guardians tied on %d votes at the cut-off, policy %s leaves %d top guardians\n", _getProcessCurrentElectionBlockNumber(), tiedVote, tiePolicy, cut)
+		}
+	}
+
+	for i := 0; i < cut; i++ {
+		fmt.Printf("elections %10d rewards: top guardian %x, has %d votes\n", _getProcessCurrentElectionBlockNumber(), guardianList[i].address, guardianList[i].vote)
+		totalVotes = _addWei(totalVotes, guardianList[i].vote)
+	}
+	return guardianList[0:cut], totalVotes
+}
+
+type guardianVote struct {
+	address [20]byte
+	vote    *big.Int
+}
+type guardianArray []*guardianVote
+
+func (s guardianArray) Len() int {
+	return len(s)
+}
+
+func (s guardianArray) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s guardianArray) Less(i, j int) bool {
+	cmp := s[i].vote.Cmp(s[j].vote)
+	return cmp > 0 || (cmp == 0 && bytes.Compare(s[i].address[:], s[j].address[:]) > 0)
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func _topGuardianAddresses(topGuardians guardianArray) [][20]byte {
+	addresses := make([][20]byte, 0, len(topGuardians))
+	for _, guardian := range topGuardians {
+		addresses = append(addresses, guardian.address)
+	}
+	return addresses
+}
+
+func TestOrbsVotingContract_excellenceProgram_DefaultParameters(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// assert
+		require.EqualValues(t, ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER, getExcellenceProgramMaxNumber())
+		require.EqualValues(t, 0, getExcellenceProgramMinStake())
+		require.EqualValues(t, EXCELLENCE_TIE_POLICY_INCLUDE_ALL, getExcellenceProgramTiePolicy())
+	})
+}
+
+func TestOrbsVotingContract_excellenceProgram_UnknownTiePolicy(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		require.Panics(t, func() {
+			_setExcellenceProgramParameters(5, 0, "Random")
+		}, "should panic because tie policy is unknown")
+	})
+}
+
+func TestOrbsVotingContract_excellenceProgram_TiesAtCutOff(t *testing.T) {
+	g1, g2, g3, g4, g5 := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xa3}, [20]byte{0xa4}, [20]byte{0xa5}
+	stakes := weiStakes(map[[20]byte]uint64{g1: 500, g2: 400, g3: 300, g4: 300, g5: 300})
+
+	tests := []struct {
+		name      string
+		tiePolicy string
+		expect    [][20]byte
+	}{
+		{"include all tied", EXCELLENCE_TIE_POLICY_INCLUDE_ALL, [][20]byte{g1, g2, g5, g4, g3}},
+		{"exclude all tied", EXCELLENCE_TIE_POLICY_EXCLUDE_ALL, [][20]byte{g1, g2}},
+		{"tied by address order", EXCELLENCE_TIE_POLICY_ADDRESS_ORDERED, [][20]byte{g1, g2, g5}},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			InServiceScope(nil, nil, func(m Mockery) {
+				_init()
+				_setExcellenceProgramParameters(3, 0, cTest.tiePolicy)
+
+				// call
+				topGuardians, total := _getTopGuardians(stakes)
+
+				// assert
+				require.EqualValues(t, cTest.expect, _topGuardianAddresses(topGuardians))
+				expectedTotal := uint64(0)
+				for _, guardian := range topGuardians {
+					expectedTotal += _toOrbs(guardian.vote)
+				}
+				require.EqualValues(t, expectedTotal, _toOrbs(total))
+			})
+		})
+	}
+}
+
+func TestOrbsVotingContract_excellenceProgram_TieBelowCutOffIsKept(t *testing.T) {
+	g1, g2, g3, g4 := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xa3}, [20]byte{0xa4}
+	stakes := weiStakes(map[[20]byte]uint64{g1: 500, g2: 500, g3: 400, g4: 300})
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_setExcellenceProgramParameters(3, 0, EXCELLENCE_TIE_POLICY_EXCLUDE_ALL)
+
+		// call
+		topGuardians, _ := _getTopGuardians(stakes)
+
+		// assert
+		require.EqualValues(t, [][20]byte{g2, g1, g3}, _topGuardianAddresses(topGuardians), "only a tie across the cut-off is excluded")
+	})
+}
+
+func TestOrbsVotingContract_excellenceProgram_MinStake(t *testing.T) {
+	g1, g2, g3 := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xa3}
+	stakes := weiStakes(map[[20]byte]uint64{g1: 500, g2: 400, g3: 399})
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_setExcellenceProgramParameters(10, 400, EXCELLENCE_TIE_POLICY_INCLUDE_ALL)
+
+		// call
+		topGuardians, total := _getTopGuardians(stakes)
+
+		// assert
+		require.EqualValues(t, [][20]byte{g1, g2}, _topGuardianAddresses(topGuardians))
+		require.EqualValues(t, 900, _toOrbs(total))
+	})
+}
+
+func TestOrbsVotingContract_excellenceProgram_MembershipKeptPerElection(t *testing.T) {
+	g1, g2, g3, g4 := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xa3}, [20]byte{0xa4}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		_setExcellenceProgramParameters(3, 0, EXCELLENCE_TIE_POLICY_INCLUDE_ALL)
+		firstIndex := _getProcessCurrentElectionIndex()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, firstIndex, map[[20]byte]string{g1: "511814381984133754", g2: "426511984986778128", g3: "426511984986778128"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, g4, firstIndex+1, "853023969973556257")
+
+		// call
+		_processRewardsGuardians(_toWei(1900), weiStakes(map[[20]byte]uint64{g1: 600, g2: 500, g3: 500, g4: 300}))
+		_setNumberOfElections(firstIndex)
+		_processRewardsGuardians(_toWei(1000), weiStakes(map[[20]byte]uint64{g4: 1000}))
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, append(append(g1[:], g3[:]...), g2[:]...), getExcellenceProgramGuardiansByIndex(firstIndex))
+		require.EqualValues(t, 1, getExcellenceProgramGuardianRankByIndex(g1[:], firstIndex))
+		require.EqualValues(t, 2, getExcellenceProgramGuardianRankByIndex(g2[:], firstIndex), "tied guardians share the rank")
+		require.EqualValues(t, 2, getExcellenceProgramGuardianRankByIndex(g3[:], firstIndex))
+		require.EqualValues(t, 0, getExcellenceProgramGuardianRankByIndex(g4[:], firstIndex))
+		require.Equal(t, _toWei(500).String(), getExcellenceProgramGuardianVoteInWeiByIndex(g2[:], firstIndex))
+
+		require.EqualValues(t, g4[:], getExcellenceProgramGuardiansByIndex(firstIndex+1))
+		require.EqualValues(t, 1, getExcellenceProgramGuardianRankByIndex(g4[:], firstIndex+1))
+		require.EqualValues(t, g4[:], getExcellenceProgramGuardians(), "latest election")
+	})
+}
+
+func TestOrbsVotingContract_excellenceProgram_SetByGovernance(t *testing.T) {
+	governance := []byte{0x01, 0x02}
+	_setGovernanceInTests(t, governance)
+
+	InServiceScope(governance, nil, func(m Mockery) {
+		_init()
+
+		// call
+		setExcellenceProgram(5, 400, EXCELLENCE_TIE_POLICY_EXCLUDE_ALL)
+
+		// assert
+		require.EqualValues(t, 5, getExcellenceProgramMaxNumber())
+		require.EqualValues(t, 400, getExcellenceProgramMinStake())
+		require.EqualValues(t, EXCELLENCE_TIE_POLICY_EXCLUDE_ALL, getExcellenceProgramTiePolicy())
+	})
+
+	InServiceScope([]byte{0x03}, nil, func(m Mockery) {
+		_init()
+
+		requirePanicsWithErrorCode(t, ERROR_NOT_GOVERNANCE, func() {
+			setExcellenceProgram(5, 400, EXCELLENCE_TIE_POLICY_EXCLUDE_ALL)
+		}, "should panic because signer is not the governance address")
+	})
+}
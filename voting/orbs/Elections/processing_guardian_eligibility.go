@@ -0,0 +1,50 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+)
+
+/***
+ * Guardian eligibility : a guardian votes only with a recent vote, a minimum self stake and a minimum accumulated stake.
+ * the outcome is kept per election, what happens to delegators of an ineligible guardian is INELIGIBLE_GUARDIAN_DELEGATORS_POLICY.
+ */
+const GUARDIAN_INELIGIBLE_VOTE_EXPIRED = "VoteExpired"
+const GUARDIAN_INELIGIBLE_SELF_STAKE_BELOW_MINIMUM = "SelfStakeBelowMinimum"
+const GUARDIAN_INELIGIBLE_ACCUMULATED_STAKE_BELOW_MINIMUM = "AccumulatedStakeBelowMinimum"
+
+func _isGuardianEligible(guardian [20]byte, hasVoted bool, selfStake *big.Int, accumulatedStake *big.Int) bool {
+	reason := ""
+	if !hasVoted {
+		reason = GUARDIAN_INELIGIBLE_VOTE_EXPIRED
+	} else if selfStake.Cmp(_toWei(GUARDIAN_MIN_SELF_STAKE)) < 0 {
+		reason = GUARDIAN_INELIGIBLE_SELF_STAKE_BELOW_MINIMUM
+	} else if accumulatedStake.Cmp(_toWei(GUARDIAN_MIN_ACCUMULATED_STAKE)) < 0 {
+		reason = GUARDIAN_INELIGIBLE_ACCUMULATED_STAKE_BELOW_MINIMUM
+	}
+	if reason != "" {
+		fmt.Printf("elections %10d: guardian %x is not eligible, %s (self stake %d, accumulated stake %d)\n", _getProcessCurrentElectionBlockNumber(), guardian, reason, selfStake, accumulatedStake)
+	}
+	state.WriteString(_formatElectionGuardianIneligibilityReason(_getProcessCurrentElectionIndex(), guardian[:]), reason)
+	return reason == ""
+}
+
+/***
+ * Guardian eligibility - data struct
+ */
+func _formatElectionGuardianIneligibilityReason(index uint32, guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Guardian_%s_IneligibilityReason", index, hex.EncodeToString(guardian)))
+}
+
+// empty when the guardian was eligible to vote in the election
+func getGuardianIneligibilityReasonByIndex(guardian []byte, index uint32) string {
+	return state.ReadString(_formatElectionGuardianIneligibilityReason(index, guardian))
+}
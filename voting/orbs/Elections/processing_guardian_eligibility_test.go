@@ -0,0 +1,131 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestOrbsVotingContract_guardianEligibility_processVoteMachine(t *testing.T) {
+	defer restoreGuardianEligibility()()
+	tests := []struct {
+		name                  string
+		policy                string
+		expectedTotalStake    uint64
+		expectDirectRewarded  bool
+		expectedSmallReason   string
+		expectedExpiredReason string
+	}{
+		{"not participating", INELIGIBLE_GUARDIAN_DELEGATORS_NOT_PARTICIPATING, 3000, false, GUARDIAN_INELIGIBLE_SELF_STAKE_BELOW_MINIMUM, GUARDIAN_INELIGIBLE_VOTE_EXPIRED},
+		{"direct participation", INELIGIBLE_GUARDIAN_DELEGATORS_DIRECT_PARTICIPATION, 3000, true, GUARDIAN_INELIGIBLE_SELF_STAKE_BELOW_MINIMUM, GUARDIAN_INELIGIBLE_VOTE_EXPIRED},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			h := newHarnessBlockBased()
+			h.electionBlock = uint64(60000)
+			aRecentVoteBlock := h.electionBlock - 1
+			anAncientVoteBlock := uint64(10000)
+
+			var v1, v2, v3 = h.addValidator(), h.addValidator(), h.addValidator()
+			var eligible, small, expired = h.addGuardian(2000), h.addGuardian(50), h.addGuardian(5000)
+			eligible.vote(aRecentVoteBlock, v1)
+			small.vote(aRecentVoteBlock, v2)
+			expired.vote(anAncientVoteBlock, v3)
+			eligibleDelegator := h.addDelegator(1000, eligible.address)
+			smallDelegator := h.addDelegator(1000, small.address)
+			expiredDelegator := h.addDelegator(500, expired.address)
+
+			InServiceScope(nil, nil, func(m Mockery) {
+				_init()
+				GUARDIAN_MIN_SELF_STAKE, GUARDIAN_MIN_ACCUMULATED_STAKE, INELIGIBLE_GUARDIAN_DELEGATORS_POLICY = 100, 0, cTest.policy
+
+				// prepare
+				h.setupOrbsStateBeforeProcessMachine()
+				h.setupEthereumStateBeforeProcess(m)
+				index := _getProcessCurrentElectionIndex()
+				mockProcessingStagesEvents(m, index, h.electionBlock)
+				participationRewards := map[[20]byte]string{eligible.address: "13648", eligibleDelegator.address: "6824"}
+				if cTest.expectDirectRewarded {
+					participationRewards[smallDelegator.address] = "6824"
+					participationRewards[expiredDelegator.address] = "3412"
+				}
+				mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, index, participationRewards)
+				mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, eligible.address, index, "25590")
+
+				// call
+				h.runProcessVoteMachineNtimes(-1)
+
+				// assert
+				m.VerifyMocks()
+				require.EqualValues(t, cTest.expectedTotalStake, getTotalStake(), "only the eligible guardian votes")
+				require.Equal(t, "", getGuardianIneligibilityReasonByIndex(eligible.address[:], index))
+				require.Equal(t, cTest.expectedSmallReason, getGuardianIneligibilityReasonByIndex(small.address[:], index))
+				require.Equal(t, cTest.expectedExpiredReason, getGuardianIneligibilityReasonByIndex(expired.address[:], index))
+				require.EqualValues(t, 0, getValidatorVote(v2.address[:]), "ineligible guardian vote is not counted")
+				require.NotEqual(t, "0", getCumulativeParticipationRewardInWei(eligibleDelegator.address[:]))
+				require.Equal(t, "0", getCumulativeParticipationRewardInWei(small.address[:]), "ineligible guardian does not participate")
+				for _, delegator := range []*delegator{smallDelegator, expiredDelegator} {
+					reward := getCumulativeParticipationRewardInWei(delegator.address[:])
+					if cTest.expectDirectRewarded {
+						require.NotEqual(t, "0", reward, "delegator %x participates directly", delegator.address)
+					} else {
+						require.Equal(t, "0", reward, "delegator %x does not participate", delegator.address)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestOrbsVotingContract_guardianEligibility_MinAccumulatedStake(t *testing.T) {
+	defer restoreGuardianEligibility()()
+	h := newHarnessBlockBased()
+	h.electionBlock = uint64(60000)
+	aRecentVoteBlock := h.electionBlock - 1
+
+	var v1, v2, v3, v4 = h.addValidator(), h.addValidator(), h.addValidator(), h.addValidator()
+	var withDelegators, alone = h.addGuardian(500), h.addGuardian(1500)
+	withDelegators.vote(aRecentVoteBlock, v1)
+	alone.vote(aRecentVoteBlock, v2, v3)
+	d1 := h.addDelegator(1100, withDelegators.address)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		GUARDIAN_MIN_SELF_STAKE, GUARDIAN_MIN_ACCUMULATED_STAKE, INELIGIBLE_GUARDIAN_DELEGATORS_POLICY = 0, 1501, INELIGIBLE_GUARDIAN_DELEGATORS_NOT_PARTICIPATING
+
+		// prepare
+		h.setupOrbsStateBeforeProcessMachine()
+		h.setupEthereumStateBeforeProcess(m)
+		index := _getProcessCurrentElectionIndex()
+		mockProcessingStagesEvents(m, index, h.electionBlock)
+		mockValidatorVotedOutEvent(m, index, v1.address, big.NewInt(16000000), big.NewInt(11200000))
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, index, map[[20]byte]string{withDelegators.address: "3411", d1.address: "7506"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, withDelegators.address, index, "13648")
+
+		// call
+		elected, _ := h.runProcessVoteMachineNtimes(-1)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, "", getGuardianIneligibilityReasonByIndex(withDelegators.address[:], index), "delegated stake counts toward the minimum")
+		require.Equal(t, GUARDIAN_INELIGIBLE_ACCUMULATED_STAKE_BELOW_MINIMUM, getGuardianIneligibilityReasonByIndex(alone.address[:], index))
+		require.EqualValues(t, 1600, getTotalStake())
+		require.EqualValues(t, 0, getGuardianVotingWeight(alone.address[:]))
+		require.ElementsMatch(t, [][20]byte{v2.address, v3.address, v4.address}, elected, "only the eligible guardian votes out")
+	})
+}
+
+func restoreGuardianEligibility() func() {
+	minSelf, minAccumulated, policy := GUARDIAN_MIN_SELF_STAKE, GUARDIAN_MIN_ACCUMULATED_STAKE, INELIGIBLE_GUARDIAN_DELEGATORS_POLICY
+	return func() {
+		GUARDIAN_MIN_SELF_STAKE, GUARDIAN_MIN_ACCUMULATED_STAKE, INELIGIBLE_GUARDIAN_DELEGATORS_POLICY = minSelf, minAccumulated, policy
+	}
+}
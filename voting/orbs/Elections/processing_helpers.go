@@ -61,6 +61,11 @@ func _getProcessCurrentElectionTime() uint64 {
 	return state.ReadUint64(_formatProcessCurrentElectionTime())
 }
 
+// election results are only written at the end of processing so the processed election is the next index
+func _getProcessCurrentElectionIndex() uint32 {
+	return getNumberOfElections() + 1
+}
+
 func _setProcessCurrentElection(electionTime, electionBlockNumber, earliestValidVoteBlockNumber uint64) {
 	state.WriteUint64(_formatProcessCurrentElectionBlockNumber(), electionBlockNumber)
 	state.WriteUint64(_formatProcessCurrentElectionEarliestValidVoteBlockNumber(), earliestValidVoteBlockNumber)
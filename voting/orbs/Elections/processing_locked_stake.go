@@ -0,0 +1,108 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+)
+
+/***
+ * Locked stake weight : stake locked in the staking contract may count more than the liquid token balance,
+ * the multiplier is applied separately to the voting weight and to the participation rewards.
+ */
+func _weighStake(stake *big.Int, lockedStake *big.Int, multiplierPercent uint64, isWeighted bool) *big.Int {
+	if !isWeighted || multiplierPercent == 0 {
+		return _addWei(stake, lockedStake)
+	}
+	weightedLockedStake := new(big.Int).Mul(lockedStake, new(big.Int).SetUint64(multiplierPercent))
+	weightedLockedStake.Div(weightedLockedStake, big.NewInt(100))
+	return _addWei(stake, weightedLockedStake)
+}
+
+// records both stake components for the current election and returns the voting weight
+func _recordStakeAtElection(ethAddr [20]byte, stake *big.Int, lockedStake *big.Int) *big.Int {
+	index := _getProcessCurrentElectionIndex()
+	state.WriteBytes(_formatUnlockedStakeAtIndex(ethAddr[:], index), stake.Bytes())
+	state.WriteBytes(_formatLockedStakeAtIndex(ethAddr[:], index), lockedStake.Bytes())
+	return _weighStake(stake, lockedStake, LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING)
+}
+
+// participant stakes are voting weights, replaced by reward weights when only one of them is multiplied
+func _toParticipantRewardWeights(participants [][20]byte, participantStakes map[[20]byte]*big.Int) map[[20]byte]*big.Int {
+	index := _getProcessCurrentElectionIndex()
+	_setLockedStakeWeightingAtIndex(index)
+	if LOCKED_STAKE_MULTIPLIER_FOR_VOTING == LOCKED_STAKE_MULTIPLIER_FOR_REWARDS {
+		return participantStakes
+	}
+	rewardWeights := make(map[[20]byte]*big.Int, len(participantStakes))
+	for _, participant := range participants {
+		rewardWeights[participant] = _getRewardWeightAtIndex(participant[:], index)
+		fmt.Printf("elections %10d: participant %x, voting weight %d, reward weight %d\n", _getProcessCurrentElectionBlockNumber(), participant, _getStakeOrZero(participantStakes, participant), rewardWeights[participant])
+	}
+	return rewardWeights
+}
+
+/***
+ * Locked stake weight - data struct
+ */
+func _formatUnlockedStakeAtIndex(ethAddr []byte, index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Stakeholder_%s_UnlockedStakeInWei", index, hex.EncodeToString(ethAddr)))
+}
+
+func _formatLockedStakeAtIndex(ethAddr []byte, index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Stakeholder_%s_LockedStakeInWei", index, hex.EncodeToString(ethAddr)))
+}
+
+func _formatLockedStakeMultiplierPercentAtIndex(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_LockedStakeMultiplierPercent", index))
+}
+
+func _formatLockedStakeMultiplierForVotingAtIndex(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_LockedStakeMultiplierForVoting", index))
+}
+
+func _formatLockedStakeMultiplierForRewardsAtIndex(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_LockedStakeMultiplierForRewards", index))
+}
+
+func _setLockedStakeWeightingAtIndex(index uint32) {
+	state.WriteUint64(_formatLockedStakeMultiplierPercentAtIndex(index), LOCKED_STAKE_MULTIPLIER_PERCENT)
+	state.WriteUint32(_formatLockedStakeMultiplierForVotingAtIndex(index), _boolToUint32(LOCKED_STAKE_MULTIPLIER_FOR_VOTING))
+	state.WriteUint32(_formatLockedStakeMultiplierForRewardsAtIndex(index), _boolToUint32(LOCKED_STAKE_MULTIPLIER_FOR_REWARDS))
+}
+
+func getUnlockedStakeInWeiByIndex(ethAddr []byte, index uint32) string {
+	return _readWei(_formatUnlockedStakeAtIndex(ethAddr, index)).String()
+}
+
+func getLockedStakeInWeiByIndex(ethAddr []byte, index uint32) string {
+	return _readWei(_formatLockedStakeAtIndex(ethAddr, index)).String()
+}
+
+func getLockedStakeMultiplierPercentByIndex(index uint32) uint64 {
+	return state.ReadUint64(_formatLockedStakeMultiplierPercentAtIndex(index))
+}
+
+func getVotingWeightInWeiByIndex(ethAddr []byte, index uint32) string {
+	return _getWeightAtIndex(ethAddr, index, state.ReadUint32(_formatLockedStakeMultiplierForVotingAtIndex(index)) == 1).String()
+}
+
+func getRewardWeightInWeiByIndex(ethAddr []byte, index uint32) string {
+	return _getRewardWeightAtIndex(ethAddr, index).String()
+}
+
+func _getRewardWeightAtIndex(ethAddr []byte, index uint32) *big.Int {
+	return _getWeightAtIndex(ethAddr, index, state.ReadUint32(_formatLockedStakeMultiplierForRewardsAtIndex(index)) == 1)
+}
+
+func _getWeightAtIndex(ethAddr []byte, index uint32, isWeighted bool) *big.Int {
+	return _weighStake(_readWei(_formatUnlockedStakeAtIndex(ethAddr, index)), _readWei(_formatLockedStakeAtIndex(ethAddr, index)),
+		getLockedStakeMultiplierPercentByIndex(index), isWeighted)
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestOrbsVotingContract_lockedStake_weighStake(t *testing.T) {
+	tests := []struct {
+		name       string
+		expect     int64
+		multiplier uint64
+		isWeighted bool
+	}{
+		{"not weighted", 900, 150, false},
+		{"weighted", 1150, 150, true},
+		{"weighted down", 650, 50, true},
+		{"no multiplier recorded", 900, 0, true},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			weight := _weighStake(big.NewInt(400), big.NewInt(500), cTest.multiplier, cTest.isWeighted)
+			require.EqualValues(t, cTest.expect, weight.Int64())
+		})
+	}
+}
+
+func TestOrbsVotingContract_lockedStake_collectGuardian_WeightedForVoting(t *testing.T) {
+	h := newHarnessBlockBased()
+	h.electionBlock = uint64(60000)
+	var v1 = h.addValidator()
+	var g1 = h.addGuardian(400)
+	g1.lockedStake = 500
+	g1.vote(h.electionBlock-1, v1)
+	defer restoreLockedStakeMultiplier()()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING, LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = 150, true, false
+		h.setupOrbsStateBeforeProcessMachine()
+		h.setupEthereumGuardiansDataBeforeProcess(m)
+		index := _getProcessCurrentElectionIndex()
+
+		// call
+		_collectOneGuardianDataFromEthereum(0)
+		_setLockedStakeWeightingAtIndex(index)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 1150, state.ReadUint64(_formatGuardianStakeKey(g1.address[:])), "voting weight counts locked stake at 150 percent")
+		require.Equal(t, _toWei(400).String(), getUnlockedStakeInWeiByIndex(g1.address[:], index))
+		require.Equal(t, _toWei(500).String(), getLockedStakeInWeiByIndex(g1.address[:], index))
+		require.EqualValues(t, 150, getLockedStakeMultiplierPercentByIndex(index))
+		require.Equal(t, _toWei(1150).String(), getVotingWeightInWeiByIndex(g1.address[:], index))
+		require.Equal(t, _toWei(900).String(), getRewardWeightInWeiByIndex(g1.address[:], index))
+	})
+}
+
+func TestOrbsVotingContract_lockedStake_toParticipantRewardWeights(t *testing.T) {
+	p1, p2 := [20]byte{0xa1}, [20]byte{0xb1}
+	defer restoreLockedStakeMultiplier()()
+
+	tests := []struct {
+		name         string
+		forVoting    bool
+		forRewards   bool
+		votingWeight uint64
+		expect       uint64
+	}{
+		{"neither", false, false, 200, 200},
+		{"both keeps voting weight", true, true, 300, 300},
+		{"rewards only", false, true, 200, 300},
+		{"voting only", true, false, 300, 200},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.name, func(t *testing.T) {
+			InServiceScope(nil, nil, func(m Mockery) {
+				ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+				_init()
+				LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING, LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = 200, cTest.forVoting, cTest.forRewards
+				_recordStakeAtElection(p1, _toWei(100), _toWei(100))
+				_recordStakeAtElection(p2, _toWei(200), big.NewInt(0))
+				votingWeights := weiStakes(map[[20]byte]uint64{p1: cTest.votingWeight, p2: 200})
+
+				// call
+				rewardWeights := _toParticipantRewardWeights([][20]byte{p1, p2}, votingWeights)
+
+				// assert
+				require.Equal(t, weiStakes(map[[20]byte]uint64{p1: cTest.expect, p2: 200}), rewardWeights)
+			})
+		})
+	}
+}
+
+func restoreLockedStakeMultiplier() func() {
+	percent, forVoting, forRewards := LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING, LOCKED_STAKE_MULTIPLIER_FOR_REWARDS
+	return func() {
+		LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING, LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = percent, forVoting, forRewards
+	}
+}
+
+func TestOrbsVotingContract_lockedStake_processRewards_RewardWeightsWithinCap(t *testing.T) {
+	p1, p2 := [20]byte{0xa1}, [20]byte{0xb1}
+	participants := [][20]byte{p1, p2}
+	defer restoreLockedStakeMultiplier()()
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		LOCKED_STAKE_MULTIPLIER_PERCENT, LOCKED_STAKE_MULTIPLIER_FOR_VOTING, LOCKED_STAKE_MULTIPLIER_FOR_REWARDS = 200, false, true
+		_recordStakeAtElection(p1, _toWei(50000000), _toWei(50000000))
+		_recordStakeAtElection(p2, _toWei(100000000), big.NewInt(0))
+		votingWeights := weiStakes(map[[20]byte]uint64{p1: 100000000, p2: 100000000})
+		totalVotes := _toWei(200000000)
+		rewardWeights := _toParticipantRewardWeights(participants, votingWeights)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "102362876396826750831697", p2: "68241917597884500554465"})
+
+		// call
+		_processRewards(totalVotes, participants, rewardWeights, nil, map[[20]byte]*big.Int{})
+
+		// assert
+		m.VerifyMocks()
+		maxReward := _maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, _toWei(250000000), ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT)
+		p1Reward, _ := new(big.Int).SetString(getCumulativeParticipationRewardInWei(p1[:]), 10)
+		p2Reward, _ := new(big.Int).SetString(getCumulativeParticipationRewardInWei(p2[:]), 10)
+		totalPaid := _addWei(p1Reward, p2Reward)
+		require.NotEqual(t, 0, votingWeights[p1].Cmp(rewardWeights[p1]), "reward weight differs from voting weight")
+		require.True(t, totalPaid.Cmp(maxReward) <= 0, "paid %s is over the election cap %s", totalPaid, maxReward)
+		require.Equal(t, maxReward, _addWei(totalPaid, _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION)))
+		require.Equal(t, new(big.Int).Div(new(big.Int).Mul(p2Reward, big.NewInt(3)), big.NewInt(2)), p1Reward, "rewarded by reward weight")
+	})
+}
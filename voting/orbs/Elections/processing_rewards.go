@@ -10,9 +10,10 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
-	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/env"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
-	"sort"
+	"math/big"
 )
 
 /***
@@ -24,72 +25,185 @@ const ELECTION_PARTICIPATION_MAX_REWARD = uint64(60000000)
 const ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT = uint64(8)
 const ELECTION_GUARDIAN_EXCELLENCE_MAX_REWARD = uint64(40000000)
 const ELECTION_GUARDIAN_EXCELLENCE_MAX_STAKE_REWARD_PERCENT = uint64(10)
-const ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER = 10
 const ELECTION_VALIDATOR_INTRODUCTION_REWARD = uint64(1000000)
 const ELECTION_VALIDATOR_MAX_STAKE_REWARD_PERCENT = uint64(4)
+const COMMISSION_BASIS_POINTS_DENOMINATOR = uint64(10000)
 
-func _processRewards(totalVotes uint64, elected [][20]byte, participants [][20]byte, participantStakes map[[20]byte]uint64, guardiansAccumulatedStake map[[20]byte]uint64) {
-	_processRewardsParticipants(totalVotes, participants, participantStakes)
+func _processRewards(totalVotes *big.Int, participants [][20]byte, participantStakes map[[20]byte]*big.Int, participantGuardians map[[20]byte][20]byte, guardiansAccumulatedStake map[[20]byte]*big.Int) {
+	_processRewardsParticipants(_sumParticipantStakes(participants, participantStakes), participants, participantStakes, participantGuardians)
 	_processRewardsGuardians(totalVotes, guardiansAccumulatedStake)
-	_processRewardsValidators(elected)
+	_processRewardsValidators()
 }
 
-func _processRewardsParticipants(totalVotes uint64, participants [][20]byte, participantStakes map[[20]byte]uint64) {
-	totalReward := _maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, totalVotes, ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT)
-	fmt.Printf("elections %10d rewards: %d participants total reward is %d \n", _getProcessCurrentElectionBlockNumber(), len(participantStakes), totalReward)
+// participants may weigh differently than their votes and may take part without a vote, so the reward is out of their own total
+func _sumParticipantStakes(participants [][20]byte, participantStakes map[[20]byte]*big.Int) *big.Int {
+	total := big.NewInt(0)
 	for _, participant := range participants {
-		stake := participantStakes[participant]
-		reward := safeuint64.Div(safeuint64.Mul(stake, totalReward), totalVotes)
-		fmt.Printf("elections %10d rewards: participant %x, stake %d adding %d\n", _getProcessCurrentElectionBlockNumber(), participant, stake, reward)
-		_addCumulativeParticipationReward(participant[:], reward)
+		total = _addWei(total, _getStakeOrZero(participantStakes, participant))
+	}
+	return total
+}
+
+// participantGuardians maps delegators to their guardian, the guardian commission is taken out of the delegator reward
+func _processRewardsParticipants(totalVotes *big.Int, participants [][20]byte, participantStakes map[[20]byte]*big.Int, participantGuardians map[[20]byte][20]byte) {
+	totalReward := _addWei(_maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, totalVotes, ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT), _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION))
+	totalReward = _capToRewardBudget(REWARD_CATEGORY_PARTICIPATION, totalReward)
+	fmt.Printf("elections %10d rewards: %d participants total reward is %d \n", _getProcessCurrentElectionBlockNumber(), len(participantStakes), totalReward)
+	distributed := big.NewInt(0)
+	commissions := make(map[[20]byte]*big.Int)
+	commissionGuardians := make([][20]byte, 0) // must not range over map as we set to state and order must be fixed
+	if totalVotes.Sign() > 0 {
+		for _, participant := range participants {
+			stake := _getStakeOrZero(participantStakes, participant)
+			reward := new(big.Int).Div(new(big.Int).Mul(stake, totalReward), totalVotes)
+			distributed = _addWei(distributed, reward)
+			if guardian, isDelegator := participantGuardians[participant]; isDelegator {
+				commission := _calculateGuardianCommission(guardian, reward)
+				if commission.Sign() > 0 {
+					fmt.Printf("elections %10d rewards: participant %x, guardian %x takes commission %d\n", _getProcessCurrentElectionBlockNumber(), participant, guardian, commission)
+					reward = new(big.Int).Sub(reward, commission)
+					_setParticipantCommissionPaidAtIndex(participant[:], _getProcessCurrentElectionIndex(), commission)
+					if guardianCommission, ok := commissions[guardian]; ok {
+						commissions[guardian] = _addWei(guardianCommission, commission)
+					} else {
+						commissions[guardian] = commission
+						commissionGuardians = append(commissionGuardians, guardian)
+					}
+				}
+			}
+			fmt.Printf("elections %10d rewards: participant %x, stake %d adding %d\n", _getProcessCurrentElectionBlockNumber(), participant, stake, reward)
+			_addCumulativeParticipationReward(participant[:], reward)
+		}
 	}
+	for _, guardian := range commissionGuardians {
+		fmt.Printf("elections %10d rewards: guardian %x, commission adding %d\n", _getProcessCurrentElectionBlockNumber(), guardian, commissions[guardian])
+		_setGuardianCommissionRewardAtIndex(guardian[:], _getProcessCurrentElectionIndex(), commissions[guardian])
+		_addCumulativeGuardianCommissionReward(guardian[:], commissions[guardian])
+	}
+	_spendRewardBudget(REWARD_CATEGORY_PARTICIPATION, distributed)
+	_setRewardRemainder(REWARD_CATEGORY_PARTICIPATION, new(big.Int).Sub(totalReward, distributed))
 }
 
-func _processRewardsGuardians(totalVotes uint64, guardiansAccumulatedStake map[[20]byte]uint64) {
+func _calculateGuardianCommission(guardian [20]byte, reward *big.Int) *big.Int {
+	commission := _getGuardianCommissionAtElection(guardian[:], _getProcessCurrentElectionIndex())
+	return new(big.Int).Div(new(big.Int).Mul(reward, new(big.Int).SetUint64(commission)), new(big.Int).SetUint64(COMMISSION_BASIS_POINTS_DENOMINATOR))
+}
+
+func _processRewardsGuardians(totalVotes *big.Int, guardiansAccumulatedStake map[[20]byte]*big.Int) {
 	fmt.Printf("elections %10d rewards: there are %d guardians with total reward is %d - choosing %d top guardians\n",
-		_getProcessCurrentElectionBlockNumber(), len(guardiansAccumulatedStake), totalVotes, ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER)
+		_getProcessCurrentElectionBlockNumber(), len(guardiansAccumulatedStake), totalVotes, getExcellenceProgramMaxNumber())
 	topGuardians, totalTopVotes := _getTopGuardians(guardiansAccumulatedStake)
 	fmt.Printf("elections %10d rewards: top %d guardians with total vote is now %d \n", _getProcessCurrentElectionBlockNumber(), len(topGuardians), totalTopVotes)
 
 	_setExcellenceProgramGuardians(topGuardians)
-	totalReward := _maxRewardForGroup(ELECTION_GUARDIAN_EXCELLENCE_MAX_REWARD, totalTopVotes, ELECTION_GUARDIAN_EXCELLENCE_MAX_STAKE_REWARD_PERCENT)
+	totalReward := _addWei(_maxRewardForGroup(ELECTION_GUARDIAN_EXCELLENCE_MAX_REWARD, totalTopVotes, ELECTION_GUARDIAN_EXCELLENCE_MAX_STAKE_REWARD_PERCENT), _getRewardRemainder(REWARD_CATEGORY_GUARDIAN_EXCELLENCE))
+	totalReward = _capToRewardBudget(REWARD_CATEGORY_GUARDIAN_EXCELLENCE, totalReward)
 	fmt.Printf("elections %10d rewards: guardians total reward is %d \n", _getProcessCurrentElectionBlockNumber(), totalReward)
-	for _, guardian := range topGuardians {
-		reward := safeuint64.Div(safeuint64.Mul(guardian.vote, totalReward), totalTopVotes)
-		fmt.Printf("elections %10d rewards: guardian %x, stake %d adding %d\n", _getProcessCurrentElectionBlockNumber(), guardian.address, guardian.vote, reward)
-		_addCumulativeGuardianExcellenceReward(guardian.address[:], reward)
+	distributed := big.NewInt(0)
+	if totalTopVotes.Sign() > 0 {
+		for _, guardian := range topGuardians {
+			reward := new(big.Int).Div(new(big.Int).Mul(guardian.vote, totalReward), totalTopVotes)
+			fmt.Printf("elections %10d rewards: guardian %x, stake %d adding %d\n", _getProcessCurrentElectionBlockNumber(), guardian.address, guardian.vote, reward)
+			_addCumulativeGuardianExcellenceReward(guardian.address[:], reward)
+			distributed = _addWei(distributed, reward)
+		}
+	}
+	_spendRewardBudget(REWARD_CATEGORY_GUARDIAN_EXCELLENCE, distributed)
+	_setRewardRemainder(REWARD_CATEGORY_GUARDIAN_EXCELLENCE, new(big.Int).Sub(totalReward, distributed))
+}
+
+/***
+ * Rewards - validators : the committee in office is rewarded in arrears when the next one is elected,
+ * pro-rated by the orbs blocks it actually sat in the committee, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS of them earn an election's share of the annual reward.
+ * the blocks are capped at that full term, so an overdue election or a faster block rate never pays more than an election's share.
+ * the stake reward is on the stakes recorded when the committee was elected, members that left the validator set since keep it.
+ * committees elected before this was introduced were rewarded when elected and are skipped.
+ */
+func _processRewardsValidators() {
+	index := getNumberOfElections()
+	if index == 0 || !_isElectionValidatorsRewardedInArrears(index) {
+		fmt.Printf("elections %10d rewards: committee of election %d was rewarded when elected\n", _getProcessCurrentElectionBlockNumber(), index)
+	} else {
+		startBlockHeight := getElectedValidatorsBlockHeightByIndex(index)
+		endBlockHeight := env.GetBlockHeight() + TRANSITION_PERIOD_LENGTH_IN_BLOCKS // when the next committee takes over
+		blocksInCommittee := uint64(0)
+		if endBlockHeight > startBlockHeight {
+			blocksInCommittee = endBlockHeight - startBlockHeight
+		}
+		if blocksInCommittee > ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS {
+			fmt.Printf("elections %10d rewards: committee of election %d sat %d blocks, rewarded for a full term\n", _getProcessCurrentElectionBlockNumber(), index, blocksInCommittee)
+			blocksInCommittee = ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+		}
+		_setElectionValidatorsRewardedBlocksAtIndex(index, blocksInCommittee)
+		_rewardValidatorsForBlocks(index, _getRewardedCommittee(index), blocksInCommittee)
 	}
+	_setElectionValidatorsRewardedInArrears(_getProcessCurrentElectionIndex())
 }
 
-func _processRewardsValidators(elected [][20]byte) {
-	electionValidatorIntroduction := _annualFactorize(safeuint64.Mul(ELECTION_VALIDATOR_INTRODUCTION_REWARD, 100))
-	validatorsStake := _getValidatorsStake()
-	fmt.Printf("elections %10d rewards: %d validadator introduction reward %d\n", _getProcessCurrentElectionBlockNumber(), len(validatorsStake), electionValidatorIntroduction)
-	for _, elected := range elected {
-		stake := validatorsStake[elected]
-		reward := safeuint64.Add(electionValidatorIntroduction, _annualFactorize(safeuint64.Mul(stake, ELECTION_VALIDATOR_MAX_STAKE_REWARD_PERCENT)))
-		fmt.Printf("elections %10d rewards: validator %x, stake %d adding %d\n", _getProcessCurrentElectionBlockNumber(), elected, stake, reward)
-		_addCumulativeValidatorReward(elected[:], reward)
+func _rewardValidatorsForBlocks(index uint32, committee [][20]byte, blocksInCommittee uint64) {
+	fullTerm := ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+	annualIntroduction := new(big.Int).Mul(_toWei(ELECTION_VALIDATOR_INTRODUCTION_REWARD), big.NewInt(100))
+	validatorsStake := _getCommitteeStakeAtIndex(index, committee)
+	divisor := new(big.Int).Mul(new(big.Int).SetUint64(_annualToElectionFactor()), new(big.Int).SetUint64(fullTerm))
+	fmt.Printf("elections %10d rewards: %d validators of election %d sat %d of %d blocks, full introduction reward %d\n",
+		_getProcessCurrentElectionBlockNumber(), len(committee), index, blocksInCommittee, fullTerm, _annualFactorize(annualIntroduction))
+	rewards := make([]*big.Int, len(committee))
+	for i, validator := range committee {
+		stake := _getStakeOrZero(validatorsStake, validator)
+		annualReward := _addWei(annualIntroduction, new(big.Int).Mul(stake, new(big.Int).SetUint64(ELECTION_VALIDATOR_MAX_STAKE_REWARD_PERCENT)))
+		proRated := new(big.Int).Mul(annualReward, new(big.Int).SetUint64(blocksInCommittee))
+		reward, remainder := new(big.Int).DivMod(_addWei(proRated, _getValidatorRewardRemainder(validator[:])), divisor, new(big.Int))
+		_setValidatorRewardRemainder(validator[:], remainder)
+		rewards[i] = reward
+	}
+	rewards = _scaleToRewardBudget(REWARD_CATEGORY_VALIDATOR, rewards)
+	distributed := big.NewInt(0)
+	for i, validator := range committee {
+		fmt.Printf("elections %10d rewards: validator %x, adding %d\n", _getProcessCurrentElectionBlockNumber(), validator, rewards[i])
+		_setValidatorRewardAtIndex(validator[:], index, rewards[i])
+		_addCumulativeValidatorReward(validator[:], rewards[i])
+		distributed = _addWei(distributed, rewards[i])
 	}
+	_spendRewardBudget(REWARD_CATEGORY_VALIDATOR, distributed)
 }
 
-func _getValidatorsStake() (validatorsStake map[[20]byte]uint64) {
-	numOfValidators := _getNumberOfValidators()
-	validatorsStake = make(map[[20]byte]uint64, numOfValidators)
-	for i := 0; i < numOfValidators; i++ {
-		validator := _getValidatorEthereumAddressAtIndex(i)
-		stake := getValidatorStake(validator[:])
+func _getRewardedCommittee(index uint32) [][20]byte {
+	committee := _splitAddresses(getElectedValidatorsEthereumAddressByIndex(index))
+	if isElectionElectedByFallback(index) == 0 || VALIDATOR_FALLBACK_REWARD_POLICY == VALIDATOR_FALLBACK_REWARD_POLICY_FULL {
+		return committee
+	}
+	if VALIDATOR_FALLBACK_REWARD_POLICY == VALIDATOR_FALLBACK_REWARD_POLICY_NONE {
+		fmt.Printf("elections %10d rewards: committee of election %d was elected by fallback and is not rewarded\n", _getProcessCurrentElectionBlockNumber(), index)
+		return nil
+	}
+	votedOut := _addressSet(_splitAddresses(getFallbackVotedOutValidatorsByIndex(index)))
+	rewarded := make([][20]byte, 0, len(committee))
+	for _, validator := range committee {
+		if votedOut[validator] {
+			fmt.Printf("elections %10d rewards: validator %x was voted out and elected by fallback, not rewarded\n", _getProcessCurrentElectionBlockNumber(), validator)
+		} else {
+			rewarded = append(rewarded, validator)
+		}
+	}
+	return rewarded
+}
+
+// validator stake is not weighted by the locked stake multiplier
+func _getCommitteeStakeAtIndex(index uint32, committee [][20]byte) (validatorsStake map[[20]byte]*big.Int) {
+	validatorsStake = make(map[[20]byte]*big.Int, len(committee))
+	for _, validator := range committee {
+		stake := _getWeightAtIndex(validator[:], index, false)
 		validatorsStake[validator] = stake
-		fmt.Printf("elections %10d rewards: validator %x, stake %d\n", _getProcessCurrentElectionBlockNumber(), validator, stake)
+		fmt.Printf("elections %10d rewards: validator %x, stake %d at election %d\n", _getProcessCurrentElectionBlockNumber(), validator, stake, index)
 	}
 	return
 }
 
-func _maxRewardForGroup(upperMaximum, totalVotes, percent uint64) uint64 {
-	upperMaximumPerElection := _annualFactorize(safeuint64.Mul(upperMaximum, 100))
-	calcMaximumPerElection := _annualFactorize(safeuint64.Mul(totalVotes, percent))
+func _maxRewardForGroup(upperMaximum uint64, totalVotes *big.Int, percent uint64) *big.Int {
+	upperMaximumPerElection := _annualFactorize(new(big.Int).Mul(_toWei(upperMaximum), big.NewInt(100)))
+	calcMaximumPerElection := _annualFactorize(new(big.Int).Mul(totalVotes, new(big.Int).SetUint64(percent)))
 	fmt.Printf("elections %10d rewards: uppperMax %d vs. %d = totalVotes %d * percent %d / number of annual election \n", _getProcessCurrentElectionBlockNumber(), upperMaximumPerElection, calcMaximumPerElection, totalVotes, percent)
-	if calcMaximumPerElection < upperMaximumPerElection {
+	if calcMaximumPerElection.Cmp(upperMaximumPerElection) < 0 {
 		return calcMaximumPerElection
 	}
 	return upperMaximumPerElection
@@ -98,116 +212,292 @@ func _maxRewardForGroup(upperMaximum, totalVotes, percent uint64) uint64 {
 const ANNUAL_TO_ELECTION_FACTOR_TIMEBASED = uint64(12174)
 const ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED = uint64(11723)
 
-func _annualFactorize(input uint64) uint64 {
+func _annualFactorize(input *big.Int) *big.Int {
+	result, _ := _annualFactorizeWithRemainder(input)
+	return result
+}
+
+func _annualFactorizeWithRemainder(input *big.Int) (*big.Int, *big.Int) {
+	return new(big.Int).DivMod(input, new(big.Int).SetUint64(_annualToElectionFactor()), new(big.Int))
+}
+
+func _annualToElectionFactor() uint64 {
 	if _isTimeBasedElections() {
-		return safeuint64.Div(input, ANNUAL_TO_ELECTION_FACTOR_TIMEBASED)
-	} else {
-		return safeuint64.Div(input, ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED)
+		return ANNUAL_TO_ELECTION_FACTOR_TIMEBASED
 	}
+	return ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED
+}
+
+/***
+ * Rewards - remainders : what was lost to integer division is added to the next election so nothing drifts away.
+ * group rewards carry the undistributed part of the group, validator rewards carry the sub-wei part of the annual division.
+ */
+func _formatRewardRemainder(category string) []byte {
+	return []byte(fmt.Sprintf("Reward_%s_RemainderInWei", category))
+}
+
+func _getRewardRemainder(category string) *big.Int {
+	return _readWei(_formatRewardRemainder(category))
+}
+
+func _setRewardRemainder(category string, remainder *big.Int) {
+	_writeRewardRemainder(_formatRewardRemainder(category), remainder)
+}
+
+func _formatValidatorRewardRemainder(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_RewardRemainder_%s", hex.EncodeToString(validator)))
+}
+
+func _getValidatorRewardRemainder(validator []byte) *big.Int {
+	return _readWei(_formatValidatorRewardRemainder(validator))
+}
+
+func _setValidatorRewardRemainder(validator []byte, remainder *big.Int) {
+	_writeRewardRemainder(_formatValidatorRewardRemainder(validator), remainder)
+}
+
+// state keeps the magnitude only, a negative remainder means more was paid than the reward and must not be carried as a positive one
+func _writeRewardRemainder(key []byte, remainder *big.Int) {
+	if remainder.Sign() < 0 {
+		panic(fmt.Sprintf("reward remainder %s is negative, more was distributed than the reward", remainder))
+	}
+	state.WriteBytes(key, remainder.Bytes())
+}
+
+/***
+ * Rewards - validators per election : blocks the committee was rewarded for, reward of each validator and fallback information
+ */
+func _formatElectionValidatorsRewardedInArrears(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_ValidatorsRewardedInArrears", index))
+}
+
+func _isElectionValidatorsRewardedInArrears(index uint32) bool {
+	return state.ReadUint32(_formatElectionValidatorsRewardedInArrears(index)) == 1
+}
+
+func _setElectionValidatorsRewardedInArrears(index uint32) {
+	state.WriteUint32(_formatElectionValidatorsRewardedInArrears(index), 1)
+}
+
+func _formatElectionValidatorsRewardedBlocks(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_ValidatorsRewardedBlocks", index))
+}
+
+func getValidatorsRewardedBlocksByIndex(index uint32) uint64 {
+	return state.ReadUint64(_formatElectionValidatorsRewardedBlocks(index))
+}
+
+func _setElectionValidatorsRewardedBlocksAtIndex(index uint32, blocks uint64) {
+	state.WriteUint64(_formatElectionValidatorsRewardedBlocks(index), blocks)
+}
+
+func _formatValidatorRewardAtIndex(validator []byte, index uint32) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_Election_%d_RewardInWei", hex.EncodeToString(validator), index))
+}
+
+func getValidatorRewardInWeiByIndex(validator []byte, index uint32) string {
+	return _readWei(_formatValidatorRewardAtIndex(validator, index)).String()
+}
+
+func _setValidatorRewardAtIndex(validator []byte, index uint32, reward *big.Int) {
+	state.WriteBytes(_formatValidatorRewardAtIndex(validator, index), reward.Bytes())
+}
+
+func _formatElectionElectedByFallback(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_ElectedByFallback", index))
+}
+
+func isElectionElectedByFallback(index uint32) uint32 {
+	return state.ReadUint32(_formatElectionElectedByFallback(index))
+}
+
+func _formatElectionFallbackVotedOut(index uint32) []byte {
+	return []byte(fmt.Sprintf("Election_%d_FallbackVotedOut", index))
+}
+
+func getFallbackVotedOutValidatorsByIndex(index uint32) []byte {
+	return state.ReadBytes(_formatElectionFallbackVotedOut(index))
 }
 
+func _setElectionElectedByFallbackAtIndex(index uint32, votedOut [][20]byte) {
+	state.WriteUint32(_formatElectionElectedByFallback(index), 1)
+	state.WriteBytes(_formatElectionFallbackVotedOut(index), _concatElectedEthereumAddresses(votedOut))
+}
+
+/***
+ * Rewards - cumulative : kept in wei, the uint64 getters return whole ORBS of the precise sum
+ */
 func _formatCumulativeParticipationReward(delegator []byte) []byte {
 	return []byte(fmt.Sprintf("Participant_CumReward_%s", hex.EncodeToString(delegator)))
 }
 
+func _formatCumulativeParticipationRewardInWei(delegator []byte) []byte {
+	return []byte(fmt.Sprintf("Participant_CumRewardInWei_%s", hex.EncodeToString(delegator)))
+}
+
 func getCumulativeParticipationReward(delegator []byte) uint64 {
 	return state.ReadUint64(_formatCumulativeParticipationReward(delegator))
 }
 
-func _addCumulativeParticipationReward(delegator []byte, reward uint64) {
-	_addCumulativeReward(_formatCumulativeParticipationReward(delegator), reward)
+func getCumulativeParticipationRewardInWei(delegator []byte) string {
+	return _readWeiOrLegacy(_formatCumulativeParticipationRewardInWei(delegator), _formatCumulativeParticipationReward(delegator)).String()
+}
+
+func _addCumulativeParticipationReward(delegator []byte, reward *big.Int) {
+	recipient := _redirectReward(REWARD_CATEGORY_PARTICIPATION, delegator, reward)
+	_addCumulativeReward(_formatCumulativeParticipationRewardInWei(recipient), _formatCumulativeParticipationReward(recipient), reward)
+	events.EmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, recipient, _toOrbs(reward), _getProcessCurrentElectionIndex(), reward.String())
 }
 
 func _formatCumulativeGuardianExcellenceReward(guardian []byte) []byte {
 	return []byte(fmt.Sprintf("Guardian_CumReward_%s", hex.EncodeToString(guardian)))
 }
 
+func _formatCumulativeGuardianExcellenceRewardInWei(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_CumRewardInWei_%s", hex.EncodeToString(guardian)))
+}
+
 func getCumulativeGuardianExcellenceReward(guardian []byte) uint64 {
 	return state.ReadUint64(_formatCumulativeGuardianExcellenceReward(guardian))
 }
 
-func _addCumulativeGuardianExcellenceReward(guardian []byte, reward uint64) {
-	_addCumulativeReward(_formatCumulativeGuardianExcellenceReward(guardian), reward)
+func getCumulativeGuardianExcellenceRewardInWei(guardian []byte) string {
+	return _readWeiOrLegacy(_formatCumulativeGuardianExcellenceRewardInWei(guardian), _formatCumulativeGuardianExcellenceReward(guardian)).String()
+}
+
+func _addCumulativeGuardianExcellenceReward(guardian []byte, reward *big.Int) {
+	recipient := _redirectReward(REWARD_CATEGORY_GUARDIAN_EXCELLENCE, guardian, reward)
+	_addCumulativeReward(_formatCumulativeGuardianExcellenceRewardInWei(recipient), _formatCumulativeGuardianExcellenceReward(recipient), reward)
+	events.EmitEvent(RewardAssigned, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, recipient, _toOrbs(reward), _getProcessCurrentElectionIndex(), reward.String())
 }
 
 func _formatCumulativeValidatorReward(validator []byte) []byte {
 	return []byte(fmt.Sprintf("Vaidator_CumReward_%s", hex.EncodeToString(validator)))
 }
 
+func _formatCumulativeValidatorRewardInWei(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_CumRewardInWei_%s", hex.EncodeToString(validator)))
+}
+
 func getCumulativeValidatorReward(validator []byte) uint64 {
 	return state.ReadUint64(_formatCumulativeValidatorReward(validator))
 }
 
-func _addCumulativeValidatorReward(validator []byte, reward uint64) {
-	_addCumulativeReward(_formatCumulativeValidatorReward(validator), reward)
+func getCumulativeValidatorRewardInWei(validator []byte) string {
+	return _readWeiOrLegacy(_formatCumulativeValidatorRewardInWei(validator), _formatCumulativeValidatorReward(validator)).String()
 }
 
-func _addCumulativeReward(key []byte, reward uint64) {
-	sumReward := safeuint64.Add(state.ReadUint64(key), reward)
-	state.WriteUint64(key, sumReward)
+func _addCumulativeValidatorReward(validator []byte, reward *big.Int) {
+	recipient := _redirectReward(REWARD_CATEGORY_VALIDATOR, validator, reward)
+	_addCumulativeReward(_formatCumulativeValidatorRewardInWei(recipient), _formatCumulativeValidatorReward(recipient), reward)
+	events.EmitEvent(RewardAssigned, REWARD_CATEGORY_VALIDATOR, recipient, _toOrbs(reward), _getProcessCurrentElectionIndex(), reward.String())
 }
 
-func _formatExcellenceProgramGuardians() []byte {
-	return []byte("Excellence_Program_Guardians")
+func _formatCumulativeGuardianCommissionReward(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_CumCommission_%s", hex.EncodeToString(guardian)))
 }
 
-func getExcellenceProgramGuardians() []byte {
-	return state.ReadBytes(_formatExcellenceProgramGuardians())
+func _formatCumulativeGuardianCommissionRewardInWei(guardian []byte) []byte {
+	return []byte(fmt.Sprintf("Guardian_CumCommissionInWei_%s", hex.EncodeToString(guardian)))
 }
 
-func _setExcellenceProgramGuardians(guardians guardianArray) {
-	guardiansForSave := make([]byte, 0, len(guardians)*20)
-	for _, guardian := range guardians {
-		guardiansForSave = append(guardiansForSave, guardian.address[:]...)
-	}
-	state.WriteBytes(_formatExcellenceProgramGuardians(), guardiansForSave)
+func getCumulativeGuardianCommissionReward(guardian []byte) uint64 {
+	return state.ReadUint64(_formatCumulativeGuardianCommissionReward(guardian))
+}
+
+func getCumulativeGuardianCommissionRewardInWei(guardian []byte) string {
+	return _readWei(_formatCumulativeGuardianCommissionRewardInWei(guardian)).String()
+}
+
+func _addCumulativeGuardianCommissionReward(guardian []byte, reward *big.Int) {
+	recipient := _redirectReward(REWARD_CATEGORY_GUARDIAN_COMMISSION, guardian, reward)
+	_addCumulativeReward(_formatCumulativeGuardianCommissionRewardInWei(recipient), _formatCumulativeGuardianCommissionReward(recipient), reward)
+	events.EmitEvent(RewardAssigned, REWARD_CATEGORY_GUARDIAN_COMMISSION, recipient, _toOrbs(reward), _getProcessCurrentElectionIndex(), reward.String())
+}
+
+func _addCumulativeReward(key []byte, legacyKey []byte, reward *big.Int) {
+	sumReward := _addWei(_readWeiOrLegacy(key, legacyKey), reward)
+	_writeWeiAndLegacy(key, legacyKey, sumReward)
 }
 
 /***
- * Rewards: Sort top guardians using sort.Interface
+ * Rewards - commission split per election : what each guardian earned and what each delegator paid
  */
-func _getTopGuardians(guardiansAccumulatedStake map[[20]byte]uint64) (topGuardiansStake guardianArray, totalVotes uint64) {
-	totalVotes = uint64(0)
+func _formatGuardianCommissionRewardAtIndex(guardian []byte, index uint32) []byte {
+	return []byte(fmt.Sprintf("Guardian_%s_Election_%d_CommissionInWei", hex.EncodeToString(guardian), index))
+}
 
-	guardianList := make(guardianArray, 0, len(guardiansAccumulatedStake))
-	for guardian, vote := range guardiansAccumulatedStake {
-		guardianList = append(guardianList, &guardianVote{guardian, vote})
-	}
-	sort.Sort(guardianList)
+func getGuardianCommissionRewardInWeiByIndex(guardian []byte, index uint32) string {
+	return _readWei(_formatGuardianCommissionRewardAtIndex(guardian, index)).String()
+}
 
-	i := 0
-	for i = 0; i < len(guardianList) && i < ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER; i++ {
-		fmt.Printf("elections %10d rewards: top guardian %x, has %d votes\n", _getProcessCurrentElectionBlockNumber(), guardianList[i].address, guardianList[i].vote)
-		totalVotes = safeuint64.Add(totalVotes, guardianList[i].vote)
-	}
-	for i = ELECTION_GUARDIAN_EXCELLENCE_MAX_NUMBER; i < len(guardianList); i++ {
-		if guardianList[i].vote != guardianList[i-1].vote {
-			break
-		}
-		fmt.Printf("elections %10d rewards: top guardian %x, has %d votes\n", _getProcessCurrentElectionBlockNumber(), guardianList[i].address, guardianList[i].vote)
-		totalVotes = safeuint64.Add(totalVotes, guardianList[i].vote)
-	}
-	if i < len(guardianList) {
-		return guardianList[0:i], totalVotes
-	} else {
-		return guardianList, totalVotes
-	}
+func _setGuardianCommissionRewardAtIndex(guardian []byte, index uint32, reward *big.Int) {
+	state.WriteBytes(_formatGuardianCommissionRewardAtIndex(guardian, index), reward.Bytes())
+}
+
+func _formatParticipantCommissionPaidAtIndex(delegator []byte, index uint32) []byte {
+	return []byte(fmt.Sprintf("Participant_%s_Election_%d_CommissionPaidInWei", hex.EncodeToString(delegator), index))
+}
+
+func getParticipantCommissionPaidInWeiByIndex(delegator []byte, index uint32) string {
+	return _readWei(_formatParticipantCommissionPaidAtIndex(delegator, index)).String()
+}
+
+func _setParticipantCommissionPaidAtIndex(delegator []byte, index uint32, commission *big.Int) {
+	state.WriteBytes(_formatParticipantCommissionPaidAtIndex(delegator, index), commission.Bytes())
 }
 
-type guardianVote struct {
-	address [20]byte
-	vote    uint64
+/***
+ * Rewards - redirection : rewards of an address with a reward recipient accumulate to the recipient.
+ * the cumulative getters show what each address received, redirected totals allow recovering what each address earned.
+ */
+func _formatRedirectedReward(category string, address []byte) []byte {
+	return []byte(fmt.Sprintf("Reward_%s_RedirectedInWei_%s", category, hex.EncodeToString(address)))
 }
-type guardianArray []*guardianVote
 
-func (s guardianArray) Len() int {
-	return len(s)
+func _formatReceivedRedirectedReward(category string, address []byte) []byte {
+	return []byte(fmt.Sprintf("Reward_%s_ReceivedRedirectedInWei_%s", category, hex.EncodeToString(address)))
 }
 
-func (s guardianArray) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+// rewards earned by the address and accumulated to its recipient
+func getRedirectedRewardInWei(category string, address []byte) string {
+	return _readWei(_formatRedirectedReward(category, address)).String()
 }
 
-func (s guardianArray) Less(i, j int) bool {
-	return s[i].vote > s[j].vote || (s[i].vote == s[j].vote && bytes.Compare(s[i].address[:], s[j].address[:]) > 0)
+// rewards earned by other addresses and accumulated to this address as their recipient
+func getReceivedRedirectedRewardInWei(category string, address []byte) string {
+	return _readWei(_formatReceivedRedirectedReward(category, address)).String()
+}
+
+// rewards earned by the address itself, regardless of where they were accumulated
+func getOriginalRewardInWei(category string, address []byte) string {
+	cumulative := _getCumulativeRewardInWei(category, address)
+	original := new(big.Int).Sub(cumulative, _readWei(_formatReceivedRedirectedReward(category, address)))
+	return _addWei(original, _readWei(_formatRedirectedReward(category, address))).String()
+}
+
+func _getCumulativeRewardInWei(category string, address []byte) *big.Int {
+	switch category {
+	case REWARD_CATEGORY_PARTICIPATION:
+		return _readWeiOrLegacy(_formatCumulativeParticipationRewardInWei(address), _formatCumulativeParticipationReward(address))
+	case REWARD_CATEGORY_GUARDIAN_EXCELLENCE:
+		return _readWeiOrLegacy(_formatCumulativeGuardianExcellenceRewardInWei(address), _formatCumulativeGuardianExcellenceReward(address))
+	case REWARD_CATEGORY_VALIDATOR:
+		return _readWeiOrLegacy(_formatCumulativeValidatorRewardInWei(address), _formatCumulativeValidatorReward(address))
+	case REWARD_CATEGORY_GUARDIAN_COMMISSION:
+		return _readWei(_formatCumulativeGuardianCommissionRewardInWei(address))
+	}
+	panic(fmt.Sprintf("unknown reward category %s", category))
+}
+
+func _redirectReward(category string, address []byte, reward *big.Int) []byte {
+	recipient := _getRewardRecipientAtElection(address, _getProcessCurrentElectionIndex())
+	if bytes.Equal(recipient, address) {
+		return address
+	}
+	fmt.Printf("elections %10d rewards: %s reward of %x redirected to %x\n", _getProcessCurrentElectionBlockNumber(), category, address, recipient)
+	state.WriteBytes(_formatRedirectedReward(category, address), _addWei(_readWei(_formatRedirectedReward(category, address)), reward).Bytes())
+	state.WriteBytes(_formatReceivedRedirectedReward(category, recipient), _addWei(_readWei(_formatReceivedRedirectedReward(category, recipient)), reward).Bytes())
+	events.EmitEvent(RewardRedirected, category, address, recipient, _getProcessCurrentElectionIndex(), reward.String())
+	return recipient
 }
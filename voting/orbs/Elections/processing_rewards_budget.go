@@ -0,0 +1,173 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/env"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
+	"time"
+)
+
+/***
+ * Rewards - budget : each category has an annual budget in ORBS for the calendar year (UTC) of the processing block, set by governance.
+ * an election's rewards are scaled down to its share of what remains, the remaining budget split over the elections left in the year,
+ * so a changed election period or skipped elections do not change the annual payout.
+ * a budget of 0 means the category is tracked but not limited.
+ */
+const REWARD_BUDGET_UNLIMITED = uint64(0)
+
+func _getRewardBudgetDefault(category string) uint64 {
+	switch category {
+	case REWARD_CATEGORY_PARTICIPATION:
+		return ELECTION_PARTICIPATION_MAX_REWARD
+	case REWARD_CATEGORY_GUARDIAN_EXCELLENCE:
+		return ELECTION_GUARDIAN_EXCELLENCE_MAX_REWARD
+	case REWARD_CATEGORY_VALIDATOR:
+		return REWARD_BUDGET_UNLIMITED
+	}
+	panic(fmt.Sprintf("reward category %s has no budget", category))
+}
+
+func _getCurrentRewardBudgetYear() uint32 {
+	return uint32(time.Unix(0, int64(env.GetBlockTimestamp())).UTC().Year())
+}
+
+// caps an election's total reward of the category by its share of what remains of the annual budget
+func _capToRewardBudget(category string, reward *big.Int) *big.Int {
+	year := _getCurrentRewardBudgetYear()
+	if getRewardBudget(category) == REWARD_BUDGET_UNLIMITED {
+		return reward
+	}
+	electionsLeft := _getElectionsLeftInYear()
+	share := new(big.Int).Div(_getRewardBudgetRemainingInWei(category, year), new(big.Int).SetUint64(electionsLeft))
+	if reward.Cmp(share) > 0 {
+		fmt.Printf("elections %10d rewards: %s reward %d capped to budget share %d of %d elections left in year %d\n", _getProcessCurrentElectionBlockNumber(), category, reward, share, electionsLeft, year)
+		return share
+	}
+	return reward
+}
+
+// the election being processed is one of those left, a partial election counts as one
+func _getElectionsLeftInYear() uint64 {
+	now := time.Unix(0, int64(env.GetBlockTimestamp())).UTC()
+	yearEnd := time.Date(now.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	timeLeft := uint64(yearEnd.Sub(now).Nanoseconds())
+	period := _getElectionPeriodDurationInNanos()
+	electionsLeft := timeLeft / period
+	if timeLeft%period > 0 {
+		electionsLeft++
+	}
+	return electionsLeft
+}
+
+// block based elections last the configured period in ethereum blocks at the block rate seen between the last two elections
+func _getElectionPeriodDurationInNanos() uint64 {
+	if _isTimeBasedElections() {
+		return getElectionPeriodInNanos()
+	}
+	return ELECTION_PERIOD_LENGTH_IN_BLOCKS * _getEthereumBlockTimeInNanos()
+}
+
+func _getEthereumBlockTimeInNanos() uint64 {
+	index := getNumberOfElections()
+	if index >= 2 {
+		previousTime, lastTime := getElectedValidatorsTimeInNanosByIndex(index-1), getElectedValidatorsTimeInNanosByIndex(index)
+		previousBlock, lastBlock := getElectedValidatorsBlockNumberByIndex(index-1), getElectedValidatorsBlockNumberByIndex(index)
+		if previousTime != 0 && lastTime > previousTime && lastBlock > previousBlock {
+			return (lastTime - previousTime) / (lastBlock - previousBlock)
+		}
+	}
+	return ETHEREUM_BLOCK_TIME_IN_NANOS
+}
+
+// scales each reward by the same ratio so they total no more than what remains of the annual budget
+func _scaleToRewardBudget(category string, rewards []*big.Int) []*big.Int {
+	total := big.NewInt(0)
+	for _, reward := range rewards {
+		total = _addWei(total, reward)
+	}
+	capped := _capToRewardBudget(category, total)
+	if capped.Cmp(total) == 0 {
+		return rewards
+	}
+	scaled := make([]*big.Int, len(rewards))
+	for i, reward := range rewards {
+		scaled[i] = new(big.Int).Div(new(big.Int).Mul(reward, capped), total)
+	}
+	return scaled
+}
+
+func _spendRewardBudget(category string, spent *big.Int) {
+	year := _getCurrentRewardBudgetYear()
+	state.WriteBytes(_formatRewardBudgetSpent(category, year), _addWei(_readWei(_formatRewardBudgetSpent(category, year)), spent).Bytes())
+	state.WriteUint32(_formatRewardBudgetLastYear(), year)
+}
+
+/***
+ * Rewards - budget data struct
+ */
+func _formatRewardBudget(category string) []byte {
+	return []byte(fmt.Sprintf("Reward_Budget_%s_Annual", category))
+}
+
+func _formatRewardBudgetIsSet(category string) []byte {
+	return []byte(fmt.Sprintf("Reward_Budget_%s_IsSet", category))
+}
+
+func _formatRewardBudgetSpent(category string, year uint32) []byte {
+	return []byte(fmt.Sprintf("Reward_Budget_%s_Year_%d_SpentInWei", category, year))
+}
+
+func _formatRewardBudgetLastYear() []byte {
+	return []byte("Reward_Budget_LastYear")
+}
+
+func setRewardBudget(category string, annualBudget uint64) {
+	_requireGovernance("setRewardBudget")
+	_setRewardBudget(category, annualBudget)
+}
+
+func _setRewardBudget(category string, annualBudget uint64) {
+	_getRewardBudgetDefault(category) // panics on unknown category
+	state.WriteUint64(_formatRewardBudget(category), annualBudget)
+	state.WriteUint32(_formatRewardBudgetIsSet(category), 1)
+}
+
+// annual budget in ORBS, 0 is unlimited
+func getRewardBudget(category string) uint64 {
+	if state.ReadUint32(_formatRewardBudgetIsSet(category)) == 0 {
+		return _getRewardBudgetDefault(category)
+	}
+	return state.ReadUint64(_formatRewardBudget(category))
+}
+
+// the year rewards were last paid in
+func getRewardBudgetLastYear() uint32 {
+	return state.ReadUint32(_formatRewardBudgetLastYear())
+}
+
+func getRewardBudgetSpentInWei(category string, year uint32) string {
+	return _readWei(_formatRewardBudgetSpent(category, year)).String()
+}
+
+// remaining budget of the year, empty when the category is unlimited
+func getRewardBudgetRemainingInWei(category string, year uint32) string {
+	if getRewardBudget(category) == REWARD_BUDGET_UNLIMITED {
+		return ""
+	}
+	return _getRewardBudgetRemainingInWei(category, year).String()
+}
+
+func _getRewardBudgetRemainingInWei(category string, year uint32) *big.Int {
+	remaining := new(big.Int).Sub(_toWei(getRewardBudget(category)), _readWei(_formatRewardBudgetSpent(category, year)))
+	if remaining.Sign() < 0 { // budget lowered below what was already paid
+		return big.NewInt(0)
+	}
+	return remaining
+}
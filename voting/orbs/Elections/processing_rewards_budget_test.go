@@ -0,0 +1,203 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestOrbsVotingContract_rewardBudget_ParticipationSpreadOverRestOfYear(t *testing.T) {
+	p1, p2 := [20]byte{0xa1}, [20]byte{0xb1}
+	participantStakes := map[[20]byte]uint64{p1: 60000000, p2: 40000000}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		totalVotes := _toWei(100000000)
+		_setRewardBudget(REWARD_CATEGORY_PARTICIPATION, 50000)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "303030303030303030303", p2: "202020202020202020202"})
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "29696969696969696969697", p2: "19797979797979797979798"})
+
+		// call
+		m.MockEnvBlockTimestamp(blockTimestampInYear(2020, time.March))
+		share := new(big.Int).Div(_toWei(50000), new(big.Int).SetUint64(_getElectionsLeftInYear()))
+		_processRewardsParticipants(totalVotes, [][20]byte{p1, p2}, weiStakes(participantStakes), nil)
+		spent := _readWei(_formatRewardBudgetSpent(REWARD_CATEGORY_PARTICIPATION, 2020))
+		require.Equal(t, share, _addWei(spent, _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION)), "an election gets its share of the remaining budget")
+		m.MockEnvBlockTimestamp(blockTimestampAtLastElectionOfYear(2020))
+		_processRewardsParticipants(totalVotes, [][20]byte{p1, p2}, weiStakes(participantStakes), nil)
+
+		// assert
+		m.VerifyMocks()
+		paid := sumWei(t, getCumulativeParticipationRewardInWei(p1[:]), getCumulativeParticipationRewardInWei(p2[:]))
+		require.Equal(t, paid, getRewardBudgetSpentInWei(REWARD_CATEGORY_PARTICIPATION, 2020))
+		require.True(t, _toWei(50000).Cmp(_readWei(_formatRewardBudgetSpent(REWARD_CATEGORY_PARTICIPATION, 2020))) >= 0, "never more than the annual budget")
+		require.Equal(t, _toWei(50000), _addWei(_readWei(_formatRewardBudgetSpent(REWARD_CATEGORY_PARTICIPATION, 2020)), _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION)), "last election of the year uses up the budget")
+		require.EqualValues(t, 2020, getRewardBudgetLastYear())
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_getElectionsLeftInYear(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		m.MockEnvBlockTimestamp(int(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).UnixNano()))
+		require.EqualValues(t, 118, _getElectionsLeftInYear(), "a partial election counts as one")
+		m.MockEnvBlockTimestamp(int(time.Date(2020, time.July, 2, 0, 0, 0, 0, time.UTC).UnixNano()))
+		require.EqualValues(t, 59, _getElectionsLeftInYear())
+		m.MockEnvBlockTimestamp(blockTimestampAtLastElectionOfYear(2020))
+		require.EqualValues(t, 1, _getElectionsLeftInYear())
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_getElectionsLeftInYearByBlockRate(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEnvBlockHeight(100)
+		newYear := uint64(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+		blockTime := uint64((10 * time.Second).Nanoseconds())
+		mockElectionCompletedEvent(m, 1, 1000)
+		mockElectionCompletedEvent(m, 2, 1000+ELECTION_PERIOD_LENGTH_IN_BLOCKS)
+		_setElectedValidators(nil, newYear, 1000)
+		_setElectedValidators(nil, newYear+ELECTION_PERIOD_LENGTH_IN_BLOCKS*blockTime, 1000+ELECTION_PERIOD_LENGTH_IN_BLOCKS)
+		m.VerifyMocks()
+
+		m.MockEnvBlockTimestamp(int(newYear))
+		require.EqualValues(t, 159, _getElectionsLeftInYear(), "20000 blocks of 10 seconds")
+		ELECTION_PERIOD_LENGTH_IN_BLOCKS = 40000
+		defer func() { ELECTION_PERIOD_LENGTH_IN_BLOCKS = 20000 }()
+		require.EqualValues(t, 80, _getElectionsLeftInYear(), "a longer configured period leaves fewer elections")
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_getElectionsLeftInYearTimeBased(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		switchToTimeBasedElections()
+
+		m.MockEnvBlockTimestamp(int(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).UnixNano()))
+		require.EqualValues(t, 122, _getElectionsLeftInYear(), "an election every three days")
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_NewYearStartsAFreshBudget(t *testing.T) {
+	p1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		totalVotes := _toWei(100000000)
+		_setRewardBudget(REWARD_CATEGORY_PARTICIPATION, 1000)
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_PARTICIPATION, p1, 1, _toWei(1000).String())
+		m.MockEnvBlockTimestamp(blockTimestampAtLastElectionOfYear(2020))
+		_processRewardsParticipants(totalVotes, [][20]byte{p1}, weiStakes(map[[20]byte]uint64{p1: 100000000}), nil)
+
+		// call
+		m.MockEnvBlockTimestamp(blockTimestampAtLastElectionOfYear(2021))
+		_processRewardsParticipants(totalVotes, [][20]byte{p1}, weiStakes(map[[20]byte]uint64{p1: 100000000}), nil)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, _toWei(1000).String(), getRewardBudgetSpentInWei(REWARD_CATEGORY_PARTICIPATION, 2020))
+		require.Equal(t, _toWei(1000).String(), getRewardBudgetSpentInWei(REWARD_CATEGORY_PARTICIPATION, 2021))
+		require.Equal(t, _toWei(2000).String(), getCumulativeParticipationRewardInWei(p1[:]))
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_ValidatorsScaledToRemaining(t *testing.T) {
+	v1, v2 := [20]byte{0xa1}, [20]byte{0xb1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		m.MockEnvBlockTimestamp(blockTimestampAtLastElectionOfYear(2020))
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_VALIDATOR, 1, map[[20]byte]string{v1: "25925925925925925925", v2: "24074074074074074074"})
+		_recordStakeAtElection(v1, _toWei(3000000), big.NewInt(0))
+		_recordStakeAtElection(v2, _toWei(1000000), big.NewInt(0))
+		_setRewardBudget(REWARD_CATEGORY_VALIDATOR, 50)
+
+		// call
+		_rewardValidatorsForBlocks(1, [][20]byte{v1, v2}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
+
+		// assert
+		m.VerifyMocks()
+		v1Reward, _ := new(big.Int).SetString(getValidatorRewardInWeiByIndex(v1[:], 1), 10)
+		v2Reward, _ := new(big.Int).SetString(getValidatorRewardInWeiByIndex(v2[:], 1), 10)
+		require.True(t, v1Reward.Cmp(v2Reward) > 0, "scaling keeps the larger stake reward larger")
+		require.Equal(t, sumWei(t, v1Reward.String(), v2Reward.String()), getRewardBudgetSpentInWei(REWARD_CATEGORY_VALIDATOR, 2020))
+		require.True(t, _toWei(50).Cmp(_addWei(v1Reward, v2Reward)) >= 0)
+		require.True(t, _toWei(49).Cmp(_addWei(v1Reward, v2Reward)) < 0, "remaining budget is used")
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_UnlimitedCategoryIsTracked(t *testing.T) {
+	v1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		m.MockEnvBlockTimestamp(blockTimestampInYear(2020, time.March))
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_VALIDATOR, v1, 1, "8530239699735562569308")
+		_setValidators([][20]byte{v1})
+		_setValidatorStake(v1[:], 0)
+
+		// call
+		_rewardValidatorsForBlocks(1, [][20]byte{v1}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, REWARD_BUDGET_UNLIMITED, getRewardBudget(REWARD_CATEGORY_VALIDATOR))
+		require.Equal(t, getCumulativeValidatorRewardInWei(v1[:]), getRewardBudgetSpentInWei(REWARD_CATEGORY_VALIDATOR, 2020))
+		require.Equal(t, "", getRewardBudgetRemainingInWei(REWARD_CATEGORY_VALIDATOR, 2020))
+		require.EqualValues(t, ELECTION_PARTICIPATION_MAX_REWARD, getRewardBudget(REWARD_CATEGORY_PARTICIPATION), "default budget is the annual maximum")
+	})
+}
+
+func TestOrbsVotingContract_rewardBudget_UnknownCategoryPanics(t *testing.T) {
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		require.Panics(t, func() {
+			_setRewardBudget(REWARD_CATEGORY_GUARDIAN_COMMISSION, 1000)
+		}, "commission is paid out of the participation budget")
+	})
+}
+
+func blockTimestampInYear(year int, month time.Month) int {
+	return int(time.Date(year, month, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+}
+
+func blockTimestampAtLastElectionOfYear(year int) int {
+	return int(time.Date(year, time.December, 31, 12, 0, 0, 0, time.UTC).UnixNano())
+}
+
+func TestOrbsVotingContract_rewardBudget_SetByGovernance(t *testing.T) {
+	governance := []byte{0x01, 0x02}
+	_setGovernanceInTests(t, governance)
+
+	InServiceScope(governance, nil, func(m Mockery) {
+		_init()
+
+		// call
+		setRewardBudget(REWARD_CATEGORY_VALIDATOR, 1000)
+
+		// assert
+		require.EqualValues(t, 1000, getRewardBudget(REWARD_CATEGORY_VALIDATOR))
+	})
+
+	InServiceScope([]byte{0x03}, nil, func(m Mockery) {
+		_init()
+
+		requirePanicsWithErrorCode(t, ERROR_NOT_GOVERNANCE, func() {
+			setRewardBudget(REWARD_CATEGORY_VALIDATOR, 1000)
+		}, "should panic because signer is not the governance address")
+	})
+}
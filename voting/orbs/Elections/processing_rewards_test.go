@@ -8,46 +8,42 @@ package elections_systemcontract
 
 import (
 	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 )
 
 func TestOrbsVotingContract_annualFactorize(t *testing.T) {
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
-		require.EqualValues(t, 75, _annualFactorize(888000))
+		require.EqualValues(t, 75, _annualFactorize(big.NewInt(888000)).Uint64())
 		switchToTimeBasedElections()
-		require.EqualValues(t, 72, _annualFactorize(888000))
+		require.EqualValues(t, 72, _annualFactorize(big.NewInt(888000)).Uint64())
 	})
 }
 
-func TestOrbsVotingContract_processRewards_getValidatorStakes(t *testing.T) {
-	validators := [][20]byte{{0x01}, {0x02}, {0x03}}
-	stakes := []uint64{100, 200}
-	stakesReal := []uint64{100, 200, 0}
+func TestOrbsVotingContract_processRewards_getCommitteeStakeAtIndex(t *testing.T) {
+	v1, v2, v3 := [20]byte{0x01}, [20]byte{0x02}, [20]byte{0x03}
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
 
 		// prepare
-		_setNumberOfValidators(len(validators))
-		for i := 0; i < len(validators); i++ {
-			_setValidatorEthereumAddressAtIndex(i, validators[i][:])
-		}
-		for i := 0; i < len(stakes); i++ {
-			_setValidatorStake(validators[i][:], stakes[i])
-		}
+		_recordStakeAtElection(v1, _toWei(100), _toWei(50))
+		_recordStakeAtElection(v2, _toWei(200), big.NewInt(0))
+		_setValidators([][20]byte{v2, v3}) // v1 left the validator set since
+		_setValidatorStake(v2[:], 900)
 
 		// call
-		vtoS := _getValidatorsStake()
+		vtoS := _getCommitteeStakeAtIndex(1, [][20]byte{v1, v2, v3})
 
 		// assert
-		require.EqualValues(t, len(validators), len(vtoS))
-		for i := 0; i < _getNumberOfValidators(); i++ {
-			require.EqualValues(t, validators[i], _getValidatorEthereumAddressAtIndex(i))
-			require.EqualValues(t, stakesReal[i], getValidatorStake(validators[i][:]))
-		}
+		require.Len(t, vtoS, 3)
+		require.Equal(t, _toWei(150), vtoS[v1], "locked stake counts without the multiplier")
+		require.Equal(t, _toWei(200), vtoS[v2], "stake at election and not the current one")
+		require.Equal(t, big.NewInt(0), vtoS[v3])
 	})
 }
 
@@ -58,10 +54,16 @@ func TestOrbsVotingContract_processRewards_processRewardsParticipants(t *testing
 	participantStakes := map[[20]byte]uint64{p1: 100000, p2: 50000, p3: 0, p4: 10000, p5: 40000}
 
 	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 		_init()
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, p1[:], uint64(68), uint32(1), "68241917597884500554")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, p2[:], uint64(34), uint32(1), "34120958798942250277")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, p3[:], uint64(0), uint32(1), "0")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, p4[:], uint64(6), uint32(1), "6824191759788450055")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_PARTICIPATION, p5[:], uint64(27), uint32(1), "27296767039153800221")
 
 		// call
-		_processRewardsParticipants(totalVotes, participants, participantStakes)
+		_processRewardsParticipants(_toWei(totalVotes), participants, weiStakes(participantStakes), nil)
 
 		// assert
 		require.EqualValues(t, 34, getCumulativeParticipationReward(p2[:]))
@@ -69,6 +71,7 @@ func TestOrbsVotingContract_processRewards_processRewardsParticipants(t *testing
 		require.EqualValues(t, 68, getCumulativeParticipationReward(p1[:]))
 		require.EqualValues(t, 0, getCumulativeParticipationReward(p3[:]))
 		require.EqualValues(t, 27, getCumulativeParticipationReward(p5[:]))
+		require.Equal(t, "68241917597884500554", getCumulativeParticipationRewardInWei(p1[:]))
 	})
 }
 
@@ -80,11 +83,13 @@ func TestOrbsVotingContract_processRewards_processRewardsParticipants_TotalAbove
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1.address: "639767977480167192698", p2.address: "127953595496033438539622", p3.address: "0"})
 
 		// call
-		_processRewardsParticipants(totalVotes, participants, h.getAllStakes())
+		_processRewardsParticipants(_toWei(totalVotes), participants, h.getAllStakes(), nil)
 
 		// assert
+		m.VerifyMocks()
 		max := ELECTION_PARTICIPATION_MAX_REWARD * 100 / ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED
 		require.EqualValues(t, max/4, getCumulativeParticipationReward(p2.address[:]))
 		require.EqualValues(t, max/800, getCumulativeParticipationReward(p1.address[:]))
@@ -98,12 +103,15 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_SmallNumberOf
 	guardiansAccumulatedStakes := map[[20]byte]uint64{p1: 540000, p2: 250000, p3: 0, p4: 10000, p5: 20000}
 
 	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{p1: "460632943785720378742", p2: "213255992493389064232", p3: "0", p4: "8530239699735562569", p5: "17060479399471125138"})
 
 		// call
-		_processRewardsGuardians(totalVotes, guardiansAccumulatedStakes)
+		_processRewardsGuardians(_toWei(totalVotes), weiStakes(guardiansAccumulatedStakes))
 
 		// assert
+		m.VerifyMocks()
 		require.EqualValues(t, 213, getCumulativeGuardianExcellenceReward(p2[:]))
 		require.EqualValues(t, 8, getCumulativeGuardianExcellenceReward(p4[:]))
 		require.EqualValues(t, 460, getCumulativeGuardianExcellenceReward(p1[:]))
@@ -118,12 +126,15 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_SmallNumberOf
 	guardiansAccumulatedStakes := map[[20]byte]uint64{p1: 400000000, p2: 100000000, p3: 0}
 
 	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{p1: "272967670391538002217861", p2: "68241917597884500554465", p3: "0"})
 
 		// call
-		_processRewardsGuardians(totalVotes, guardiansAccumulatedStakes)
+		_processRewardsGuardians(_toWei(totalVotes), weiStakes(guardiansAccumulatedStakes))
 
 		// assert
+		m.VerifyMocks()
 		max := ELECTION_GUARDIAN_EXCELLENCE_MAX_REWARD * 100 / ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED
 		require.EqualValues(t, max*4/5, getCumulativeGuardianExcellenceReward(p1[:]))
 		require.EqualValues(t, max/5, getCumulativeGuardianExcellenceReward(p2[:]))
@@ -142,11 +153,17 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_LargeNumberOf
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		topRewards := map[[20]byte]string{p1.address: "1876652733941823765247", p2.address: "1023628763968267508316"}
+		for i, reward := range []string{"341209587989422502772", "426511984986778128465", "511814381984133754158", "597116778981489379851", "682419175978845005544", "767721572976200631237", "853023969973556256930", "938326366970911882623"} {
+			topRewards[h.getActor(i+2).address] = reward
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, topRewards)
 
 		// call
-		_processRewardsGuardians(0, h.getAllStakes())
+		_processRewardsGuardians(big.NewInt(0), h.getAllStakes())
 
 		// assert
+		m.VerifyMocks()
 		for i := 2; i < 12; i++ {
 			calculatedTotal += h.getActor(i).stake
 		}
@@ -154,7 +171,7 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_LargeNumberOf
 			calcualtedTotalRewardFromStake += getCumulativeGuardianExcellenceReward(h.getActor(i).address[:])
 		}
 
-		require.EqualValues(t, 8013, calcualtedTotalRewardFromStake)
+		require.EqualValues(t, 8014, calcualtedTotalRewardFromStake)
 		require.EqualValues(t, 1876, getCumulativeGuardianExcellenceReward(p1.address[:]))
 		require.EqualValues(t, 1023, getCumulativeGuardianExcellenceReward(p2.address[:]))
 		require.EqualValues(t, 0, getCumulativeGuardianExcellenceReward(p3.address[:]))
@@ -175,11 +192,17 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_LargeNumberOf
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		topRewards := map[[20]byte]string{p1.address: "1876652733941823765247", p2.address: "1023628763968267508316", p4.address: "341209587989422502772"}
+		for i, reward := range []string{"341209587989422502772", "426511984986778128465", "511814381984133754158", "597116778981489379851", "682419175978845005544", "767721572976200631237", "853023969973556256930", "938326366970911882623"} {
+			topRewards[h.getActor(i+2).address] = reward
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, topRewards)
 
 		// call
-		_processRewardsGuardians(0, h.getAllStakes())
+		_processRewardsGuardians(big.NewInt(0), h.getAllStakes())
 
 		// assert
+		m.VerifyMocks()
 		for i := 2; i < 12; i++ {
 			calculatedTotal += h.getActor(i).stake
 		}
@@ -188,7 +211,7 @@ func TestOrbsVotingContract_processRewards_processRewardsGuardians_LargeNumberOf
 		}
 
 		require.EqualValues(t, 220, len(getExcellenceProgramGuardians()))
-		require.EqualValues(t, 8354, calcualtedTotalRewardFromStake)
+		require.EqualValues(t, 8355, calcualtedTotalRewardFromStake)
 		require.EqualValues(t, 1876, getCumulativeGuardianExcellenceReward(p1.address[:]))
 		require.EqualValues(t, 1023, getCumulativeGuardianExcellenceReward(p2.address[:]))
 		require.EqualValues(t, 0, getCumulativeGuardianExcellenceReward(p3.address[:]))
@@ -210,11 +233,17 @@ func TestOrbsVotingContract_processRewards_processRewardsParticipants_LargeNumbe
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
+		topRewards := map[[20]byte]string{p1.address: "79857563146460585755225", p2.address: "43558670807160319502850"}
+		for i, reward := range []string{"14519556935720106500950", "18149446169650133126187", "21779335403580159751425", "25409224637510186376662", "29039113871440213001900", "32669003105370239627137", "36298892339300266252375", "39928781573230292877612"} {
+			topRewards[h.getActor(i+2).address] = reward
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, topRewards)
 
 		// call
-		_processRewardsGuardians(0, h.getAllStakes())
+		_processRewardsGuardians(big.NewInt(0), h.getAllStakes())
 
 		// assert
+		m.VerifyMocks()
 		for i := 2; i < 12; i++ {
 			calculatedTotal += uint64(h.getActor(i).stake)
 		}
@@ -237,24 +266,21 @@ func TestOrbsVotingContract_processRewards_processRewardsValidators(t *testing.T
 	validatorStakes := map[[20]byte]uint64{p1: 1000000, p2: 500000, p3: 0, p4: 100000, p5: 400000}
 
 	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 		_init()
-		_setNumberOfValidators(len(validatorStakes))
-		_setValidatorEthereumAddressAtIndex(0, p1[:])
-		_setValidatorStake(p1[:], uint64(1000000))
-		_setValidatorEthereumAddressAtIndex(1, p2[:])
-		_setValidatorStake(p2[:], uint64(500000))
-		_setValidatorEthereumAddressAtIndex(2, p3[:])
-		_setValidatorStake(p3[:], uint64(0))
-		_setValidatorEthereumAddressAtIndex(3, p4[:])
-		_setValidatorStake(p4[:], uint64(100000))
-		_setValidatorEthereumAddressAtIndex(4, p5[:])
-		_setValidatorStake(p5[:], uint64(400000))
+		for _, validator := range [][20]byte{p1, p2, p3, p4, p5} {
+			_recordStakeAtElection(validator, _toWei(validatorStakes[validator]), big.NewInt(0))
+		}
+		electionValidatorIntroduction := ELECTION_VALIDATOR_INTRODUCTION_REWARD * 100 / ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_VALIDATOR, p1[:], electionValidatorIntroduction+341, uint32(1), "8871449287724985072080")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_VALIDATOR, p2[:], electionValidatorIntroduction+170, uint32(1), "8700844493730273820694")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_VALIDATOR, p3[:], electionValidatorIntroduction+0, uint32(1), "8530239699735562569308")
+		m.MockEmitEvent(RewardAssigned, REWARD_CATEGORY_VALIDATOR, p4[:], electionValidatorIntroduction+34, uint32(1), "8564360658534504819585")
 
 		// call
-		_processRewardsValidators([][20]byte{p1, p2, p3, p4})
+		_rewardValidatorsForBlocks(1, [][20]byte{p1, p2, p3, p4}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
 
 		// assert
-		electionValidatorIntroduction := ELECTION_VALIDATOR_INTRODUCTION_REWARD * 100 / ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED
 		require.EqualValues(t, electionValidatorIntroduction+341, getCumulativeValidatorReward(p1[:]))
 		require.EqualValues(t, electionValidatorIntroduction+170, getCumulativeValidatorReward(p2[:]))
 		require.EqualValues(t, electionValidatorIntroduction+0, getCumulativeValidatorReward(p3[:]))
@@ -278,7 +304,7 @@ func TestOrbsVotingContract_processRewards_maxRewardForGroup(t *testing.T) {
 		_init()
 		for i := range tests {
 			cTest := tests[i]
-			reward := _maxRewardForGroup(cTest.max, cTest.total, cTest.percent)
+			reward := _toOrbs(_maxRewardForGroup(cTest.max, _toWei(cTest.total), cTest.percent))
 			require.EqualValues(t, cTest.expect, reward, fmt.Sprintf("%s was calculated to %d instead of %d", cTest.name, reward, cTest.expect))
 		}
 	})
@@ -292,6 +318,7 @@ type rewardHarness struct {
 }
 
 func newRewardHarness() *rewardHarness {
+	ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
 	return &rewardHarness{nextAddress: 0xa1, stakes: make(map[[20]byte]uint64)}
 }
 
@@ -311,6 +338,441 @@ func (f *rewardHarness) getNumActors() int {
 	return len(f.actors)
 }
 
-func (f *rewardHarness) getAllStakes() map[[20]byte]uint64 {
-	return f.stakes
+func (f *rewardHarness) getAllStakes() map[[20]byte]*big.Int {
+	return weiStakes(f.stakes)
+}
+
+func TestOrbsVotingContract_processRewards_FractionalStakeIsRewarded(t *testing.T) {
+	p1, p2 := [20]byte{0xa0}, [20]byte{0xb1}
+	halfOrbs := big.NewInt(500000000000000000)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "341209587989422", p2: "682419175978845005544"})
+		participantStakes := map[[20]byte]*big.Int{p1: halfOrbs, p2: _toWei(1000000)}
+		totalVotes := _addWei(halfOrbs, _toWei(1000000))
+
+		// call
+		_processRewardsParticipants(totalVotes, [][20]byte{p1, p2}, participantStakes, nil)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 0, getCumulativeParticipationReward(p1[:]), "less than one ORBS")
+		require.Equal(t, "341209587989422", getCumulativeParticipationRewardInWei(p1[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_RemainderCarriedToNextElection(t *testing.T) {
+	p1, p2, p3 := [20]byte{0xa0}, [20]byte{0xb1}, [20]byte{0xc1}
+	participants := [][20]byte{p1, p2, p3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "68241917597884500554", p2: "68241917597884500554", p3: "68241917597884500554"})
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "68241917597884500555", p2: "68241917597884500555", p3: "68241917597884500555"})
+		participantStakes := weiStakes(map[[20]byte]uint64{p1: 100000, p2: 100000, p3: 100000})
+		totalVotes := _toWei(300000)
+		electionReward := _maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, totalVotes, ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT)
+
+		// call
+		for i := 0; i < 3; i++ {
+			_processRewardsParticipants(totalVotes, participants, participantStakes, nil)
+		}
+
+		// assert
+		m.VerifyMocks()
+		distributed := big.NewInt(0)
+		for _, p := range participants {
+			reward, _ := new(big.Int).SetString(getCumulativeParticipationRewardInWei(p[:]), 10)
+			distributed = _addWei(distributed, reward)
+		}
+		remainder := _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION)
+		require.True(t, remainder.Cmp(big.NewInt(3)) < 0, "remainder is less than one wei per participant")
+		require.Zero(t, _addWei(distributed, remainder).Cmp(new(big.Int).Mul(electionReward, big.NewInt(3))), "nothing is lost between elections")
+	})
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorRemainderCarried(t *testing.T) {
+	p1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_VALIDATOR, p1, 1, "8530239699735562569308")
+		_setValidators([][20]byte{p1})
+		_setValidatorStake(p1[:], 0)
+		twoAnnualIntroductions := new(big.Int).Mul(_toWei(ELECTION_VALIDATOR_INTRODUCTION_REWARD), big.NewInt(200))
+		expectedReward, expectedRemainder := new(big.Int).DivMod(twoAnnualIntroductions, new(big.Int).SetUint64(ANNUAL_TO_ELECTION_FACTOR_BLOCKBASED), new(big.Int))
+
+		// call
+		_rewardValidatorsForBlocks(1, [][20]byte{p1}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
+		_rewardValidatorsForBlocks(2, [][20]byte{p1}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, expectedReward.String(), getCumulativeValidatorRewardInWei(p1[:]), "two elections together as if divided once")
+		require.Zero(t, new(big.Int).Mul(expectedRemainder, new(big.Int).SetUint64(ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)).Cmp(_getValidatorRewardRemainder(p1[:])), "remainder is kept in block units")
+	})
+}
+
+func TestOrbsVotingContract_processRewards_NegativeRemainderIsNotStored(t *testing.T) {
+	p1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_setRewardRemainder(REWARD_CATEGORY_PARTICIPATION, big.NewInt(5))
+
+		// call & assert
+		require.Panics(t, func() {
+			_setRewardRemainder(REWARD_CATEGORY_PARTICIPATION, big.NewInt(-7))
+		})
+		require.Panics(t, func() {
+			_setValidatorRewardRemainder(p1[:], big.NewInt(-1))
+		})
+		require.EqualValues(t, 5, _getRewardRemainder(REWARD_CATEGORY_PARTICIPATION).Int64())
+		require.Zero(t, _getValidatorRewardRemainder(p1[:]).Sign())
+	})
+}
+
+func TestOrbsVotingContract_processRewards_LegacyCumulativeRewardIsKept(t *testing.T) {
+	p1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, p1, 1, "1500000000000000000")
+		state.WriteUint64(_formatCumulativeGuardianExcellenceReward(p1[:]), 100) // written before wei precision
+
+		// call
+		_addCumulativeGuardianExcellenceReward(p1[:], big.NewInt(1500000000000000000))
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, 101, getCumulativeGuardianExcellenceReward(p1[:]))
+		require.Equal(t, "101500000000000000000", getCumulativeGuardianExcellenceRewardInWei(p1[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_GuardianCommissionOnChainedDelegators(t *testing.T) {
+	g, d1, d2, d3 := [20]byte{0xa0}, [20]byte{0xb1}, [20]byte{0xb2}, [20]byte{0xb3}
+	relationship := map[[20]byte][][20]byte{g: {d1}, d1: {d2, d3}} // d2, d3 delegate to g through d1
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g: "6824191759788450055", d1: "12283545167619210099", d2: "18425317751428815150", d3: "24567090335238420199"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_COMMISSION, g, 1, "6141772583809605049")
+		index := _getProcessCurrentElectionIndex()
+		_setGuardianCommission(g[:], 1000, index)
+		delegatorStakes := weiStakes(map[[20]byte]uint64{d1: 20000, d2: 30000, d3: 40000})
+		participants := [][20]byte{g}
+		participantStakes := map[[20]byte]*big.Int{g: _toWei(10000)}
+		participantGuardians := make(map[[20]byte][20]byte)
+		totalVotes := _addWei(_toWei(10000), _calculateOneGuardianVoteRecursive(g, relationship, delegatorStakes, &participants, participantStakes, participantGuardians))
+		totalReward := _maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, totalVotes, ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT)
+		rewardOf := func(p [20]byte) *big.Int {
+			return new(big.Int).Div(new(big.Int).Mul(participantStakes[p], totalReward), totalVotes)
+		}
+
+		// call
+		_processRewardsParticipants(totalVotes, participants, participantStakes, participantGuardians)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, rewardOf(g).String(), getCumulativeParticipationRewardInWei(g[:]), "guardian own reward has no commission")
+		totalCommission := big.NewInt(0)
+		for _, d := range [][20]byte{d1, d2, d3} {
+			commission := new(big.Int).Div(rewardOf(d), big.NewInt(10))
+			totalCommission = _addWei(totalCommission, commission)
+			require.Equal(t, new(big.Int).Sub(rewardOf(d), commission).String(), getCumulativeParticipationRewardInWei(d[:]))
+			require.Equal(t, commission.String(), getParticipantCommissionPaidInWeiByIndex(d[:], index))
+		}
+		require.Equal(t, totalCommission.String(), getCumulativeGuardianCommissionRewardInWei(g[:]))
+		require.Equal(t, totalCommission.String(), getGuardianCommissionRewardInWeiByIndex(g[:], index))
+		require.Equal(t, "0", getGuardianCommissionRewardInWeiByIndex(g[:], index+1))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_GuardianCommissionNotYetEffective(t *testing.T) {
+	g, d1 := [20]byte{0xa0}, [20]byte{0xb1}
+	participants := [][20]byte{g, d1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g: "6824191759788450055", d1: "13648383519576900110"})
+		_setGuardianCommission(g[:], 1000, _getProcessCurrentElectionIndex()+1)
+		participantStakes := weiStakes(map[[20]byte]uint64{g: 10000, d1: 20000})
+
+		// call
+		_processRewardsParticipants(_toWei(30000), participants, participantStakes, map[[20]byte][20]byte{d1: g})
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, "0", getCumulativeGuardianCommissionRewardInWei(g[:]))
+		require.Equal(t, "0", getParticipantCommissionPaidInWeiByIndex(d1[:], _getProcessCurrentElectionIndex()))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_GuardianCommissionCapped(t *testing.T) {
+	g, d1 := [20]byte{0xa0}, [20]byte{0xb1}
+	participants := [][20]byte{g, d1}
+	defer func(max uint64) { MAX_GUARDIAN_COMMISSION_BASIS_POINTS = max }(MAX_GUARDIAN_COMMISSION_BASIS_POINTS)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g: "6824191759788450055", d1: "10236287639682675083"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_COMMISSION, g, 1, "3412095879894225027")
+		MAX_GUARDIAN_COMMISSION_BASIS_POINTS = 2500
+		_setGuardianCommission(g[:], 10000, _getProcessCurrentElectionIndex())
+		participantStakes := weiStakes(map[[20]byte]uint64{g: 10000, d1: 20000})
+		totalVotes := _toWei(30000)
+		reward := new(big.Int).Div(new(big.Int).Mul(participantStakes[d1], _maxRewardForGroup(ELECTION_PARTICIPATION_MAX_REWARD, totalVotes, ELECTION_PARTICIPATION_MAX_STAKE_REWARD_PERCENT)), totalVotes)
+
+		// call
+		_processRewardsParticipants(totalVotes, participants, participantStakes, map[[20]byte][20]byte{d1: g})
+
+		// assert
+		m.VerifyMocks()
+		commission := new(big.Int).Div(reward, big.NewInt(4))
+		require.Equal(t, commission.String(), getCumulativeGuardianCommissionRewardInWei(g[:]))
+		require.Equal(t, new(big.Int).Sub(reward, commission).String(), getCumulativeParticipationRewardInWei(d1[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_RewardsRedirectedToRecipient(t *testing.T) {
+	p1, p2, recipient := [20]byte{0xa1}, [20]byte{0xa2}, [20]byte{0xc1}
+	participants := [][20]byte{p1, p2, recipient}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{p1: "6824191759788450055", p2: "13648383519576900110", recipient: "20472575279365350166"})
+		m.MockEmitEvent(RewardRecipientMirrored, p1[:], recipient[:], uint32(1), uint64(100000), uint32(1))
+		m.MockEmitEvent(RewardRedirected, REWARD_CATEGORY_PARTICIPATION, p1[:], recipient[:], uint32(1), "6824191759788450055")
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{recipient: "6824191759788450055", p2: "13648383519576900111"})
+		participantStakes := weiStakes(map[[20]byte]uint64{p1: 10000, p2: 20000, recipient: 30000})
+		totalVotes := _toWei(60000)
+
+		// before redirection
+		_processRewardsParticipants(totalVotes, participants, participantStakes, nil)
+		p1FirstReward := getCumulativeParticipationRewardInWei(p1[:])
+		recipientFirstReward := getCumulativeParticipationRewardInWei(recipient[:])
+
+		// call
+		_mirrorRewardRecipientData(p1[:], recipient[:], 100000, 1)
+		_processRewardsParticipants(totalVotes, participants, participantStakes, nil)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, p1FirstReward, getCumulativeParticipationRewardInWei(p1[:]), "no new rewards accumulate to the stakeholder")
+		require.Equal(t, p1FirstReward, getRedirectedRewardInWei(REWARD_CATEGORY_PARTICIPATION, p1[:]), "same stake earns the same reward")
+		require.Equal(t, p1FirstReward, getReceivedRedirectedRewardInWei(REWARD_CATEGORY_PARTICIPATION, recipient[:]))
+		require.Equal(t, sumWei(t, p1FirstReward, p1FirstReward), getOriginalRewardInWei(REWARD_CATEGORY_PARTICIPATION, p1[:]))
+		require.Equal(t, sumWei(t, recipientFirstReward, recipientFirstReward), getOriginalRewardInWei(REWARD_CATEGORY_PARTICIPATION, recipient[:]), "received rewards are not counted as earned")
+		require.Equal(t, sumWei(t, recipientFirstReward, recipientFirstReward, p1FirstReward), getCumulativeParticipationRewardInWei(recipient[:]))
+		require.Equal(t, "0", getRedirectedRewardInWei(REWARD_CATEGORY_PARTICIPATION, p2[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorRewardsRedirectedToRecipient(t *testing.T) {
+	v1, recipient := [20]byte{0xa1}, [20]byte{0xc1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		m.MockEmitEvent(RewardRecipientMirrored, v1[:], recipient[:], uint32(1), uint64(100000), uint32(1))
+		m.MockEmitEvent(RewardRedirected, REWARD_CATEGORY_VALIDATOR, v1[:], recipient[:], uint32(1), "8530239699735562569308")
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_VALIDATOR, recipient, 1, "8530239699735562569308")
+		_setValidators([][20]byte{v1})
+		_setValidatorStake(v1[:], 0)
+		_mirrorRewardRecipientData(v1[:], recipient[:], 100000, 1)
+
+		// call
+		_rewardValidatorsForBlocks(1, [][20]byte{v1}, ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, "0", getCumulativeValidatorRewardInWei(v1[:]))
+		reward := getCumulativeValidatorRewardInWei(recipient[:])
+		require.NotEqual(t, "0", reward)
+		require.Equal(t, reward, getOriginalRewardInWei(REWARD_CATEGORY_VALIDATOR, v1[:]))
+		require.Equal(t, "0", getOriginalRewardInWei(REWARD_CATEGORY_VALIDATOR, recipient[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorsRewardedByBlocksInCommittee(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xa1}, [20]byte{0xb1}, [20]byte{0xc1}
+	fullTerm := ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_VALIDATOR, 2, map[[20]byte]string{v1: expectedValidatorIntroductionReward(fullTerm), v2: expectedValidatorIntroductionReward(fullTerm)})
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_VALIDATOR, 3, map[[20]byte]string{v2: expectedValidatorIntroductionReward(fullTerm / 2), v3: expectedValidatorIntroductionReward(fullTerm / 2)})
+		_setValidators([][20]byte{v1, v2, v3})
+		_setValidatorStake(v1[:], 0)
+		_setValidatorStake(v2[:], 0)
+		_setValidatorStake(v3[:], 0)
+
+		// call
+		electCommitteeAtBlockHeight(m, [][20]byte{v1, v2}, 1000)                      // first committee, nothing to reward yet
+		electCommitteeAtBlockHeight(m, [][20]byte{v2, v3}, 1000+int(fullTerm))        // v1 and v2 sat a full term
+		electCommitteeAtBlockHeight(m, [][20]byte{v3}, 1000+int(fullTerm+fullTerm/2)) // v2 and v3 sat half a term
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, fullTerm, getValidatorsRewardedBlocksByIndex(1))
+		require.EqualValues(t, fullTerm/2, getValidatorsRewardedBlocksByIndex(2))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm), getValidatorRewardInWeiByIndex(v1[:], 1))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm), getValidatorRewardInWeiByIndex(v2[:], 1))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm/2), getValidatorRewardInWeiByIndex(v3[:], 2))
+		require.Equal(t, "0", getValidatorRewardInWeiByIndex(v3[:], 1))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm), getCumulativeValidatorRewardInWei(v1[:]))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm+fullTerm/2), getCumulativeValidatorRewardInWei(v2[:]))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm/2), getCumulativeValidatorRewardInWei(v3[:]))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorsRewardedForOverdueTerm(t *testing.T) {
+	v1 := [20]byte{0xa1}
+	fullTerm := ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_VALIDATOR, v1, 2, expectedValidatorIntroductionReward(fullTerm))
+		_setValidators([][20]byte{v1})
+		_setValidatorStake(v1[:], 0)
+
+		// call
+		electCommitteeAtBlockHeight(m, [][20]byte{v1}, 1000)
+		electCommitteeAtBlockHeight(m, [][20]byte{v1}, 1000+3*int(fullTerm))
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, fullTerm, getValidatorsRewardedBlocksByIndex(1))
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm), getCumulativeValidatorRewardInWei(v1[:]), "an overdue election pays at most a full term")
+	})
+}
+
+func TestOrbsVotingContract_processRewards_LegacyCommitteeNotRewardedTwice(t *testing.T) {
+	v1 := [20]byte{0xa1}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockElectionCompletedEvent(m, 1, 0, v1)
+		_setValidators([][20]byte{v1})
+		_setValidatorStake(v1[:], 0)
+		m.MockEnvBlockHeight(1000)
+		_setElectedValidators([][20]byte{v1}, 0, 0) // elected and rewarded in advance before this version
+
+		// call
+		electCommitteeAtBlockHeight(m, [][20]byte{v1}, 1000+int(ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS))
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, "0", getCumulativeValidatorRewardInWei(v1[:]))
+		require.True(t, _isElectionValidatorsRewardedInArrears(2))
+	})
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorsElectedByFallbackPolicy(t *testing.T) {
+	v1, v2 := [20]byte{0xa1}, [20]byte{0xb1}
+	fullTerm := ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+	defer func(policy string) { VALIDATOR_FALLBACK_REWARD_POLICY = policy }(VALIDATOR_FALLBACK_REWARD_POLICY)
+	tests := []struct {
+		policy   string
+		rewarded [][20]byte
+	}{
+		{VALIDATOR_FALLBACK_REWARD_POLICY_FULL, [][20]byte{v1, v2}},
+		{VALIDATOR_FALLBACK_REWARD_POLICY_EXCLUDE_VOTED_OUT, [][20]byte{v1}},
+		{VALIDATOR_FALLBACK_REWARD_POLICY_NONE, nil},
+	}
+	for i := range tests {
+		cTest := tests[i]
+		t.Run(cTest.policy, func(t *testing.T) {
+			InServiceScope(nil, nil, func(m Mockery) {
+				ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+				_init()
+				for _, validator := range cTest.rewarded {
+					mockRewardAssignedEvent(m, REWARD_CATEGORY_VALIDATOR, validator, 2, expectedValidatorIntroductionReward(fullTerm))
+				}
+				VALIDATOR_FALLBACK_REWARD_POLICY = cTest.policy
+				_setValidators([][20]byte{v1, v2})
+				_setValidatorStake(v1[:], 0)
+				_setValidatorStake(v2[:], 0)
+				_setElectionElectedByFallbackAtIndex(1, [][20]byte{v2})
+
+				// call
+				electCommitteeAtBlockHeight(m, [][20]byte{v1, v2}, 1000)
+				electCommitteeAtBlockHeight(m, [][20]byte{v1, v2}, 1000+int(fullTerm))
+
+				// assert
+				m.VerifyMocks()
+				isRewarded := _addressSet(cTest.rewarded)
+				for _, validator := range [][20]byte{v1, v2} {
+					expected := "0"
+					if isRewarded[validator] {
+						expected = expectedValidatorIntroductionReward(fullTerm)
+					}
+					require.Equal(t, expected, getCumulativeValidatorRewardInWei(validator[:]), "validator %x", validator)
+				}
+			})
+		})
+	}
+}
+
+func TestOrbsVotingContract_processRewards_ValidatorsStakeRewardAfterLeaving(t *testing.T) {
+	v1, v2 := [20]byte{0xa1}, [20]byte{0xb1}
+	fullTerm := ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		_init()
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_VALIDATOR, 2, map[[20]byte]string{v1: "8871449287724985072080", v2: expectedValidatorIntroductionReward(fullTerm)})
+		_recordStakeAtElection(v1, _toWei(1000000), big.NewInt(0))
+		_recordStakeAtElection(v2, big.NewInt(0), big.NewInt(0))
+
+		// call
+		electCommitteeAtBlockHeight(m, [][20]byte{v1, v2}, 1000)
+		_setValidators([][20]byte{v2}) // v1 left the validator set before the committee is rewarded
+		electCommitteeAtBlockHeight(m, [][20]byte{v2}, 1000+int(fullTerm))
+
+		// assert
+		m.VerifyMocks()
+		v1Reward, _ := new(big.Int).SetString(getValidatorRewardInWeiByIndex(v1[:], 1), 10)
+		v2Reward, _ := new(big.Int).SetString(getValidatorRewardInWeiByIndex(v2[:], 1), 10)
+		require.Equal(t, expectedValidatorIntroductionReward(fullTerm), v2Reward.String())
+		require.True(t, v1Reward.Cmp(v2Reward) > 0, "stake reward is on the stake recorded at election")
+	})
+}
+
+func electCommitteeAtBlockHeight(m Mockery, committee [][20]byte, blockHeight int) {
+	m.MockEnvBlockHeight(blockHeight)
+	_processRewardsValidators()
+	mockElectionCompletedEvent(m, getNumberOfElections()+1, 0, committee...)
+	_setElectedValidators(committee, 0, 0)
+}
+
+// introduction reward of a validator without stake that sat the given blocks, undivided by carried remainders
+func expectedValidatorIntroductionReward(blocksInCommittee uint64) string {
+	annualIntroduction := new(big.Int).Mul(_toWei(ELECTION_VALIDATOR_INTRODUCTION_REWARD), big.NewInt(100))
+	proRated := new(big.Int).Mul(annualIntroduction, new(big.Int).SetUint64(blocksInCommittee))
+	divisor := new(big.Int).Mul(new(big.Int).SetUint64(_annualToElectionFactor()), new(big.Int).SetUint64(ELECTION_PERIOD_LENGTH_IN_ORBS_BLOCKS))
+	return new(big.Int).Div(proRated, divisor).String()
+}
+
+func sumWei(t *testing.T, values ...string) string {
+	sum := big.NewInt(0)
+	for _, value := range values {
+		wei, ok := new(big.Int).SetString(value, 10)
+		require.True(t, ok, "not a number %s", value)
+		sum = _addWei(sum, wei)
+	}
+	return sum.String()
 }
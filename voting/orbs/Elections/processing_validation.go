@@ -0,0 +1,77 @@
+// Copyright 2019 the orbs-ethereum-contracts authors
+// This file is part of the orbs-ethereum-contracts library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package elections_systemcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+)
+
+/***
+ * Validators validation : a validator that cannot be part of a usable committee is excluded from the election,
+ * the reason is kept per election. validators sharing an orbs address are all excluded as none can be trusted with it.
+ */
+const VALIDATOR_EXCLUSION_NOT_REGISTERED = "NotRegistered"
+const VALIDATOR_EXCLUSION_ZERO_ORBS_ADDRESS = "ZeroOrbsAddress"
+const VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS = "DuplicateOrbsAddress"
+
+func _validateValidatorData(validator [20]byte, isRegistered bool, orbsAddress [20]byte) {
+	index := _getProcessCurrentElectionIndex()
+	if !isRegistered {
+		_excludeValidator(index, validator, VALIDATOR_EXCLUSION_NOT_REGISTERED)
+		return
+	}
+	if orbsAddress == [20]byte{} {
+		_excludeValidator(index, validator, VALIDATOR_EXCLUSION_ZERO_ORBS_ADDRESS)
+		return
+	}
+	claimedBy := _addressSliceToArray(state.ReadBytes(_formatElectionOrbsAddressValidator(index, orbsAddress[:])))
+	if claimedBy == [20]byte{} {
+		state.WriteBytes(_formatElectionOrbsAddressValidator(index, orbsAddress[:]), validator[:])
+		return
+	}
+	if getValidatorExclusionReasonByIndex(claimedBy[:], index) == "" {
+		_excludeValidator(index, claimedBy, VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+	}
+	_excludeValidator(index, validator, VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+}
+
+func _excludeValidator(index uint32, validator [20]byte, reason string) {
+	fmt.Printf("elections %10d: validator %x excluded, %s\n", _getProcessCurrentElectionBlockNumber(), validator, reason)
+	state.WriteString(_formatElectionValidatorExclusionReason(index, validator[:]), reason)
+	events.EmitEvent(ValidatorExcluded, index, validator[:], reason)
+}
+
+func _getValidValidators() [][20]byte {
+	index := _getProcessCurrentElectionIndex()
+	validators := _getValidators()
+	valid := make([][20]byte, 0, len(validators))
+	for _, validator := range validators {
+		if getValidatorExclusionReasonByIndex(validator[:], index) == "" {
+			valid = append(valid, validator)
+		}
+	}
+	return valid
+}
+
+/***
+ * Validators validation - data struct
+ */
+func _formatElectionValidatorExclusionReason(index uint32, validator []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_Validator_%s_ExclusionReason", index, hex.EncodeToString(validator)))
+}
+
+func _formatElectionOrbsAddressValidator(index uint32, orbsAddress []byte) []byte {
+	return []byte(fmt.Sprintf("Election_%d_OrbsAddress_%s_Validator", index, hex.EncodeToString(orbsAddress)))
+}
+
+// empty when the validator took part in the election
+func getValidatorExclusionReasonByIndex(validator []byte, index uint32) string {
+	return state.ReadString(_formatElectionValidatorExclusionReason(index, validator))
+}
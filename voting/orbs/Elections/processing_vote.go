@@ -10,7 +10,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/ethereum"
-	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/safemath/safeuint64"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/events"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	"math/big"
 )
@@ -21,7 +21,8 @@ import (
 func processVoting() uint64 {
 	_initCurrentElection()
 	if isProcessingPeriod() == 0 {
-		panic(fmt.Sprintf("mirror period of election %d did not end. cannot start processing", getNumberOfElections()+1))
+		panic(_newError(ERROR_MIRROR_PERIOD_NOT_ENDED, fmt.Sprintf("mirror period of election %d did not end. cannot start processing", _getProcessCurrentElectionIndex()),
+			errorField{"electionIndex", _getProcessCurrentElectionIndex()}))
 	}
 
 	_calculateProcessCurrentElectionValues()
@@ -62,9 +63,9 @@ func _processVotingStateMachine() [][20]byte {
 		}
 		return nil
 	} else if processState == VOTING_PROCESS_STATE_CALCULATIONS {
-		candidateVotes, totalVotes, participants, participantStakes, guardiansAccumulatedStake := _calculateVotes()
+		candidateVotes, totalVotes, participants, participantStakes, participantGuardians, guardiansAccumulatedStake := _calculateVotes()
 		elected := _processValidatorsSelection(candidateVotes, totalVotes)
-		_processRewards(totalVotes, elected, participants, participantStakes, guardiansAccumulatedStake)
+		_processRewards(totalVotes, participants, participantStakes, participantGuardians, guardiansAccumulatedStake)
 		_setVotingProcessState("") // clear state
 		return elected
 	}
@@ -75,6 +76,7 @@ func _nextProcessVotingState(stage string) {
 	_setVotingProcessItem(0)
 	_setVotingProcessState(stage)
 	fmt.Printf("elections %10d: moving to state %s\n", _getProcessCurrentElectionBlockNumber(), stage)
+	events.EmitEvent(ProcessingStageAdvanced, _getProcessCurrentElectionIndex(), _getProcessCurrentElectionBlockNumber(), stage)
 }
 
 func _readValidatorsFromEthereumToState() {
@@ -114,14 +116,20 @@ func _collectNextValidatorDataFromEthereum() (isDone bool) {
 func _collectOneValidatorDataFromEthereum(i int) {
 	validator := _getValidatorEthereumAddressAtIndex(i)
 
-	var orbsAddress [20]byte
-	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getValidatorsRegistryEthereumContractAddress(), getValidatorsRegistryAbi(), "getOrbsAddress", &orbsAddress, validator)
+	var isRegistered bool
+	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getValidatorsRegistryEthereumContractAddress(), getValidatorsRegistryAbi(), "isValidator", &isRegistered, validator)
+	data := &ValidatorData{}
+	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getValidatorsRegistryEthereumContractAddress(), getValidatorsRegistryAbi(), "getValidatorData", data, validator)
+	orbsAddress := data.OrbsAddress
 	stake := _getStakeAtElection(validator)
 	lockedStake := _getLockedStakeAtElection(validator)
 
-	_setValidatorStake(validator[:], safeuint64.Add(stake, lockedStake))
+	_recordStakeAtElection(validator, stake, lockedStake)
+	_setValidatorStakeInWei(validator[:], _addWei(stake, lockedStake))
 	_setValidatorOrbsAddress(validator[:], orbsAddress[:])
-	fmt.Printf("elections %10d: from ethereum validator %x, unlocked-stake %d, locked-stake %d, orbsAddress %x\n", _getProcessCurrentElectionBlockNumber(), validator, stake, lockedStake, orbsAddress)
+	_setValidatorDataAtIndex(_getProcessCurrentElectionIndex(), validator[:], data)
+	fmt.Printf("elections %10d: from ethereum validator %x, unlocked-stake %d, locked-stake %d, orbsAddress %x, ip %v\n", _getProcessCurrentElectionBlockNumber(), validator, stake, lockedStake, orbsAddress, data.IpAddress)
+	_validateValidatorData(validator, isRegistered, orbsAddress)
 }
 
 func _collectNextGuardiansDataFromEthereum() bool {
@@ -139,16 +147,16 @@ type Vote struct {
 
 func _collectOneGuardianDataFromEthereum(i int) {
 	guardian := _getGuardianAtIndex(i)
-	stake := uint64(0)
-	lockedStake := uint64(0)
+	votingWeight := big.NewInt(0)
 	candidates := [][20]byte{{}}
 
 	out := Vote{}
 	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getVotingEthereumContractAddress(), getVotingAbi(), "getCurrentVoteBytes20", &out, guardian)
 	voteBlockNumber := out.BlockNumber.Uint64()
 	if voteBlockNumber != 0 && voteBlockNumber >= _getProcessCurrentElectionEarliestValidVoteBlockNumber() {
-		stake = _getStakeAtElection(guardian)
-		lockedStake = _getLockedStakeAtElection(guardian)
+		stake := _getStakeAtElection(guardian)
+		lockedStake := _getLockedStakeAtElection(guardian)
+		votingWeight = _recordStakeAtElection(guardian, stake, lockedStake)
 		candidates = out.ValidatorsBytes20
 		voteBlockNumber = out.BlockNumber.Uint64()
 		fmt.Printf("elections %10d: from ethereum guardian %x voted at %d, unlocked-stake %d, locked-stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, voteBlockNumber, stake, lockedStake)
@@ -157,7 +165,7 @@ func _collectOneGuardianDataFromEthereum(i int) {
 		fmt.Printf("elections %10d: from ethereum guardian %x vote is too old, will ignore\n", _getProcessCurrentElectionBlockNumber(), guardian)
 	}
 
-	_setGuardianStake(guardian[:], safeuint64.Add(stake, lockedStake))
+	_setGuardianStakeInWei(guardian[:], votingWeight)
 	_setGuardianVoteBlockNumber(guardian[:], voteBlockNumber)
 	_setCandidates(guardian[:], candidates)
 }
@@ -172,47 +180,50 @@ func _collectNextDelegatorStakeFromEthereum() bool {
 
 func _collectOneDelegatorStakeFromEthereum(i int) {
 	delegator := _getDelegatorAtIndex(i)
-	stake := uint64(0)
-	lockedStake := uint64(0)
+	stake := big.NewInt(0)
+	lockedStake := big.NewInt(0)
+	votingWeight := big.NewInt(0)
 	if !_isGuardian(delegator) {
 		stake = _getStakeAtElection(delegator)
 		lockedStake = _getLockedStakeAtElection(delegator)
+		votingWeight = _recordStakeAtElection(delegator, stake, lockedStake)
 	} else {
 		fmt.Printf("elections %10d: from ethereum delegator %x is actually a guardian, will ignore\n", _getProcessCurrentElectionBlockNumber(), delegator)
 	}
-	state.WriteUint64(_formatDelegatorStakeKey(delegator[:]), safeuint64.Add(stake, lockedStake))
+	_writeWeiAndLegacy(_formatDelegatorStakeInWeiKey(delegator[:]), _formatDelegatorStakeKey(delegator[:]), votingWeight)
 	fmt.Printf("elections %10d: from ethereum delegator %x , unlocked-stake %d, locked stake %d\n", _getProcessCurrentElectionBlockNumber(), delegator, stake, lockedStake)
 }
 
-func _getStakeAtElection(ethAddr [20]byte) uint64 {
+func _getStakeAtElection(ethAddr [20]byte) *big.Int {
 	stake := new(*big.Int)
 	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getTokenEthereumContractAddress(), getTokenAbi(), "balanceOf", stake, ethAddr)
-	return ((*stake).Div(*stake, ETHEREUM_STAKE_FACTOR)).Uint64()
+	return *stake
 }
 
-func _getLockedStakeAtElection(ethAddr [20]byte) uint64 {
+func _getLockedStakeAtElection(ethAddr [20]byte) *big.Int {
 	lockedStake := new(*big.Int)
 	ethereum.CallMethodAtBlock(_getProcessCurrentElectionBlockNumber(), getStakingEthereumContractAddress(), getStakingAbi(), "getStakeBalanceOf", lockedStake, ethAddr)
-	return ((*lockedStake).Div(*lockedStake, ETHEREUM_STAKE_FACTOR)).Uint64()
+	return *lockedStake
 }
 
-func _calculateVotes() (candidateVotes map[[20]byte]uint64, totalVotes uint64, participants [][20]byte, participantStakes map[[20]byte]uint64, guardianAccumulatedStakes map[[20]byte]uint64) {
+func _calculateVotes() (candidateVotes map[[20]byte]*big.Int, totalVotes *big.Int, participants [][20]byte, participantStakes map[[20]byte]*big.Int, participantGuardians map[[20]byte][20]byte, guardianAccumulatedStakes map[[20]byte]*big.Int) {
 	guardians := _getGuardians()
 	guardianStakes := _collectGuardiansStake(guardians)
 	delegators, delegatorStakes := _collectDelegatorsStake(guardians)
 	guardianToDelegators := _findGuardianDelegators(delegators)
-	candidateVotes, totalVotes, participants, participantStakes, guardianAccumulatedStakes = _guardiansCastVotes(guardianStakes, guardianToDelegators, delegatorStakes)
+	candidateVotes, totalVotes, participants, participantStakes, participantGuardians, guardianAccumulatedStakes = _guardiansCastVotes(guardianStakes, guardianToDelegators, delegatorStakes)
+	participantStakes = _toParticipantRewardWeights(participants, participantStakes)
 	return
 }
 
-func _collectGuardiansStake(guardians map[[20]byte]bool) (guardianStakes map[[20]byte]uint64) {
-	guardianStakes = make(map[[20]byte]uint64)
+func _collectGuardiansStake(guardians map[[20]byte]bool) (guardianStakes map[[20]byte]*big.Int) {
+	guardianStakes = make(map[[20]byte]*big.Int)
 	numOfGuardians := _getNumberOfGuardians()
 	for i := 0; i < numOfGuardians; i++ {
 		guardian := _getGuardianAtIndex(i)
 		voteBlockNumber := _getGuardianVoteBlockNumber(guardian[:])
 		if voteBlockNumber != 0 {
-			stake := getGuardianStake(guardian[:])
+			stake := _getGuardianStakeInWei(guardian[:])
 			guardianStakes[guardian] = stake
 			fmt.Printf("elections %10d: guardian %x, stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, stake)
 		} else {
@@ -222,15 +233,15 @@ func _collectGuardiansStake(guardians map[[20]byte]bool) (guardianStakes map[[20
 	return
 }
 
-func _collectDelegatorsStake(guardians map[[20]byte]bool) (delegators [][20]byte, delegatorStakes map[[20]byte]uint64) {
-	delegatorStakes = make(map[[20]byte]uint64)
+func _collectDelegatorsStake(guardians map[[20]byte]bool) (delegators [][20]byte, delegatorStakes map[[20]byte]*big.Int) {
+	delegatorStakes = make(map[[20]byte]*big.Int)
 	delegators = make([][20]byte, 0, _getNumberOfDelegators())
 	numOfDelegators := _getNumberOfDelegators()
 	for i := 0; i < numOfDelegators; i++ {
 		delegator := _getDelegatorAtIndex(i)
 		if !guardians[delegator] {
 			if _, ok := delegatorStakes[delegator]; !ok {
-				stake := state.ReadUint64(_formatDelegatorStakeKey(delegator[:]))
+				stake := _readWeiOrLegacy(_formatDelegatorStakeInWeiKey(delegator[:]), _formatDelegatorStakeKey(delegator[:]))
 				delegatorStakes[delegator] = stake
 				delegators = append(delegators, delegator)
 				fmt.Printf("elections %10d: delegator %x, stake %d\n", _getProcessCurrentElectionBlockNumber(), delegator, stake)
@@ -260,73 +271,152 @@ func _findGuardianDelegators(delegators [][20]byte) (guardianToDelegators map[[2
 	return
 }
 
-func _guardiansCastVotes(guardianStakes map[[20]byte]uint64, guardianDelegators map[[20]byte][][20]byte, delegatorStakes map[[20]byte]uint64) (candidateVotes map[[20]byte]uint64, totalVotes uint64, participants [][20]byte, participantStakes map[[20]byte]uint64, guardainsAccumulatedStakes map[[20]byte]uint64) {
-	totalVotes = uint64(0)
-	candidateVotes = make(map[[20]byte]uint64)
+func _guardiansCastVotes(guardianStakes map[[20]byte]*big.Int, guardianDelegators map[[20]byte][][20]byte, delegatorStakes map[[20]byte]*big.Int) (candidateVotes map[[20]byte]*big.Int, totalVotes *big.Int, participants [][20]byte, participantStakes map[[20]byte]*big.Int, participantGuardians map[[20]byte][20]byte, guardainsAccumulatedStakes map[[20]byte]*big.Int) {
+	totalVotes = big.NewInt(0)
+	candidateVotes = make(map[[20]byte]*big.Int)
 	participants = make([][20]byte, 0, len(guardianStakes)+len(delegatorStakes))
-	participantStakes = make(map[[20]byte]uint64, len(guardianStakes)+len(delegatorStakes))
-	guardainsAccumulatedStakes = make(map[[20]byte]uint64, len(guardianStakes))
+	participantStakes = make(map[[20]byte]*big.Int, len(guardianStakes)+len(delegatorStakes))
+	participantGuardians = make(map[[20]byte][20]byte, len(delegatorStakes))
+	guardainsAccumulatedStakes = make(map[[20]byte]*big.Int, len(guardianStakes))
 	numOfGuardians := _getNumberOfGuardians()
 	for i := 0; i < numOfGuardians; i++ { // must not range over map as we set to state and order must be fixed
 		guardian := _getGuardianAtIndex(i)
-		if guardianStake, ok := guardianStakes[guardian]; ok {
-			//	for guardian, guardianStake := range guardianStakes {
-			participantStakes[guardian] = guardianStake
-			participants = append(participants, guardian)
-			fmt.Printf("elections %10d: guardian %x, self-voting stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, guardianStake)
-			stake := safeuint64.Add(guardianStake, _calculateOneGuardianVoteRecursive(guardian, guardianDelegators, delegatorStakes, &participants, participantStakes))
-			guardainsAccumulatedStakes[guardian] = stake
-			_setGuardianVotingWeight(guardian[:], stake)
-			totalVotes = safeuint64.Add(totalVotes, stake)
-			fmt.Printf("elections %10d: guardian %x, voting stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, stake)
-
-			candidateList := _getCandidates(guardian[:])
-			for _, candidate := range candidateList {
-				fmt.Printf("elections %10d: guardian %x, voted for candidate %x\n", _getProcessCurrentElectionBlockNumber(), guardian, candidate)
-				candidateVotes[candidate] = safeuint64.Add(candidateVotes[candidate], stake)
+		guardianStake, hasVoted := guardianStakes[guardian]
+		if !hasVoted {
+			guardianStake = big.NewInt(0)
+		}
+		delegates := make([][20]byte, 0)
+		delegateStakes := make(map[[20]byte]*big.Int)
+		delegateGuardians := make(map[[20]byte][20]byte)
+		stake := _addWei(guardianStake, _calculateOneGuardianVoteRecursive(guardian, guardianDelegators, delegatorStakes, &delegates, delegateStakes, delegateGuardians))
+		if !_isGuardianEligible(guardian, hasVoted, guardianStake, stake) {
+			_setGuardianVotingWeightInWei(guardian[:], big.NewInt(0))
+			if INELIGIBLE_GUARDIAN_DELEGATORS_POLICY == INELIGIBLE_GUARDIAN_DELEGATORS_DIRECT_PARTICIPATION {
+				for _, delegate := range delegates { // participate on their own, no guardian to vote or take commission
+					fmt.Printf("elections %10d: delegator %x of ineligible guardian %x, participating directly with stake %d\n", _getProcessCurrentElectionBlockNumber(), delegate, guardian, delegateStakes[delegate])
+					participants = append(participants, delegate)
+					participantStakes[delegate] = delegateStakes[delegate]
+				}
+			}
+			continue
+		}
+		participantStakes[guardian] = guardianStake
+		participants = append(participants, guardian)
+		fmt.Printf("elections %10d: guardian %x, self-voting stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, guardianStake)
+		for _, delegate := range delegates {
+			participants = append(participants, delegate)
+			participantStakes[delegate] = delegateStakes[delegate]
+			participantGuardians[delegate] = delegateGuardians[delegate]
+		}
+		guardainsAccumulatedStakes[guardian] = stake
+		_setGuardianVotingWeightInWei(guardian[:], stake)
+		totalVotes = _addWei(totalVotes, stake)
+		fmt.Printf("elections %10d: guardian %x, voting stake %d\n", _getProcessCurrentElectionBlockNumber(), guardian, stake)
+
+		candidateList := _getCandidates(guardian[:])
+		for _, candidate := range candidateList {
+			fmt.Printf("elections %10d: guardian %x, voted for candidate %x\n", _getProcessCurrentElectionBlockNumber(), guardian, candidate)
+			if votes, ok := candidateVotes[candidate]; ok {
+				candidateVotes[candidate] = _addWei(votes, stake)
+			} else {
+				candidateVotes[candidate] = stake
 			}
 		}
 	}
 	fmt.Printf("elections %10d: total voting stake %d\n", _getProcessCurrentElectionBlockNumber(), totalVotes)
-	_setTotalStake(totalVotes)
+	_setTotalStakeInWei(totalVotes)
 	return
 }
 
 // Note : important that first call is to guardian ... otherwise not all delegators will be added to participants
-func _calculateOneGuardianVoteRecursive(currentLevelGuardian [20]byte, guardianToDelegators map[[20]byte][][20]byte, delegatorStakes map[[20]byte]uint64, participants *[][20]byte, participantStakes map[[20]byte]uint64) uint64 {
+// participantGuardians maps every delegator, however deep in the delegation chain, to the guardian of the first call
+func _calculateOneGuardianVoteRecursive(currentLevelGuardian [20]byte, guardianToDelegators map[[20]byte][][20]byte, delegatorStakes map[[20]byte]*big.Int, participants *[][20]byte, participantStakes map[[20]byte]*big.Int, participantGuardians map[[20]byte][20]byte) *big.Int {
 	guardianDelegatorList, ok := guardianToDelegators[currentLevelGuardian]
-	currentVotes := delegatorStakes[currentLevelGuardian]
+	currentVotes := big.NewInt(0)
+	if stake, hasStake := delegatorStakes[currentLevelGuardian]; hasStake {
+		currentVotes = stake
+	}
+	guardian, isDelegate := participantGuardians[currentLevelGuardian]
+	if !isDelegate {
+		guardian = currentLevelGuardian
+	}
 	if ok {
 		for _, delegate := range guardianDelegatorList {
-			participantStakes[delegate] = delegatorStakes[delegate]
+			participantStakes[delegate] = _getStakeOrZero(delegatorStakes, delegate)
+			participantGuardians[delegate] = guardian
 			*participants = append(*participants, delegate)
-			currentVotes = safeuint64.Add(currentVotes, _calculateOneGuardianVoteRecursive(delegate, guardianToDelegators, delegatorStakes, participants, participantStakes))
+			currentVotes = _addWei(currentVotes, _calculateOneGuardianVoteRecursive(delegate, guardianToDelegators, delegatorStakes, participants, participantStakes, participantGuardians))
 		}
 	}
 	return currentVotes
 }
 
-func _processValidatorsSelection(candidateVotes map[[20]byte]uint64, totalVotes uint64) [][20]byte {
-	validators := _getValidators()
-	voteOutThreshhold := safeuint64.Div(safeuint64.Mul(totalVotes, VOTE_OUT_WEIGHT_PERCENT), 100)
+func _getStakeOrZero(stakes map[[20]byte]*big.Int, address [20]byte) *big.Int {
+	if stake, ok := stakes[address]; ok {
+		return stake
+	}
+	return big.NewInt(0)
+}
+
+// votes are kept for every validator, also for those excluded from the selection
+func _setValidatorsVoteInWei(candidateVotes map[[20]byte]*big.Int) {
+	for _, validator := range _getValidators() {
+		_setValidatorVoteInWei(validator[:], _getStakeOrZero(candidateVotes, validator))
+	}
+}
+
+func _processValidatorsSelection(candidateVotes map[[20]byte]*big.Int, totalVotes *big.Int) [][20]byte {
+	_setValidatorsVoteInWei(candidateVotes)
+	validators := _getValidValidators()
+	voteOutThreshhold := new(big.Int).Div(new(big.Int).Mul(totalVotes, new(big.Int).SetUint64(VOTE_OUT_WEIGHT_PERCENT)), big.NewInt(100))
 	fmt.Printf("elections %10d: %d is vote out threshhold\n", _getProcessCurrentElectionBlockNumber(), voteOutThreshhold)
 
 	winners := make([][20]byte, 0, len(validators))
+	votedOut := make([][20]byte, 0, len(validators))
+	voteOutWeights := make(map[[20]byte]*big.Int, len(validators))
 	for _, validator := range validators {
 		voted, ok := candidateVotes[validator]
-		_setValidatorVote(validator[:], voted)
-		if !ok || voted < voteOutThreshhold {
+		if !ok {
+			voted = big.NewInt(0)
+		}
+		voteOutWeights[validator] = voted
+		if !ok || voted.Cmp(voteOutThreshhold) < 0 {
 			fmt.Printf("elections %10d: elected %x (got %d vote outs)\n", _getProcessCurrentElectionBlockNumber(), validator, voted)
 			winners = append(winners, validator)
 		} else {
 			fmt.Printf("elections %10d: candidate %x voted out by %d votes\n", _getProcessCurrentElectionBlockNumber(), validator, voted)
+			votedOut = append(votedOut, validator)
 		}
 	}
+	winners, votedOut = _addDeferredVoteOuts(winners, votedOut, voteOutWeights)
+	winners, banned := _removeBannedValidators(winners, totalVotes)
+	winners, banned = _reinstateBannedValidators(winners, banned)
 	if len(winners) < MIN_ELECTED_VALIDATORS {
 		fmt.Printf("elections %10d: not enought validators left after vote using all validators %x\n", _getProcessCurrentElectionBlockNumber(), validators)
-		return validators
+		_setElectionBannedValidatorsAtIndex(_getProcessCurrentElectionIndex(), nil)
+		// the fallback keeps every validator, so nobody leaves and the deferred vote outs are cleared, only the joins are limited
+		elected, _ := _limitCommitteeChurn(validators, validators, nil, voteOutWeights)
+		isWinner := _addressSet(winners)
+		notWinners := make([][20]byte, 0, len(elected))
+		for _, validator := range elected {
+			if !isWinner[validator] {
+				notWinners = append(notWinners, validator)
+			}
+		}
+		_setElectionElectedByFallbackAtIndex(_getProcessCurrentElectionIndex(), notWinners)
+		return elected
 	} else {
-		return winners
+		_setElectionBannedValidatorsAtIndex(_getProcessCurrentElectionIndex(), banned)
+		elected, leaving := _limitCommitteeChurn(validators, winners, append(votedOut, banned...), voteOutWeights)
+		_banVotedOutValidators(leaving, banned)
+		isBanned := _addressSet(banned)
+		for _, validator := range leaving {
+			if !isBanned[validator] {
+				events.EmitEvent(ValidatorVotedOut, _getProcessCurrentElectionIndex(), validator[:], _toOrbs(voteOutWeights[validator]), _toOrbs(voteOutThreshhold),
+					voteOutWeights[validator].String(), voteOutThreshhold.String())
+			}
+		}
+		return elected
 	}
 }
 
@@ -339,7 +429,19 @@ func getTotalStake() uint64 {
 }
 
 func _setTotalStake(weight uint64) {
-	state.WriteUint64(_formatTotalVotingStakeKey(), weight)
+	_setTotalStakeInWei(_toWei(weight))
+}
+
+func _formatTotalVotingStakeInWeiKey() []byte {
+	return []byte("Total_Voting_Weight_In_Wei")
+}
+
+func getTotalStakeInWei() string {
+	return _readWeiOrLegacy(_formatTotalVotingStakeInWeiKey(), _formatTotalVotingStakeKey()).String()
+}
+
+func _setTotalStakeInWei(weight *big.Int) {
+	_writeWeiAndLegacy(_formatTotalVotingStakeInWeiKey(), _formatTotalVotingStakeKey(), weight)
 }
 
 const VOTING_PROCESS_STATE_VALIDATORS = "validators"
@@ -11,6 +11,7 @@ import (
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 )
 
@@ -31,7 +32,7 @@ func TestOrbsVotingContract_getStakeFromEthereum(t *testing.T) {
 
 		// assert
 		m.VerifyMocks()
-		require.EqualValues(t, stakeSetup, stake)
+		require.EqualValues(t, stakeSetup, _toOrbs(stake))
 	})
 }
 
@@ -52,7 +53,7 @@ func TestOrbsVotingContract_getLockedStakeFromEthereum(t *testing.T) {
 
 		// assert
 		m.VerifyMocks()
-		require.EqualValues(t, stakeSetup, stake)
+		require.EqualValues(t, stakeSetup, _toOrbs(stake))
 	})
 }
 
@@ -71,13 +72,14 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakes(t *tes
 	g4.vote(aRecentVoteBlock, v2, v5)
 	g5.vote(anAncientVoteBlock, v4)
 
+	g3Delegators := make([]*delegator, 0, 10)
 	for i := 0; i < 10; i++ {
-		h.addDelegator(500, g3.address)
+		g3Delegators = append(g3Delegators, h.addDelegator(500, g3.address))
 	}
 
 	d1 := h.addDelegator(500, g4.address)
 	d2 := h.addDelegator(500, d1.address)
-	h.addDelegator(500, d2.address)
+	d3 := h.addDelegator(500, d2.address)
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
@@ -85,6 +87,14 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakes(t *tes
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
 		h.setupEthereumStateBeforeProcess(m)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockValidatorVotedOutEvent(m, 1, v2.address, big.NewInt(82000000), big.NewInt(57400000))
+		participationRewards := map[[20]byte]string{g1.address: "682", g2.address: "1364", g3.address: "2729", g4.address: "6824", d1.address: "3412", d2.address: "3412", d3.address: "3412"}
+		for _, d := range g3Delegators {
+			participationRewards[d.address] = "3412"
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, participationRewards)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{g1.address: "853", g2.address: "1706", g3.address: "46062", g4.address: "21325"})
 
 		// call
 		expectedNumOfStateTransitions := len(h.guardians) + len(h.delegators) + len(h.validators) + 2
@@ -111,13 +121,14 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakesWithLoc
 	g3.vote(aRecentVoteBlock, v2, v3)
 	g4.vote(aRecentVoteBlock, v2, v5)
 
+	g3Delegators := make([]*delegator, 0, 10)
 	for i := 0; i < 10; i++ {
-		h.addDelegator(500, g3.address)
+		g3Delegators = append(g3Delegators, h.addDelegator(500, g3.address))
 	}
 
 	d1 := h.addDelegator(500, g4.address).withLockedStake(100000)
 	d2 := h.addDelegator(500, d1.address)
-	h.addDelegator(500, d2.address)
+	d3 := h.addDelegator(500, d2.address)
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
@@ -125,6 +136,15 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakesWithLoc
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
 		h.setupEthereumStateBeforeProcess(m)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockValidatorVotedOutEvent(m, 1, v2.address, big.NewInt(1082000000), big.NewInt(757400000))
+		mockValidatorVotedOutEvent(m, 1, v5.address, big.NewInt(1025000000), big.NewInt(757400000))
+		participationRewards := map[[20]byte]string{g1.address: "682", g2.address: "1364", g3.address: "2729", g4.address: "6824", d1.address: "685830", d2.address: "3412", d3.address: "3412"}
+		for _, d := range g3Delegators {
+			participationRewards[d.address] = "3412"
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, participationRewards)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{g1.address: "853", g2.address: "1706", g3.address: "46063", g4.address: "874348"})
 
 		// call
 		expectedNumOfStateTransitions := len(h.guardians) + len(h.delegators) + len(h.validators) + 2
@@ -153,13 +173,14 @@ func TestOrbsVotingContract_processVote_processVoteMachineWithTimeBased_Calulate
 	g4.vote(aRecentVoteBlock, v2, v5)
 	g5.vote(anAncientVoteBlock, v4)
 
+	g3Delegators := make([]*delegator, 0, 10)
 	for i := 0; i < 10; i++ {
-		h.addDelegator(500, g3.address)
+		g3Delegators = append(g3Delegators, h.addDelegator(500, g3.address))
 	}
 
 	d1 := h.addDelegator(500, g4.address)
 	d2 := h.addDelegator(500, d1.address)
-	h.addDelegator(500, d2.address)
+	d3 := h.addDelegator(500, d2.address)
 
 	InServiceScope(nil, nil, func(m Mockery) {
 		_init()
@@ -167,6 +188,14 @@ func TestOrbsVotingContract_processVote_processVoteMachineWithTimeBased_Calulate
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
 		h.setupEthereumStateBeforeProcess(m)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockValidatorVotedOutEvent(m, 1, v2.address, big.NewInt(82000000), big.NewInt(57400000))
+		participationRewards := map[[20]byte]string{g1.address: "682", g2.address: "1364", g3.address: "2729", g4.address: "6824", d1.address: "3412", d2.address: "3412", d3.address: "3412"}
+		for _, d := range g3Delegators {
+			participationRewards[d.address] = "3412"
+		}
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, participationRewards)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{g1.address: "853", g2.address: "1706", g3.address: "46062", g4.address: "21325"})
 
 		// call
 		expectedNumOfStateTransitions := len(h.guardians) + len(h.delegators) + len(h.validators) + 2
@@ -192,7 +221,7 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakes_Guardi
 	g2.vote(aRecentVoteBlock, v1)
 
 	d1 := h.addDelegator(100000, g1.address)
-	h.addDelegator(100000, g1.address)
+	d2 := h.addDelegator(100000, g1.address)
 	d3 := h.addDelegator(10000, g2.address)
 	h.addDelegator(10000, g2.address)
 
@@ -202,6 +231,9 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakes_Guardi
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
 		h.setupEthereumStateBeforeProcess(m)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g1.address: "682419", d1.address: "682419", d2.address: "682419"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, g1.address, 1, "2559071")
 
 		// call
 		h.runProcessVoteMachineNtimes(0)
@@ -259,13 +291,16 @@ func TestOrbsVotingContract_processVote_processVoteMachine_CalulateStakes_Delega
 		h.setupEthereumGuardiansDataBeforeProcess(m)
 		mockStakedAndLockedInEthereum(m, h.electionBlock, realD2.address, realD2.stake, realD2.lockedStake)
 		mockStakedAndLockedInEthereum(m, h.electionBlock, realD3.address, realD3.stake, realD3.lockedStake)
+		mockProcessingStagesEvents(m, 1, h.electionBlock)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, 1, map[[20]byte]string{g1.address: "68", g2.address: "682", g3.address: "6824", g4.address: "68241917", realD2.address: "6"})
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, 1, map[[20]byte]string{g1.address: "85", g2.address: "861", g3.address: "8530", g4.address: "85302396"})
 
 		// call
 		expectedNumOfStateTransitions := len(h.guardians) + len(h.delegators) + len(h.validators) + 3
 		_, actualRuns := h.runProcessVoteMachineNtimes(expectedNumOfStateTransitions)
 
 		// assert
-		//m.VerifyMocks()
+		m.VerifyMocks()
 		require.True(t, actualRuns <= expectedNumOfStateTransitions, "did not finish in correct amount of passes")
 		require.EqualValues(t, 10, getGuardianVotingWeight(g1.address[:]))
 		require.EqualValues(t, 101, getGuardianVotingWeight(g2.address[:]))
@@ -321,9 +356,11 @@ func TestOrbsVotingContract_processVote_collectValidatorDataFromEthereum(t *test
 
 		// prepare
 		h.setupOrbsStateBeforeProcessMachine()
-		mockValidatorOrbsAddressInEthereum(m, h.electionBlock, v1.address, v1.orbsAddress)
+		mockValidatorRegisteredInEthereum(m, h.electionBlock, v1.address, true)
+		mockValidatorDataInEthereum(m, h.electionBlock, v1)
 		mockStakedAndLockedInEthereum(m, h.electionBlock, v1.address, 250, 250)
-		mockValidatorOrbsAddressInEthereum(m, h.electionBlock, v2.address, v2.orbsAddress)
+		mockValidatorRegisteredInEthereum(m, h.electionBlock, v2.address, true)
+		mockValidatorDataInEthereum(m, h.electionBlock, v2)
 		mockStakedAndLockedInEthereum(m, h.electionBlock, v2.address, 450, 450)
 		_setVotingProcessItem(0)
 
@@ -684,18 +721,23 @@ func TestOrbsVotingContract_processVote_calculateOneGuardianVoteRecursive(t *tes
 		cTest := tests[i]
 		t.Run(cTest.name, func(t *testing.T) {
 			var participants [][20]byte
-			participantStakes := make(map[[20]byte]uint64)
-			stakes := _calculateOneGuardianVoteRecursive(guardian, cTest.relationship, delegatorStakes, &participants, participantStakes)
+			participantStakes := make(map[[20]byte]*big.Int)
+			participantGuardians := make(map[[20]byte][20]byte)
+			stakes := _toOrbs(_calculateOneGuardianVoteRecursive(guardian, cTest.relationship, weiStakes(delegatorStakes), &participants, participantStakes, participantGuardians))
 			require.EqualValues(t, cTest.expect, stakes, fmt.Sprintf("%s was calculated to %d instead of %d", cTest.name, stakes, cTest.expect))
 			require.EqualValues(t, len(cTest.expectParticipantStake), len(participantStakes), "participants stake length not equal")
 			for k, v := range participantStakes {
-				require.EqualValues(t, cTest.expectParticipantStake[k], v, "bad values")
+				require.EqualValues(t, cTest.expectParticipantStake[k], _toOrbs(v), "bad values")
 			}
 			require.EqualValues(t, len(cTest.expectParticipantStake), len(participants), "participants length not equal")
 			for _, p := range participants {
 				_, ok := participantStakes[p]
 				require.True(t, ok, "missing key")
 			}
+			require.EqualValues(t, len(cTest.expectParticipantStake), len(participantGuardians), "participants guardian length not equal")
+			for p, g := range participantGuardians {
+				require.EqualValues(t, guardian, g, "delegator %x should be mapped to the top guardian also when chained", p)
+			}
 		})
 	}
 }
@@ -743,10 +785,20 @@ func TestOrbsVotingContract_processVote_guardiansCastVotes(t *testing.T) {
 		}
 		for i := range tests {
 			cTest := tests[i]
-			candidatesVotes, total, _, _, _ := _guardiansCastVotes(cTest.guardianStake, relationship, delegatorStakes)
-			require.EqualValues(t, cTest.expectedTotal, total)
+			candidatesVotes, total, _, _, participantGuardians, _ := _guardiansCastVotes(weiStakes(cTest.guardianStake), relationship, weiStakes(delegatorStakes))
+			require.EqualValues(t, cTest.expectedTotal, _toOrbs(total))
 			for validator, vote := range cTest.expect {
-				require.EqualValues(t, vote, candidatesVotes[validator])
+				require.EqualValues(t, vote, _toOrbs(candidatesVotes[validator]))
+			}
+			for guardian := range cTest.guardianStake {
+				_, isDelegator := participantGuardians[guardian]
+				require.False(t, isDelegator, "guardian should not be mapped to a guardian")
+				for _, delegator := range relationship[guardian] {
+					require.EqualValues(t, guardian, participantGuardians[delegator])
+				}
+			}
+			if _, ok := cTest.guardianStake[g2]; ok { // g2 delegation is three levels deep
+				require.EqualValues(t, g2, participantGuardians[[20]byte{0xa2, 0xb3}])
 			}
 		}
 	})
@@ -768,6 +820,7 @@ func TestOrbsVotingContract_processVote_processValidatorsSelection(t *testing.T)
 	InServiceScope(nil, nil, func(m Mockery) {
 		MIN_ELECTED_VALIDATORS = 2
 		_init()
+		mockValidatorVotedOutEvent(m, 1, v2, _toWei(701), _toWei(700))
 		_setNumberOfValidators(4)
 		_setValidatorEthereumAddressAtIndex(0, v1[:])
 		_setValidatorEthereumAddressAtIndex(1, v2[:])
@@ -776,9 +829,197 @@ func TestOrbsVotingContract_processVote_processValidatorsSelection(t *testing.T)
 
 		for i := range tests {
 			cTest := tests[i]
-			validCandidates := _processValidatorsSelection(cTest.original, cTest.maxVotes)
+			validCandidates := _processValidatorsSelection(weiStakes(cTest.original), _toWei(cTest.maxVotes))
 			require.Equal(t, len(cTest.expect), len(validCandidates))
 			require.ElementsMatch(t, cTest.expect, validCandidates)
 		}
+		m.VerifyMocks()
+	})
+}
+
+func TestOrbsVotingContract_processVote_processValidatorsSelection_EmitsVotedOut(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 2
+		_init()
+		_setValidators([][20]byte{v1, v2, v3})
+		m.MockEmitEvent(ValidatorVotedOut, uint32(1), v2[:], uint64(701), uint64(700), _toWei(701).String(), _toWei(700).String())
+
+		// call
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v1: 320, v2: 701, v3: 699}), _toWei(1000))
+
+		// assert
+		m.VerifyMocks()
+		require.ElementsMatch(t, [][20]byte{v1, v3}, elected)
+		require.EqualValues(t, 0, isElectionElectedByFallback(1))
+	})
+}
+
+func TestOrbsVotingContract_processVote_processValidatorsSelection_VoteOutInWei(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		ETHEREUM_STAKE_FACTOR = big.NewInt(1000000000000000000)
+		MIN_ELECTED_VALIDATORS = 2
+		_init()
+		_setValidators([][20]byte{v1, v2, v3})
+		halfOrbs := new(big.Int).Div(ETHEREUM_STAKE_FACTOR, big.NewInt(2))
+		totalVotes := _addWei(_toWei(1000), new(big.Int).Div(new(big.Int).Mul(ETHEREUM_STAKE_FACTOR, big.NewInt(9)), big.NewInt(10)))
+		v2Votes := _addWei(_toWei(700), halfOrbs) // threshold is 70% of 1000.9, 700.63
+
+		// call (an unmocked event would panic)
+		elected := _processValidatorsSelection(map[[20]byte]*big.Int{v2: v2Votes}, totalVotes)
+
+		// assert
+		require.ElementsMatch(t, [][20]byte{v1, v2, v3}, elected, "700.5 is below the threshold although whole ORBS would vote it out")
+		require.Equal(t, v2Votes.String(), getValidatorVoteInWei(v2[:]))
+		require.EqualValues(t, 700, getValidatorVote(v2[:]))
+	})
+}
+
+func TestOrbsVotingContract_processVote_processValidatorsSelection_NoVotedOutEventWhenTooFewLeft(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		MIN_ELECTED_VALIDATORS = 3
+		_init()
+		_setValidators([][20]byte{v1, v2, v3})
+
+		// call (an unmocked event would panic)
+		elected := _processValidatorsSelection(weiStakes(map[[20]byte]uint64{v1: 320, v2: 701, v3: 699}), _toWei(1000))
+
+		// assert
+		require.ElementsMatch(t, [][20]byte{v1, v2, v3}, elected)
+		require.EqualValues(t, 1, isElectionElectedByFallback(1))
+		require.EqualValues(t, v2[:], getFallbackVotedOutValidatorsByIndex(1), "voted out validator is recorded for the fallback reward policy")
+	})
+}
+
+func TestOrbsVotingContract_processVote_nextProcessVotingState_EmitsStageAdvanced(t *testing.T) {
+	electionBlock := uint64(60000)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		_setNumberOfElections(4)
+		_setProcessCurrentElection(0, electionBlock, 0)
+		_setVotingProcessItem(7)
+		m.MockEmitEvent(ProcessingStageAdvanced, uint32(5), electionBlock, VOTING_PROCESS_STATE_GUARDIANS)
+
+		// call
+		_nextProcessVotingState(VOTING_PROCESS_STATE_GUARDIANS)
+
+		// assert
+		m.VerifyMocks()
+		require.EqualValues(t, VOTING_PROCESS_STATE_GUARDIANS, _getVotingProcessState())
+		require.EqualValues(t, 0, _getVotingProcessItem())
+	})
+}
+
+func TestOrbsVotingContract_processVote_processVoteMachine_InvalidValidatorsExcluded(t *testing.T) {
+	h := newHarnessBlockBased()
+	h.electionBlock = uint64(60000)
+	aRecentVoteBlock := h.electionBlock - 1
+
+	var v1, v2, v3 = h.addValidator(), h.addValidator(), h.addValidator()
+	var unregistered = h.addValidator().withIsRegistered(false)
+	var zeroOrbs = h.addValidator().withOrbsAddress([20]byte{})
+	var dup1 = h.addValidator().withOrbsAddress([20]byte{0xff})
+	var dup2 = h.addValidator().withOrbsAddress([20]byte{0xff})
+	var g1 = h.addGuardian(1000)
+	g1.vote(aRecentVoteBlock)
+	d1 := h.addDelegator(500, g1.address)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		h.setupOrbsStateBeforeProcessMachine()
+		h.setupEthereumStateBeforeProcess(m)
+		index := _getProcessCurrentElectionIndex()
+		mockProcessingStagesEvents(m, index, h.electionBlock)
+		m.MockEmitEvent(ValidatorExcluded, index, unregistered.address[:], VALIDATOR_EXCLUSION_NOT_REGISTERED)
+		m.MockEmitEvent(ValidatorExcluded, index, zeroOrbs.address[:], VALIDATOR_EXCLUSION_ZERO_ORBS_ADDRESS)
+		m.MockEmitEvent(ValidatorExcluded, index, dup1.address[:], VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+		m.MockEmitEvent(ValidatorExcluded, index, dup2.address[:], VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, index, map[[20]byte]string{g1.address: "6824", d1.address: "3412"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, g1.address, index, "12795")
+
+		// call
+		elected, _ := h.runProcessVoteMachineNtimes(-1)
+
+		// assert
+		m.VerifyMocks()
+		require.ElementsMatch(t, [][20]byte{v1.address, v2.address, v3.address}, elected)
+		require.Equal(t, "", getValidatorExclusionReasonByIndex(v1.address[:], index))
+		require.Equal(t, VALIDATOR_EXCLUSION_NOT_REGISTERED, getValidatorExclusionReasonByIndex(unregistered.address[:], index))
+		require.Equal(t, VALIDATOR_EXCLUSION_ZERO_ORBS_ADDRESS, getValidatorExclusionReasonByIndex(zeroOrbs.address[:], index))
+		require.Equal(t, VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS, getValidatorExclusionReasonByIndex(dup1.address[:], index))
+		require.Equal(t, VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS, getValidatorExclusionReasonByIndex(dup2.address[:], index))
+	})
+}
+
+func TestOrbsVotingContract_processVote_validateValidatorData_EmitsExcludedOnce(t *testing.T) {
+	v1, v2, v3 := [20]byte{0xc1}, [20]byte{0xc2}, [20]byte{0xc3}
+	sharedOrbsAddress := [20]byte{0xee}
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+		m.MockEmitEvent(ValidatorExcluded, uint32(1), v1[:], VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+		m.MockEmitEvent(ValidatorExcluded, uint32(1), v2[:], VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+		m.MockEmitEvent(ValidatorExcluded, uint32(1), v3[:], VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS)
+
+		// call
+		_validateValidatorData(v1, true, sharedOrbsAddress)
+		_validateValidatorData(v2, true, sharedOrbsAddress)
+		_validateValidatorData(v3, true, sharedOrbsAddress)
+
+		// assert
+		m.VerifyMocks()
+		require.Equal(t, VALIDATOR_EXCLUSION_DUPLICATE_ORBS_ADDRESS, getValidatorExclusionReasonByIndex(v1[:], 1))
+	})
+}
+
+func TestOrbsVotingContract_processVote_processVoteMachine_TopologyFromElectionSnapshot(t *testing.T) {
+	h := newHarnessBlockBased()
+	h.electionBlock = uint64(60000)
+	aRecentVoteBlock := h.electionBlock - 1
+
+	var v1, v2, v3 = h.addValidator(), h.addValidator(), h.addValidator()
+	var g1 = h.addGuardian(1000)
+	g1.vote(aRecentVoteBlock)
+	d1 := h.addDelegator(500, g1.address)
+
+	InServiceScope(nil, nil, func(m Mockery) {
+		_init()
+
+		// prepare
+		h.setupOrbsStateBeforeProcessMachine()
+		h.setupEthereumStateBeforeProcess(m)
+		index := _getProcessCurrentElectionIndex()
+		mockProcessingStagesEvents(m, index, h.electionBlock)
+		mockRewardAssignedEvents(m, REWARD_CATEGORY_PARTICIPATION, index, map[[20]byte]string{g1.address: "6824", d1.address: "3412"})
+		mockRewardAssignedEvent(m, REWARD_CATEGORY_GUARDIAN_EXCELLENCE, g1.address, index, "12795")
+
+		// call
+		elected, _ := h.runProcessVoteMachineNtimes(-1)
+		m.MockEnvBlockHeight(1000)
+		mockElectionCompletedEvent(m, index, h.electionBlock, elected...)
+		_setElectedValidators(elected, h.electionTime, h.electionBlock)
+
+		// assert
+		m.VerifyMocks()
+		electedEthereum := _splitAddresses(getElectedValidatorsEthereumAddressByIndex(index))
+		topology := getElectedValidatorsTopologyByIndex(index)
+		require.Len(t, topology, len(electedEthereum)*TOPOLOGY_ENTRY_LENGTH)
+		byAddress := map[[20]byte]*validator{v1.address: v1, v2.address: v2, v3.address: v3}
+		for i, address := range electedEthereum {
+			entry := topology[i*TOPOLOGY_ENTRY_LENGTH : (i+1)*TOPOLOGY_ENTRY_LENGTH]
+			require.EqualValues(t, byAddress[address].orbsAddress[:], entry[:20], "topology is in committee order")
+			require.EqualValues(t, byAddress[address].ipAddress[:], entry[20:])
+		}
+		require.Equal(t, v2.name, getValidatorNameByIndex(v2.address[:], index))
+		require.Equal(t, v2.website, getValidatorWebsiteByIndex(v2.address[:], index))
+		require.EqualValues(t, v2.orbsAddress[:], getValidatorOrbsAddressByIndex(v2.address[:], index))
 	})
 }
@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"math/big"
 )
 
 /***
@@ -80,7 +81,23 @@ func getValidatorStake(validator []byte) uint64 {
 }
 
 func _setValidatorStake(validator []byte, stake uint64) {
-	state.WriteUint64(_formatValidatorStakeKey(validator), stake)
+	_setValidatorStakeInWei(validator, _toWei(stake))
+}
+
+func _formatValidatorStakeInWeiKey(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_StakeInWei", hex.EncodeToString(validator)))
+}
+
+func getValidatorStakeInWei(validator []byte) string {
+	return _getValidatorStakeInWei(validator).String()
+}
+
+func _getValidatorStakeInWei(validator []byte) *big.Int {
+	return _readWeiOrLegacy(_formatValidatorStakeInWeiKey(validator), _formatValidatorStakeKey(validator))
+}
+
+func _setValidatorStakeInWei(validator []byte, stake *big.Int) {
+	_writeWeiAndLegacy(_formatValidatorStakeInWeiKey(validator), _formatValidatorStakeKey(validator), stake)
 }
 
 func _formatValidatorVoteKey(validator []byte) []byte {
@@ -91,6 +108,14 @@ func getValidatorVote(validator []byte) uint64 {
 	return state.ReadUint64(_formatValidatorVoteKey(validator))
 }
 
-func _setValidatorVote(validator []byte, stake uint64) {
-	state.WriteUint64(_formatValidatorVoteKey(validator), stake)
+func _formatValidatorVoteInWeiKey(validator []byte) []byte {
+	return []byte(fmt.Sprintf("Validator_%s_VoteInWei", hex.EncodeToString(validator)))
+}
+
+func getValidatorVoteInWei(validator []byte) string {
+	return _readWeiOrLegacy(_formatValidatorVoteInWeiKey(validator), _formatValidatorVoteKey(validator)).String()
+}
+
+func _setValidatorVoteInWei(validator []byte, stake *big.Int) {
+	_writeWeiAndLegacy(_formatValidatorVoteInWeiKey(validator), _formatValidatorVoteKey(validator), stake)
 }
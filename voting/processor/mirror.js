@@ -10,6 +10,8 @@ BigInt.prototype.toJSON = function() { return this.toString(); };
 const ethereumConnectionURL = process.env.NETWORK_URL_ON_ETHEREUM;
 const erc20ContractAddress = process.env.ERC20_CONTRACT_ADDRESS;
 const votingContractAddress = process.env.VOTING_CONTRACT_ADDRESS;
+const guardiansCommissionContractAddress = process.env.GUARDIANS_COMMISSION_CONTRACT_ADDRESS;
+const rewardRecipientsContractAddress = process.env.REWARD_RECIPIENTS_CONTRACT_ADDRESS;
 const orbsUrl = process.env.ORBS_URL;
 const orbsVchain = process.env.ORBS_VCHAINID;
 const orbsVotingContractName = process.env.ORBS_VOTING_CONTRACT_NAME;
@@ -24,6 +26,8 @@ let endBlock = 0;
 
 let totalTransfers = 0;
 let totalDelegate = 0;
+let totalCommission = 0;
+let totalRecipient = 0;
 
 const slack = require('./src/slack');
 
@@ -64,7 +68,13 @@ function validateInput() {
     }
 }
 
+const EXPECTED_ERROR_CODES = ["ERR_MIRROR_STALE_ORDERING", "ERR_MIRROR_DELEGATE_OVERRIDES_TRANSFER"];
+
 function isExpectedError(outputArgsJson) {
+    if (EXPECTED_ERROR_CODES.some(code => outputArgsJson.includes(code + "{"))) {
+        return true;
+    }
+    // contracts deployed before error codes were introduced
     return outputArgsJson.includes("failed since current delegation is from block-height") || outputArgsJson.includes("failed since already have delegation with method");
 }
 
@@ -148,6 +158,30 @@ async function delegateEvents(orbs, ethereumConnectionURL, votingContractAddress
     }
 }
 
+async function rewardRecipientEvents(orbs, ethereumConnectionURL, rewardRecipientsContractAddress, startBlock, endBlock) {
+    let events = await require('./src/findRewardRecipientEvents')(ethereumConnectionURL, rewardRecipientsContractAddress, startBlock, endBlock);
+    totalRecipient += events.length;
+    if (verbose) {
+        console.log('\x1b[34m%s\x1b[0m', `Found ${events.length} RewardRecipientSet events`);
+    }
+
+    if (events.length > 0) {
+        await filterAndSendOnlyNewEvents(orbs, events, "mirrorRewardRecipient");
+    }
+}
+
+async function commissionEvents(orbs, ethereumConnectionURL, guardiansCommissionContractAddress, startBlock, endBlock) {
+    let events = await require('./src/findCommissionEvents')(ethereumConnectionURL, guardiansCommissionContractAddress, startBlock, endBlock);
+    totalCommission += events.length;
+    if (verbose) {
+        console.log('\x1b[34m%s\x1b[0m', `Found ${events.length} CommissionUpdated events`);
+    }
+
+    if (events.length > 0) {
+        await filterAndSendOnlyNewEvents(orbs, events, "mirrorGuardianCommission");
+    }
+}
+
 async function iterateOverEvents(orbs, start, end, pace) {
     for (let i = start; i < end; i = i + pace) {
         let minEnd = i + pace < end ? i + pace : end;
@@ -157,6 +191,12 @@ async function iterateOverEvents(orbs, start, end, pace) {
             }
             await transferEvents(orbs, ethereumConnectionURL, erc20ContractAddress, i, minEnd);
             await delegateEvents(orbs, ethereumConnectionURL, votingContractAddress, i, minEnd);
+            if (rewardRecipientsContractAddress) {
+                await rewardRecipientEvents(orbs, ethereumConnectionURL, rewardRecipientsContractAddress, i, minEnd);
+            }
+            if (guardiansCommissionContractAddress) {
+                await commissionEvents(orbs, ethereumConnectionURL, guardiansCommissionContractAddress, i, minEnd);
+            }
         } catch (e) {
             if (verbose) {
                 console.log('\x1b[35m%s\x1b[0m', `too many events, slowing down by factor of 10`, e);
@@ -215,7 +255,7 @@ async function main() {
         let endTime = Date.now();
         console.log('\x1b[35m%s\x1b[0m', `took ${Math.floor((endTime - startTime) / 60000)} minutes, ${((endTime - startTime) % 60000) / 1000.0} seconds.`);
     }
-    console.log('\x1b[35m%s\x1b[0m', `Processed ${totalTransfers} transfer events and  ${totalDelegate} delegate events.`);
+    console.log('\x1b[35m%s\x1b[0m', `Processed ${totalTransfers} transfer events, ${totalDelegate} delegate events, ${totalRecipient} reward recipient events and ${totalCommission} commission events.`);
 }
 
 main()
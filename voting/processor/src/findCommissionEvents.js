@@ -0,0 +1,62 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+const Web3 = require('web3');
+
+const GUARDIANS_COMMISSION_ABI = [{"anonymous":false,"inputs":[{"indexed":true,"name":"guardian","type":"address"},{"indexed":false,"name":"commissionBasisPoints","type":"uint256"}],"name":"CommissionUpdated","type":"event"}];
+
+async function getAllPastCommissionEvents(guardiansCommissionContract, startBlock, endBlock) {
+    let options = {
+        fromBlock: startBlock,
+        toBlock: endBlock
+    };
+
+    let mapOfCommissions = {};
+    let listOfCommissions = [];
+    let events = await guardiansCommissionContract.getPastEvents('CommissionUpdated', options);
+    for (let i = events.length-1; i >= 0;i--) {
+        let event = events[i];
+        let guardianAddress = getAddressFromTopic(event, TOPIC_GUARDIAN_ADDR);
+        let currentCommissionIndex = mapOfCommissions[guardianAddress];
+        if (typeof currentCommissionIndex === 'number' && isObjectNewerThanTx(listOfCommissions[currentCommissionIndex], event) ) {
+            continue;
+        }
+        let obj = generateCommissionObject(event.blockNumber, event.transactionIndex, event.transactionHash, guardianAddress, event.returnValues.commissionBasisPoints);
+
+        if(typeof currentCommissionIndex === 'number') {
+            listOfCommissions[currentCommissionIndex] = obj;
+        } else {
+            mapOfCommissions[guardianAddress] = listOfCommissions.length;
+            listOfCommissions.push(obj);
+        }
+    }
+    return listOfCommissions;
+}
+
+const TOPIC_GUARDIAN_ADDR = 1;
+function getAddressFromTopic(event, i) {
+    let topic = event.raw.topics[i];
+    return '0x' + topic.substring(26)
+}
+
+function isObjectNewerThanTx(latestCommission, event) {
+    return latestCommission.block > event.blockNumber ||
+        (latestCommission.block === event.blockNumber && latestCommission.transactionIndex > event.transactionIndex)
+}
+
+function generateCommissionObject(block, transactionIndex, txHash, guardianAddress, commissionBasisPoints) {
+    return {
+        block, transactionIndex, txHash, guardianAddress, commissionBasisPoints
+    }
+}
+
+module.exports = async function (networkConnectionUrl, guardiansCommissionContractAddress, startBlock, endBlock) {
+    let web3 = await new Web3(new Web3.providers.HttpProvider(networkConnectionUrl));
+    let contract = await new web3.eth.Contract(GUARDIANS_COMMISSION_ABI, guardiansCommissionContractAddress);
+    return await getAllPastCommissionEvents(contract, startBlock, endBlock);
+};
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2019 the orbs-ethereum-contracts authors
+ * This file is part of the orbs-ethereum-contracts library in the Orbs project.
+ *
+ * This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+ * The above notice should be included in all copies or substantial portions of the software.
+ */
+
+const Web3 = require('web3');
+
+const REWARD_RECIPIENTS_ABI = [{"anonymous":false,"inputs":[{"indexed":true,"name":"stakeholder","type":"address"},{"indexed":true,"name":"recipient","type":"address"}],"name":"RewardRecipientSet","type":"event"}];
+
+async function getAllPastRewardRecipientEvents(rewardRecipientsContract, startBlock, endBlock) {
+    let options = {
+        fromBlock: startBlock,
+        toBlock: endBlock
+    };
+
+    let mapOfRecipients = {};
+    let listOfRecipients = [];
+    let events = await rewardRecipientsContract.getPastEvents('RewardRecipientSet', options);
+    for (let i = events.length-1; i >= 0;i--) {
+        let event = events[i];
+        let stakeholderAddress = getAddressFromTopic(event, TOPIC_STAKEHOLDER_ADDR);
+        let currentRecipientIndex = mapOfRecipients[stakeholderAddress];
+        if (typeof currentRecipientIndex === 'number' && isObjectNewerThanTx(listOfRecipients[currentRecipientIndex], event) ) {
+            continue;
+        }
+        let obj = generateRecipientObject(event.blockNumber, event.transactionIndex, event.transactionHash, stakeholderAddress, getAddressFromTopic(event, TOPIC_RECIPIENT_ADDR));
+
+        if(typeof currentRecipientIndex === 'number') {
+            listOfRecipients[currentRecipientIndex] = obj;
+        } else {
+            mapOfRecipients[stakeholderAddress] = listOfRecipients.length;
+            listOfRecipients.push(obj);
+        }
+    }
+    return listOfRecipients;
+}
+
+const TOPIC_STAKEHOLDER_ADDR = 1;
+const TOPIC_RECIPIENT_ADDR = 2;
+function getAddressFromTopic(event, i) {
+    let topic = event.raw.topics[i];
+    return '0x' + topic.substring(26)
+}
+
+function isObjectNewerThanTx(latestRecipient, event) {
+    return latestRecipient.block > event.blockNumber ||
+        (latestRecipient.block === event.blockNumber && latestRecipient.transactionIndex > event.transactionIndex)
+}
+
+function generateRecipientObject(block, transactionIndex, txHash, stakeholderAddress, recipientAddress) {
+    return {
+        block, transactionIndex, txHash, stakeholderAddress, recipientAddress
+    }
+}
+
+module.exports = async function (networkConnectionUrl, rewardRecipientsContractAddress, startBlock, endBlock) {
+    let web3 = await new Web3(new Web3.providers.HttpProvider(networkConnectionUrl));
+    let contract = await new web3.eth.Contract(REWARD_RECIPIENTS_ABI, rewardRecipientsContractAddress);
+    return await getAllPastRewardRecipientEvents(contract, startBlock, endBlock);
+};